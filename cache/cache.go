@@ -2,9 +2,13 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/log"
 	configcat "github.com/configcat/go-sdk/v9"
@@ -13,39 +17,122 @@ import (
 const (
 	keyName     = "key"
 	payloadName = "payload"
+	ttlName     = "expires_at"
 )
 
+// ErrNotFound is returned by the caches when there's no entry under the requested key.
+var ErrNotFound = errors.New("cache item not found")
+
 type ReaderWriter = configcat.ConfigCache
 
 type External interface {
 	ReaderWriter
+	Ping(ctx context.Context) error
 	Shutdown()
 }
 
-func SetupExternalCache(conf *config.CacheConfig, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+func notFound(key string) error {
+	return fmt.Errorf("%w for key '%s'", ErrNotFound, key)
+}
+
+type wrapper interface {
+	unwrap() External
+}
+
+func SetupExternalCache(conf *config.CacheConfig, telemetryReporter telemetry.Reporter, statusReporter status.Reporter, log log.Logger) (External, error) {
+	store, err := setupStore(conf, telemetryReporter, statusReporter, log)
+	if err != nil || store == nil {
+		return store, err
+	}
+	if conf.KeyPrefix != "" {
+		store = newPrefixed(conf.KeyPrefix, store)
+	}
+	if !conf.Encryption.Enabled {
+		return store, nil
+	}
+	encrypted, err := newEncrypted(&conf.Encryption, store, log.WithPrefix("cache"))
+	if err != nil {
+		store.Shutdown()
+		return nil, err
+	}
+	return encrypted, nil
+}
+
+// AsFileBased looks through the layers wrapping the given cache and returns it as FileBased when it stores
+// its entries in files.
+func AsFileBased(c ReaderWriter) (FileBased, bool) {
+	prefix := ""
+	for {
+		if fileBased, ok := c.(FileBased); ok {
+			if prefix != "" {
+				return &prefixedFileBased{FileBased: fileBased, prefix: prefix}, true
+			}
+			return fileBased, true
+		}
+		if prefixed, ok := c.(*prefixedStore); ok {
+			prefix = prefixed.prefix + prefix
+		}
+		w, ok := c.(wrapper)
+		if !ok {
+			return nil, false
+		}
+		c = w.unwrap()
+	}
+}
+
+func setupStore(conf *config.CacheConfig, telemetryReporter telemetry.Reporter, statusReporter status.Reporter, log log.Logger) (External, error) {
 	cacheLog := log.WithPrefix("cache")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second) // give 15 sec to spin up the cache connection
 	defer cancel()
 
-	if conf.Redis.Enabled {
-		redis, err := newRedis(&conf.Redis, telemetryReporter, cacheLog)
-		if err != nil {
-			return nil, err
-		}
-		return redis, nil
-	} else if conf.MongoDb.Enabled {
-		mongoDb, err := newMongoDb(ctx, &conf.MongoDb, telemetryReporter, cacheLog)
-		if err != nil {
-			return nil, err
+	if len(conf.Chain) > 0 {
+		tiers := make([]chainTier, 0, len(conf.Chain))
+		for _, name := range conf.Chain {
+			store, err := setupBackend(ctx, name, conf, telemetryReporter, cacheLog)
+			if err != nil {
+				for _, tier := range tiers {
+					tier.store.Shutdown()
+				}
+				return nil, err
+			}
+			store = newResilient(newInstrumented(store, name, telemetryReporter), conf, status.CacheTier(name), statusReporter, cacheLog.WithPrefix(name))
+			tiers = append(tiers, chainTier{name: name, store: store})
 		}
-		return mongoDb, nil
-	} else if conf.DynamoDb.Enabled {
-		dynamoDb, err := newDynamoDb(ctx, &conf.DynamoDb, telemetryReporter, cacheLog)
-		if err != nil {
-			return nil, err
+		cacheLog.Reportf("using cache chain: %s", strings.Join(conf.Chain, " -> "))
+		return newChain(tiers, statusReporter, cacheLog), nil
+	}
+	for _, name := range config.CacheBackends {
+		if conf.IsBackendEnabled(name) {
+			store, err := setupBackend(ctx, name, conf, telemetryReporter, cacheLog)
+			if err != nil {
+				return nil, err
+			}
+			return newResilient(newInstrumented(store, name, telemetryReporter), conf, status.Cache, statusReporter, cacheLog), nil
 		}
-		return dynamoDb, nil
 	}
 	return nil, nil
 }
+
+func setupBackend(ctx context.Context, name string, conf *config.CacheConfig, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+	ttl := time.Duration(conf.Ttl) * time.Second
+	switch name {
+	case config.CacheRedis:
+		return newRedis(&conf.Redis, ttl, telemetryReporter, log)
+	case config.CacheMongoDb:
+		return newMongoDb(ctx, &conf.MongoDb, ttl, telemetryReporter, log)
+	case config.CacheDynamoDb:
+		return newDynamoDb(ctx, &conf.DynamoDb, ttl, telemetryReporter, log)
+	case config.CacheSql:
+		if ttl > 0 {
+			log.Warnf("the SQL cache doesn't support TTL, its entries won't expire")
+		}
+		return newSql(ctx, &conf.Sql, telemetryReporter, log)
+	case config.CacheFilesystem:
+		if ttl > 0 {
+			log.Warnf("the filesystem cache doesn't support TTL, its entries won't expire")
+		}
+		return newFilesystem(&conf.Filesystem, log)
+	}
+	return nil, fmt.Errorf("unknown cache backend '%s'", name)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/stretchr/testify/assert"
@@ -20,10 +21,11 @@ func TestSetupExternalCache_OnlyOneSelected(t *testing.T) {
 			Database:   "test_db",
 			Collection: "coll",
 		},
-	}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	defer store.Shutdown()
-	assert.IsType(t, &redisStore{}, store)
+	assert.IsType(t, &instrumentedStore{}, store)
+	assert.IsType(t, &redisStore{}, store.(*instrumentedStore).External)
 }
 
 func (s *mongoTestSuite) TestSetupExternalCache() {
@@ -32,24 +34,27 @@ func (s *mongoTestSuite) TestSetupExternalCache() {
 		Url:        s.addr,
 		Database:   "test_db",
 		Collection: "coll",
-	}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	defer store.Shutdown()
-	assert.IsType(s.T(), &mongoDbStore{}, store)
+	assert.IsType(s.T(), &instrumentedStore{}, store)
+	assert.IsType(s.T(), &mongoDbStore{}, store.(*instrumentedStore).External)
 }
 
 func (s *redisTestSuite) TestSetupExternalCache() {
-	store, err := SetupExternalCache(&config.CacheConfig{Redis: config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}, Enabled: true}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	store, err := SetupExternalCache(&config.CacheConfig{Redis: config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}, Enabled: true}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	defer store.Shutdown()
-	assert.IsType(s.T(), &redisStore{}, store)
+	assert.IsType(s.T(), &instrumentedStore{}, store)
+	assert.IsType(s.T(), &redisStore{}, store.(*instrumentedStore).External)
 }
 
 func (s *valkeyTestSuite) TestSetupExternalCache() {
-	store, err := SetupExternalCache(&config.CacheConfig{Redis: config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}, Enabled: true}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	store, err := SetupExternalCache(&config.CacheConfig{Redis: config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}, Enabled: true}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	defer store.Shutdown()
-	assert.IsType(s.T(), &redisStore{}, store)
+	assert.IsType(s.T(), &instrumentedStore{}, store)
+	assert.IsType(s.T(), &redisStore{}, store.(*instrumentedStore).External)
 }
 
 func (s *dynamoDbTestSuite) TestSetupExternalCache() {
@@ -57,8 +62,9 @@ func (s *dynamoDbTestSuite) TestSetupExternalCache() {
 		Enabled: true,
 		Table:   tableName,
 		Url:     s.addr,
-	}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	defer store.Shutdown()
-	assert.IsType(s.T(), &dynamoDbStore{}, store)
+	assert.IsType(s.T(), &instrumentedStore{}, store)
+	assert.IsType(s.T(), &dynamoDbStore{}, store.(*instrumentedStore).External)
 }
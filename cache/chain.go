@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/log"
+)
+
+type chainTier struct {
+	name  string
+	store External
+}
+
+// chainStore reads from its tiers in order and falls back to the next one when a tier fails,
+// while writes are fanned out to every tier.
+type chainStore struct {
+	tiers          []chainTier
+	statusReporter status.Reporter
+	log            log.Logger
+}
+
+func newChain(tiers []chainTier, statusReporter status.Reporter, log log.Logger) External {
+	return &chainStore{tiers: tiers, statusReporter: statusReporter, log: log}
+}
+
+func (c *chainStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var errs []error
+	for _, tier := range c.tiers {
+		data, err := tier.store.Get(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			// a miss means the tier is reachable, it just doesn't have the entry
+			c.statusReporter.ReportOk(status.CacheTier(tier.name), "cache read succeeded, entry not found")
+			errs = append(errs, err)
+			continue
+		}
+		if err != nil {
+			c.log.Debugf("reading from %s failed, falling back to the next tier: %s", tier.name, err)
+			c.statusReporter.ReportError(status.CacheTier(tier.name), "cache read failed")
+			errs = append(errs, err)
+			continue
+		}
+		c.statusReporter.ReportOk(status.CacheTier(tier.name), "cache read succeeded")
+		return data, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Set writes the value into each tier concurrently. It fails only when none of the tiers could be written.
+func (c *chainStore) Set(ctx context.Context, key string, value []byte) error {
+	errs := make([]error, len(c.tiers))
+	var wg sync.WaitGroup
+	for i, tier := range c.tiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := tier.store.Set(ctx, key, value); err != nil {
+				c.log.Errorf("writing to %s failed: %s", tier.name, err)
+				c.statusReporter.ReportError(status.CacheTier(tier.name), "cache write failed")
+				errs[i] = err
+				return
+			}
+			c.statusReporter.ReportOk(status.CacheTier(tier.name), "cache write succeeded")
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Ping checks each tier, and fails only when none of them are reachable.
+func (c *chainStore) Ping(ctx context.Context) error {
+	var errs []error
+	for _, tier := range c.tiers {
+		if err := tier.store.Ping(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(c.tiers) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func (c *chainStore) Shutdown() {
+	for _, tier := range c.tiers {
+		tier.store.Shutdown()
+	}
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainStore(t *testing.T) {
+	s := miniredis.RunT(t)
+	conf := &config.CacheConfig{
+		Chain:      []string{config.CacheRedis, config.CacheFilesystem},
+		Redis:      config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}},
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()},
+	}
+	reporter := status.NewReporter(conf)
+	store, err := SetupExternalCache(conf, telemetry.NewEmptyReporter(), reporter, log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+	assert.IsType(t, &chainStore{}, store)
+
+	cacheEntry := configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test`))
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	redisVal, err := s.Get("k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, []byte(redisVal))
+	fileBased, ok := AsFileBased(store.(*chainStore).tiers[1].store)
+	require.True(t, ok)
+	fileVal, err := os.ReadFile(fileBased.EntryPath("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, fileVal)
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+
+	tiers := reporter.GetStatus().Cache.Tiers
+	assert.Equal(t, 2, len(tiers))
+	assert.Equal(t, config.CacheRedis, tiers[0].Name)
+	assert.Equal(t, status.Healthy, tiers[0].Status)
+	assert.Equal(t, config.CacheFilesystem, tiers[1].Name)
+	assert.Equal(t, status.Healthy, tiers[1].Status)
+
+	s.Close()
+
+	res, err = store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+
+	cacheEntry = configcatcache.CacheSegmentsToBytes(time.Now(), "etag2", []byte(`test2`))
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	res, err = store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+
+	tiers = reporter.GetStatus().Cache.Tiers
+	assert.Equal(t, status.Degraded, tiers[0].Status)
+	assert.Contains(t, tiers[0].Records[len(tiers[0].Records)-1], "[error] cache read failed")
+	assert.Equal(t, status.Healthy, tiers[1].Status)
+}
+
+func TestChainStore_Miss(t *testing.T) {
+	s := miniredis.RunT(t)
+	conf := &config.CacheConfig{
+		Chain:      []string{config.CacheRedis, config.CacheFilesystem},
+		Redis:      config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}},
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()},
+	}
+	reporter := status.NewReporter(conf)
+	store, err := SetupExternalCache(conf, telemetry.NewEmptyReporter(), reporter, log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	for range 3 {
+		_, err = store.Get(t.Context(), "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+
+	tiers := reporter.GetStatus().Cache.Tiers
+	assert.Equal(t, status.Healthy, tiers[0].Status)
+	assert.Equal(t, status.Healthy, tiers[1].Status)
+	assert.Contains(t, tiers[0].Records[len(tiers[0].Records)-1], "[ok] cache read succeeded, entry not found")
+}
+
+func TestChainStore_AllFail(t *testing.T) {
+	s := miniredis.RunT(t)
+	dir := t.TempDir()
+	conf := &config.CacheConfig{
+		Chain:      []string{config.CacheRedis, config.CacheFilesystem},
+		Redis:      config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}},
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: dir},
+	}
+	store, err := SetupExternalCache(conf, telemetry.NewEmptyReporter(), status.NewReporter(conf), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	_, err = store.Get(t.Context(), "k1")
+	assert.ErrorContains(t, err, "cache item not found for key 'k1'")
+
+	s.Close()
+	_ = os.RemoveAll(dir)
+
+	err = store.Set(t.Context(), "k1", []byte("test"))
+	assert.Error(t, err)
+}
+
+func TestSetupExternalCache_Chain_Invalid(t *testing.T) {
+	conf := &config.CacheConfig{
+		Chain:      []string{config.CacheFilesystem, config.CacheSql},
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()},
+		Sql:        config.SqlConfig{Enabled: true, Driver: "unknown"},
+	}
+	_, err := SetupExternalCache(conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -16,10 +18,11 @@ import (
 type dynamoDbStore struct {
 	dynamoDb *dynamodb.Client
 	table    *string
+	ttl      time.Duration
 	log      log.Logger
 }
 
-func newDynamoDb(ctx context.Context, conf *config.DynamoDbConfig, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+func newDynamoDb(ctx context.Context, conf *config.DynamoDbConfig, ttl time.Duration, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
 	dynamoLog := log.WithPrefix("dynamodb")
 	awsCtx, err := awsconfig.LoadDefaultConfig(ctx)
 	telemetryReporter.InstrumentAws(&awsCtx)
@@ -33,10 +36,14 @@ func newDynamoDb(ctx context.Context, conf *config.DynamoDbConfig, telemetryRepo
 			options.BaseEndpoint = aws.String(conf.Url)
 		})
 	}
+	if ttl > 0 {
+		dynamoLog.Reportf("entries are written with a TTL in the '%s' attribute, enable Time to Live on it for the '%s' table to have them removed", ttlName, conf.Table)
+	}
 	log.Reportf("using DynamoDB for cache storage")
 	return &dynamoDbStore{
 		dynamoDb: dynamodb.NewFromConfig(awsCtx, opts...),
 		table:    aws.String(conf.Table),
+		ttl:      ttl,
 	}, nil
 }
 
@@ -51,6 +58,12 @@ func (d *dynamoDbStore) Get(ctx context.Context, key string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	// DynamoDB deletes expired items only eventually
+	if expiresAt, ok := res.Item[ttlName].(*types.AttributeValueMemberN); ok {
+		if sec, err := strconv.ParseInt(expiresAt.Value, 10, 64); err == nil && time.Unix(sec, 0).Before(time.Now()) {
+			return nil, notFound(key)
+		}
+	}
 	if payload, ok := res.Item[payloadName]; ok {
 		switch v := payload.(type) {
 		case *types.AttributeValueMemberB:
@@ -59,20 +72,29 @@ func (d *dynamoDbStore) Get(ctx context.Context, key string) ([]byte, error) {
 			return nil, fmt.Errorf("invalid item under key '%s'", key)
 		}
 	}
-	return nil, fmt.Errorf("cache item not found for key '%s'", key)
+	return nil, notFound(key)
 }
 
 func (d *dynamoDbStore) Set(ctx context.Context, key string, value []byte) error {
+	item := map[string]types.AttributeValue{
+		keyName:     &types.AttributeValueMemberS{Value: key},
+		payloadName: &types.AttributeValueMemberB{Value: value},
+	}
+	if d.ttl > 0 {
+		item[ttlName] = &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Add(d.ttl).Unix(), 10)}
+	}
 	_, err := d.dynamoDb.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: d.table,
-		Item: map[string]types.AttributeValue{
-			keyName:     &types.AttributeValueMemberS{Value: key},
-			payloadName: &types.AttributeValueMemberB{Value: value},
-		},
+		Item:      item,
 	})
 	return err
 }
 
+func (d *dynamoDbStore) Ping(ctx context.Context) error {
+	_, err := d.dynamoDb.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: d.table})
+	return err
+}
+
 func (d *dynamoDbStore) Shutdown() {
 	// nothing to do
 }
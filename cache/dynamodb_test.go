@@ -64,7 +64,7 @@ func (s *dynamoDbTestSuite) TestDynamoDbStore() {
 			Enabled: true,
 			Table:   tableName,
 			Url:     s.addr,
-		}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+		}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 		assert.NoError(s.T(), err)
 		defer store.Shutdown()
 
@@ -92,7 +92,7 @@ func (s *dynamoDbTestSuite) TestDynamoDbStore() {
 			Enabled: true,
 			Table:   tableName,
 			Url:     s.addr,
-		}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+		}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 		assert.NoError(s.T(), err)
 		defer store.Shutdown()
 
@@ -100,12 +100,34 @@ func (s *dynamoDbTestSuite) TestDynamoDbStore() {
 		assert.Error(s.T(), err)
 	})
 
+	s.Run("ttl", func() {
+		store, err := newDynamoDb(s.T().Context(), &config.DynamoDbConfig{
+			Enabled: true,
+			Table:   tableName,
+			Url:     s.addr,
+		}, 1*time.Second, telemetry.NewEmptyReporter(), log.NewNullLogger())
+		assert.NoError(s.T(), err)
+		defer store.Shutdown()
+
+		err = store.Set(s.T().Context(), "k4", []byte("test"))
+		assert.NoError(s.T(), err)
+
+		res, err := store.Get(s.T().Context(), "k4")
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), []byte("test"), res)
+
+		time.Sleep(2 * time.Second)
+
+		_, err = store.Get(s.T().Context(), "k4")
+		assert.ErrorContains(s.T(), err, "cache item not found for key 'k4'")
+	})
+
 	s.Run("no-table", func() {
 		store, err := newDynamoDb(s.T().Context(), &config.DynamoDbConfig{
 			Enabled: true,
 			Table:   "nonexisting",
 			Url:     s.addr,
-		}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+		}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 		assert.NoError(s.T(), err)
 		defer store.Shutdown()
 
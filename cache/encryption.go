@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/log"
+)
+
+const (
+	encryptionVersion = 1
+	dataKeySize       = 32
+)
+
+// encryptionMagic marks encrypted entries. It starts with a zero byte, which can't be the first byte of a plain
+// cache entry, so entries written before enabling encryption can be told apart from the encrypted ones.
+var encryptionMagic = []byte{0, 'c', 'c', 'e'}
+
+// encryptedStore wraps an External cache with envelope encryption. Each entry is encrypted with a random data key
+// using AES-GCM, and the data key is encrypted with the active key loaded from the key file. Entries are tagged with
+// the id of the key that protects them, so keys can be rotated without invalidating the existing entries.
+//
+// Entry layout: magic | version | key id length | key id | encrypted data key | encrypted payload
+type encryptedStore struct {
+	External
+	keys           map[string]cipher.AEAD
+	activeKeyId    string
+	allowPlaintext bool
+	log            log.Logger
+}
+
+func newEncrypted(conf *config.EncryptionConfig, inner External, log log.Logger) (External, error) {
+	keys, ids, err := loadEncryptionKeys(conf.KeyFile)
+	if err != nil {
+		log.Errorf("couldn't load encryption keys from %s: %s", conf.KeyFile, err)
+		return nil, err
+	}
+	activeKeyId := conf.ActiveKeyId
+	if activeKeyId == "" {
+		activeKeyId = ids[0]
+	}
+	if _, ok := keys[activeKeyId]; !ok {
+		err = fmt.Errorf("active key '%s' not found in %s", activeKeyId, conf.KeyFile)
+		log.Errorf("%s", err)
+		return nil, err
+	}
+	log.Reportf("cache encryption enabled with key '%s'", activeKeyId)
+	if conf.AllowPlaintextRead {
+		log.Warnf("reading unencrypted cache entries is allowed, disable 'allow_plaintext_read' once every entry is re-written encrypted")
+	}
+	return &encryptedStore{External: inner, keys: keys, activeKeyId: activeKeyId, allowPlaintext: conf.AllowPlaintextRead, log: log}, nil
+}
+
+func (e *encryptedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := e.External.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, encryptionMagic) {
+		if !e.allowPlaintext {
+			return nil, fmt.Errorf("cache entry for key '%s' is not encrypted", key)
+		}
+		e.log.Warnf("cache entry for key '%s' is not encrypted, returning it as is", key)
+		return data, nil
+	}
+	return e.decrypt(key, data)
+}
+
+func (e *encryptedStore) Set(ctx context.Context, key string, value []byte) error {
+	data, err := e.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	return e.External.Set(ctx, key, data)
+}
+
+func (e *encryptedStore) unwrap() External {
+	return e.External
+}
+
+func (e *encryptedStore) encrypt(key string, value []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	dataAead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, len(encryptionMagic)+2+len(e.activeKeyId))
+	header = append(header, encryptionMagic...)
+	header = append(header, encryptionVersion, byte(len(e.activeKeyId)))
+	header = append(header, e.activeKeyId...)
+
+	result := seal(e.keys[e.activeKeyId], bytes.Clone(header), dataKey, header)
+	return seal(dataAead, result, value, append(header, key...)), nil
+}
+
+func (e *encryptedStore) decrypt(key string, data []byte) ([]byte, error) {
+	prefixLen := len(encryptionMagic) + 2
+	if len(data) < prefixLen {
+		return nil, fmt.Errorf("invalid encrypted cache entry for key '%s'", key)
+	}
+	if data[len(encryptionMagic)] != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encryption version %d for key '%s'", data[len(encryptionMagic)], key)
+	}
+	headerLen := prefixLen + int(data[len(encryptionMagic)+1])
+	if len(data) < headerLen {
+		return nil, fmt.Errorf("invalid encrypted cache entry for key '%s'", key)
+	}
+	header := data[:headerLen]
+	keyId := string(data[prefixLen:headerLen])
+	keyAead, ok := e.keys[keyId]
+	if !ok {
+		return nil, fmt.Errorf("cache entry for key '%s' is encrypted with unknown key '%s'", key, keyId)
+	}
+	wrappedLen := keyAead.NonceSize() + dataKeySize + keyAead.Overhead()
+	if len(data) < headerLen+wrappedLen {
+		return nil, fmt.Errorf("invalid encrypted cache entry for key '%s'", key)
+	}
+	dataKey, err := open(keyAead, data[headerLen:headerLen+wrappedLen], header)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decrypt the data key of cache entry '%s': %s", key, err)
+	}
+	dataAead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	value, err := open(dataAead, data[headerLen+wrappedLen:], append(bytes.Clone(header), key...))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decrypt cache entry '%s': %s", key, err)
+	}
+	return value, nil
+}
+
+func seal(aead cipher.AEAD, dst []byte, plaintext []byte, additionalData []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	_, _ = rand.Read(nonce)
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, additionalData)
+}
+
+func open(aead cipher.AEAD, data []byte, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], additionalData)
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadEncryptionKeys reads the keys from a file that contains one '<key id>:<base64 encoded AES key>' entry per line.
+// Empty lines and lines starting with '#' are ignored. It returns the keys by their id and the ids in file order.
+func loadEncryptionKeys(path string) (map[string]cipher.AEAD, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	keys := make(map[string]cipher.AEAD)
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(line, ":")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" || len(id) > 255 {
+			return nil, nil, fmt.Errorf("line %d: invalid key entry, it must be in '<key id>:<base64 encoded key>' format", lineNum)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, nil, fmt.Errorf("line %d: duplicate key id '%s'", lineNum, id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: key '%s' is not valid base64: %s", lineNum, id, err)
+		}
+		aead, err := newAead(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: key '%s' must be 16, 24, or 32 bytes long", lineNum, id)
+		}
+		keys[id] = aead
+		ids = append(ids, id)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("no keys found")
+	}
+	return keys, ids, nil
+}
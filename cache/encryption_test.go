@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStore(t *testing.T) {
+	inner, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	store, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1", "k2")}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	cacheEntry := configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`{"f":{"flag":{"v":{"b":true}}}}`))
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	raw, err := inner.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), string(encryptionMagic)+"\x01\x02k1"))
+	assert.NotContains(t, string(raw), "flag")
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+}
+
+func TestEncryptedStore_Rotation(t *testing.T) {
+	inner, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	keyFile := writeKeyFile(t, "k1", "k2")
+	oldStore, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: keyFile}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+	newStore, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: keyFile, ActiveKeyId: "k2"}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+
+	err = oldStore.Set(t.Context(), "old", []byte("old value"))
+	assert.NoError(t, err)
+	err = newStore.Set(t.Context(), "new", []byte("new value"))
+	assert.NoError(t, err)
+
+	res, err := newStore.Get(t.Context(), "old")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("old value"), res)
+	res, err = oldStore.Get(t.Context(), "new")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new value"), res)
+
+	otherStore, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k3")}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+	_, err = otherStore.Get(t.Context(), "new")
+	assert.ErrorContains(t, err, "cache entry for key 'new' is encrypted with unknown key 'k2'")
+}
+
+func TestEncryptedStore_Plain(t *testing.T) {
+	inner, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	store, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1")}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+
+	err = inner.Set(t.Context(), "k1", []byte("plain"))
+	assert.NoError(t, err)
+
+	_, err = store.Get(t.Context(), "k1")
+	assert.ErrorContains(t, err, "cache entry for key 'k1' is not encrypted")
+
+	_, err = store.Get(t.Context(), "k2")
+	assert.ErrorContains(t, err, "cache item not found for key 'k2'")
+}
+
+func TestEncryptedStore_AllowPlaintextRead(t *testing.T) {
+	inner, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	store, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1"), AllowPlaintextRead: true}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+
+	err = inner.Set(t.Context(), "k1", []byte("plain"))
+	assert.NoError(t, err)
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("plain"), res)
+
+	err = store.Set(t.Context(), "k1", []byte("plain"))
+	assert.NoError(t, err)
+	raw, err := inner.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), string(encryptionMagic)))
+
+	_, err = store.Get(t.Context(), "k2")
+	assert.ErrorContains(t, err, "cache item not found for key 'k2'")
+}
+
+func TestEncryptedStore_Tampered(t *testing.T) {
+	inner, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	store, err := newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1")}, inner, log.NewNullLogger())
+	require.NoError(t, err)
+
+	err = store.Set(t.Context(), "k1", []byte("value"))
+	assert.NoError(t, err)
+	raw, _ := inner.Get(t.Context(), "k1")
+
+	t.Run("moved to other key", func(t *testing.T) {
+		_ = inner.Set(t.Context(), "k2", raw)
+		_, err = store.Get(t.Context(), "k2")
+		assert.ErrorContains(t, err, "couldn't decrypt cache entry 'k2'")
+	})
+	t.Run("modified", func(t *testing.T) {
+		modified := []byte(string(raw))
+		modified[len(modified)-1] ^= 1
+		_ = inner.Set(t.Context(), "k1", modified)
+		_, err = store.Get(t.Context(), "k1")
+		assert.ErrorContains(t, err, "couldn't decrypt cache entry 'k1'")
+	})
+	t.Run("truncated", func(t *testing.T) {
+		_ = inner.Set(t.Context(), "k1", raw[:10])
+		_, err = store.Get(t.Context(), "k1")
+		assert.ErrorContains(t, err, "invalid encrypted cache entry for key 'k1'")
+	})
+}
+
+func TestEncryptedStore_Invalid(t *testing.T) {
+	inner, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"empty", "# comment\n\n", "no keys found"},
+		{"missing id", ":" + base64.StdEncoding.EncodeToString(make([]byte, 32)), "line 1: invalid key entry"},
+		{"invalid base64", "k1:???", "line 1: key 'k1' is not valid base64"},
+		{"invalid length", "k1:" + base64.StdEncoding.EncodeToString(make([]byte, 10)), "line 1: key 'k1' must be 16, 24, or 32 bytes long"},
+		{"duplicate", "k1:" + base64.StdEncoding.EncodeToString(make([]byte, 16)) + "\nk1:" + base64.StdEncoding.EncodeToString(make([]byte, 16)), "line 2: duplicate key id 'k1'"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keyFile := filepath.Join(t.TempDir(), "keys")
+			_ = os.WriteFile(keyFile, []byte(test.content), 0600)
+			_, err = newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: keyFile}, inner, log.NewNullLogger())
+			assert.ErrorContains(t, err, test.err)
+		})
+	}
+	t.Run("missing file", func(t *testing.T) {
+		_, err = newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: filepath.Join(t.TempDir(), "keys")}, inner, log.NewNullLogger())
+		assert.Error(t, err)
+	})
+	t.Run("unknown active key", func(t *testing.T) {
+		_, err = newEncrypted(&config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1"), ActiveKeyId: "k2"}, inner, log.NewNullLogger())
+		assert.ErrorContains(t, err, "active key 'k2' not found")
+	})
+}
+
+func TestSetupExternalCache_Encryption(t *testing.T) {
+	store, err := SetupExternalCache(&config.CacheConfig{
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()},
+		Encryption: config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1")},
+	}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+	assert.IsType(t, &encryptedStore{}, store)
+
+	fileBased, ok := AsFileBased(store)
+	assert.True(t, ok)
+	assert.IsType(t, &filesystemStore{}, fileBased)
+
+	_, err = SetupExternalCache(&config.CacheConfig{
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()},
+		Encryption: config.EncryptionConfig{Enabled: true, KeyFile: filepath.Join(t.TempDir(), "keys")},
+	}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	assert.Error(t, err)
+}
+
+func writeKeyFile(t *testing.T, ids ...string) string {
+	var content strings.Builder
+	content.WriteString("# test keys\n")
+	for _, id := range ids {
+		key := make([]byte, 32)
+		_, _ = rand.Read(key)
+		content.WriteString(id + ":" + base64.StdEncoding.EncodeToString(key) + "\n")
+	}
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(keyFile, []byte(content.String()), 0600))
+	return keyFile
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/log"
+)
+
+const (
+	fsEntryExt = ".cache"
+	fsLockExt  = ".lock"
+)
+
+// FileBased is implemented by caches that store each entry in a separate file, so changes can be watched.
+type FileBased interface {
+	EntryPath(key string) string
+}
+
+type filesystemStore struct {
+	dir string
+	log log.Logger
+}
+
+func newFilesystem(conf *config.FilesystemConfig, log log.Logger) (External, error) {
+	store, err := newFilesystemStore(conf.Path, log)
+	if err != nil {
+		return nil, err
+	}
+	log.Reportf("using the filesystem (%s) for cache storage", store.dir)
+	return store, nil
+}
+
+// NewLocalSnapshot returns a filesystem store used to keep the last-known-good configs on the local disk.
+func NewLocalSnapshot(conf *config.LocalSnapshotConfig, log log.Logger) (ReaderWriter, error) {
+	store, err := newFilesystemStore(conf.Path, log)
+	if err != nil {
+		return nil, err
+	}
+	log.Reportf("keeping local snapshots of the last-known-good configs in %s", store.dir)
+	return store, nil
+}
+
+func newFilesystemStore(path string, log log.Logger) (*filesystemStore, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		log.Errorf("invalid directory path %s: %s", path, err)
+		return nil, err
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		log.Errorf("couldn't create the directory %s: %s", dir, err)
+		return nil, err
+	}
+	return &filesystemStore{dir: dir, log: log}, nil
+}
+
+func (f *filesystemStore) EntryPath(key string) string {
+	return filepath.Join(f.dir, url.QueryEscape(key)+fsEntryExt)
+}
+
+func (f *filesystemStore) Get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(f.EntryPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, notFound(key)
+	}
+	return data, err
+}
+
+// Set writes the value into a temp file first then renames it to the entry's path, so readers
+// never see a partially written entry. Concurrent writers of the same key are serialized with a lock file.
+func (f *filesystemStore) Set(_ context.Context, key string, value []byte) error {
+	entryPath := f.EntryPath(key)
+	lock, err := os.OpenFile(filepath.Join(f.dir, url.QueryEscape(key)+fsLockExt), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = lock.Close()
+	}()
+	if err = lockFile(lock); err != nil {
+		return fmt.Errorf("couldn't lock cache entry '%s': %s", key, err)
+	}
+	defer func() {
+		_ = unlockFile(lock)
+	}()
+
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(value)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, entryPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+func (f *filesystemStore) Ping(_ context.Context) error {
+	info, err := os.Stat(f.dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", f.dir)
+	}
+	return nil
+}
+
+func (f *filesystemStore) Shutdown() {
+	f.log.Reportf("shutdown complete")
+}
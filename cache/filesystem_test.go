@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	store, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	cacheEntry := configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test`))
+
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+
+	cacheEntry = configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test2`))
+
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	res, err = store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+}
+
+func TestFilesystemStore_Empty(t *testing.T) {
+	store, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	_, err = store.Get(t.Context(), "k2")
+	assert.ErrorContains(t, err, "cache item not found for key 'k2'")
+}
+
+func TestFilesystemStore_EntryPath(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: dir}, log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	path := store.(FileBased).EntryPath("configcat-proxy-profile-a/b:c")
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.Equal(t, "configcat-proxy-profile-a%2Fb%3Ac.cache", filepath.Base(path))
+
+	err = store.Set(t.Context(), "configcat-proxy-profile-a/b:c", []byte("test"))
+	assert.NoError(t, err)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", string(data))
+}
+
+func TestFilesystemStore_ConcurrentWrites(t *testing.T) {
+	dir := t.TempDir()
+	store1, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: dir}, log.NewNullLogger())
+	require.NoError(t, err)
+	store2, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: dir}, log.NewNullLogger())
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store1.Set(t.Context(), "k1", []byte("value-from-store1")))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store2.Set(t.Context(), "k1", []byte("value-from-store2")))
+		}()
+	}
+	wg.Wait()
+
+	res, err := store1.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Contains(t, []string{"value-from-store1", "value-from-store2"}, string(res))
+
+	entries, _ := filepath.Glob(filepath.Join(dir, ".tmp-*"))
+	assert.Empty(t, entries)
+}
+
+func TestFilesystemStore_Invalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	_ = os.WriteFile(file, []byte{}, 0644)
+	_, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: file}, log.NewNullLogger())
+	assert.Error(t, err)
+}
+
+func TestSetupExternalCache_Filesystem(t *testing.T) {
+	store, err := SetupExternalCache(&config.CacheConfig{Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+	assert.IsType(t, &instrumentedStore{}, store)
+	assert.IsType(t, &filesystemStore{}, store.(*instrumentedStore).External)
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+)
+
+// instrumentedStore records the latency and the failures of each operation of a cache backend.
+type instrumentedStore struct {
+	External
+	backend           string
+	telemetryReporter telemetry.Reporter
+}
+
+func newInstrumented(inner External, backend string, telemetryReporter telemetry.Reporter) External {
+	return &instrumentedStore{External: inner, backend: backend, telemetryReporter: telemetryReporter}
+}
+
+func (i *instrumentedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	start := time.Now()
+	b, err := i.External.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		// a missing entry is an expected outcome, not a cache failure
+		i.telemetryReporter.RecordCacheOperation(i.backend, telemetry.CacheGet, time.Since(start), nil)
+	} else {
+		i.telemetryReporter.RecordCacheOperation(i.backend, telemetry.CacheGet, time.Since(start), err)
+	}
+	return b, err
+}
+
+func (i *instrumentedStore) Set(ctx context.Context, key string, value []byte) error {
+	start := time.Now()
+	err := i.External.Set(ctx, key, value)
+	i.telemetryReporter.RecordCacheOperation(i.backend, telemetry.CacheSet, time.Since(start), err)
+	return err
+}
+
+func (i *instrumentedStore) unwrap() External {
+	return i.External
+}
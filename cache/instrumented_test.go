@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cacheOperation struct {
+	backend   string
+	operation string
+	err       error
+}
+
+type operationRecorder struct {
+	telemetry.Reporter
+
+	operations []cacheOperation
+}
+
+func (o *operationRecorder) RecordCacheOperation(backend string, operation string, _ time.Duration, err error) {
+	o.operations = append(o.operations, cacheOperation{backend: backend, operation: operation, err: err})
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	fs, err := newFilesystem(&config.FilesystemConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+	recorder := &operationRecorder{}
+	store := newInstrumented(fs, config.CacheFilesystem, recorder)
+	defer store.Shutdown()
+
+	_, err = store.Get(t.Context(), "k1")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, store.Set(t.Context(), "k1", []byte("test")))
+	val, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test"), val)
+
+	assert.Equal(t, []cacheOperation{
+		{backend: config.CacheFilesystem, operation: telemetry.CacheGet},
+		{backend: config.CacheFilesystem, operation: telemetry.CacheSet},
+		{backend: config.CacheFilesystem, operation: telemetry.CacheGet},
+	}, recorder.operations)
+
+	fileBased, ok := AsFileBased(store)
+	assert.True(t, ok)
+	assert.IsType(t, &filesystemStore{}, fileBased)
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/configcat/configcat-proxy/config"
@@ -15,15 +16,17 @@ import (
 type mongoDbStore struct {
 	mongoDb    *mongo.Client
 	collection *mongo.Collection
+	ttl        time.Duration
 	log        log.Logger
 }
 
 type entry struct {
-	Key     string
-	Payload []byte
+	Key       string
+	Payload   []byte
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
 }
 
-func newMongoDb(ctx context.Context, conf *config.MongoDbConfig, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+func newMongoDb(ctx context.Context, conf *config.MongoDbConfig, ttl time.Duration, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
 	opts := options.Client().ApplyURI(conf.Url)
 	telemetryReporter.InstrumentMongoDb(opts)
 	if conf.Tls.Enabled {
@@ -48,10 +51,21 @@ func newMongoDb(ctx context.Context, conf *config.MongoDbConfig, telemetryReport
 		log.Errorf("couldn't create the 'key' index in the '%s' MongoDB collection: %s", conf.Collection, err)
 		return nil, err
 	}
+	if ttl > 0 {
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.M{ttlName: 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		if err != nil {
+			log.Errorf("couldn't create the '%s' TTL index in the '%s' MongoDB collection: %s", ttlName, conf.Collection, err)
+			return nil, err
+		}
+	}
 	log.Reportf("using MongoDB for cache storage")
 	return &mongoDbStore{
 		mongoDb:    client,
 		collection: collection,
+		ttl:        ttl,
 		log:        log,
 	}, nil
 }
@@ -59,14 +73,33 @@ func newMongoDb(ctx context.Context, conf *config.MongoDbConfig, telemetryReport
 func (m *mongoDbStore) Get(ctx context.Context, key string) ([]byte, error) {
 	var result entry
 	err := m.collection.FindOne(ctx, bson.M{keyName: key}).Decode(&result)
-	return result.Payload, err
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, notFound(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the TTL monitor removes expired documents only periodically
+	if result.ExpiresAt != nil && result.ExpiresAt.Before(time.Now()) {
+		return nil, notFound(key)
+	}
+	return result.Payload, nil
 }
 
 func (m *mongoDbStore) Set(ctx context.Context, key string, value []byte) error {
-	_, err := m.collection.ReplaceOne(ctx, bson.M{keyName: key}, entry{Key: key, Payload: value}, options.Replace().SetUpsert(true))
+	e := entry{Key: key, Payload: value}
+	if m.ttl > 0 {
+		expiresAt := time.Now().Add(m.ttl)
+		e.ExpiresAt = &expiresAt
+	}
+	_, err := m.collection.ReplaceOne(ctx, bson.M{keyName: key}, e, options.Replace().SetUpsert(true))
 	return err
 }
 
+func (m *mongoDbStore) Ping(ctx context.Context) error {
+	return m.mongoDb.Ping(ctx, nil)
+}
+
 func (m *mongoDbStore) Shutdown() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -48,7 +48,7 @@ func (s *mongoTestSuite) TestMongoDbStore() {
 		Url:        s.addr,
 		Database:   "test_db",
 		Collection: "coll",
-	}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	defer store.Shutdown()
 
@@ -77,7 +77,7 @@ func (s *mongoTestSuite) TestMongoDbStore_Empty() {
 		Url:        s.addr,
 		Database:   "test_db",
 		Collection: "coll",
-	}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	defer store.Shutdown()
 
@@ -85,13 +85,36 @@ func (s *mongoTestSuite) TestMongoDbStore_Empty() {
 	assert.Error(s.T(), err)
 }
 
+func (s *mongoTestSuite) TestMongoDbStore_Ttl() {
+	store, err := newMongoDb(s.T().Context(), &config.MongoDbConfig{
+		Enabled:    true,
+		Url:        s.addr,
+		Database:   "test_db",
+		Collection: "coll_ttl",
+	}, 1*time.Second, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	assert.NoError(s.T(), err)
+	defer store.Shutdown()
+
+	err = store.Set(s.T().Context(), "k1", []byte("test"))
+	assert.NoError(s.T(), err)
+
+	res, err := store.Get(s.T().Context(), "k1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []byte("test"), res)
+
+	time.Sleep(2 * time.Second)
+
+	_, err = store.Get(s.T().Context(), "k1")
+	assert.ErrorContains(s.T(), err, "cache item not found for key 'k1'")
+}
+
 func (s *mongoTestSuite) TestMongoDbStore_Invalid() {
 	_, err := newMongoDb(s.T().Context(), &config.MongoDbConfig{
 		Enabled:    true,
 		Url:        "invalid",
 		Database:   "test_db",
 		Collection: "coll",
-	}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 
 	assert.Error(s.T(), err)
 }
@@ -109,7 +132,7 @@ func (s *mongoTestSuite) TestMongoDbStore_TLS_Invalid() {
 				{Key: "nonexisting", Cert: "nonexisting"},
 			},
 		},
-	}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.ErrorContains(s.T(), err, "failed to load certificate and key files")
 	assert.Nil(s.T(), store)
 }
@@ -123,7 +146,7 @@ func (s *mongoTestSuite) TestMongoDbStore_Connect_Fails() {
 		Url:        "mongodb://localhost:12345",
 		Database:   "test_db",
 		Collection: "coll",
-	}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.ErrorContains(s.T(), err, "context deadline exceeded")
 	assert.Nil(s.T(), store)
 }
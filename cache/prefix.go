@@ -0,0 +1,34 @@
+package cache
+
+import "context"
+
+// prefixedStore puts every key under a namespace, so multiple proxy deployments can share the same cache.
+type prefixedStore struct {
+	External
+	prefix string
+}
+
+func newPrefixed(prefix string, inner External) External {
+	return &prefixedStore{External: inner, prefix: prefix}
+}
+
+func (p *prefixedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return p.External.Get(ctx, p.prefix+key)
+}
+
+func (p *prefixedStore) Set(ctx context.Context, key string, value []byte) error {
+	return p.External.Set(ctx, p.prefix+key, value)
+}
+
+func (p *prefixedStore) unwrap() External {
+	return p.External
+}
+
+type prefixedFileBased struct {
+	FileBased
+	prefix string
+}
+
+func (p *prefixedFileBased) EntryPath(key string) string {
+	return p.FileBased.EntryPath(p.prefix + key)
+}
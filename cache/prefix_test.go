@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixedStore_Redis(t *testing.T) {
+	s := miniredis.RunT(t)
+	store, err := SetupExternalCache(&config.CacheConfig{
+		KeyPrefix: "team-a:",
+		Ttl:       60,
+		Redis:     config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}},
+	}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+	assert.IsType(t, &prefixedStore{}, store)
+
+	err = store.Set(t.Context(), "k1", []byte("test"))
+	assert.NoError(t, err)
+
+	assert.False(t, s.Exists("k1"))
+	val, err := s.Get("team-a:k1")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", val)
+	assert.Equal(t, 60*time.Second, s.TTL("team-a:k1"))
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test"), res)
+
+	s.FastForward(61 * time.Second)
+
+	_, err = store.Get(t.Context(), "k1")
+	assert.Error(t, err)
+}
+
+func TestPrefixedStore_Filesystem(t *testing.T) {
+	dir := t.TempDir()
+	store, err := SetupExternalCache(&config.CacheConfig{
+		KeyPrefix:  "team-a-",
+		Filesystem: config.FilesystemConfig{Enabled: true, Path: dir},
+		Encryption: config.EncryptionConfig{Enabled: true, KeyFile: writeKeyFile(t, "k1")},
+	}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	err = store.Set(t.Context(), "k1", []byte("test"))
+	assert.NoError(t, err)
+
+	fileBased, ok := AsFileBased(store)
+	assert.True(t, ok)
+	path := fileBased.EntryPath("k1")
+	assert.Equal(t, filepath.Join(dir, "team-a-k1.cache"), path)
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test"), res)
+}
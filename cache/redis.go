@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
@@ -11,14 +13,43 @@ import (
 
 type redisStore struct {
 	redisDb redis.UniversalClient
+	ttl     time.Duration
 	log     log.Logger
 }
 
-func newRedis(conf *config.RedisConfig, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+func newRedis(conf *config.RedisConfig, ttl time.Duration, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+	opts, err := redisOptions(conf)
+	if err != nil {
+		log.Errorf("failed to configure TLS for Redis: %s", err)
+		return nil, err
+	}
+	rdb := redis.NewUniversalClient(opts)
+	telemetryReporter.InstrumentRedis(rdb)
+	log.Reportf("using Redis (%s) for cache storage", conf.Topology())
+	return &redisStore{
+		redisDb: rdb,
+		ttl:     ttl,
+		log:     log,
+	}, nil
+}
+
+func redisOptions(conf *config.RedisConfig) (*redis.UniversalOptions, error) {
 	opts := &redis.UniversalOptions{
-		Addrs:    conf.Addresses,
-		Password: conf.Password,
-		DB:       conf.DB,
+		Addrs:            conf.Addresses,
+		Password:         conf.Password,
+		DB:               conf.DB,
+		MasterName:       conf.MasterName,
+		SentinelUsername: conf.SentinelUser,
+		SentinelPassword: conf.SentinelPassword,
+		IsClusterMode:    conf.ClusterMode,
+		ReadOnly:         conf.ReadOnly,
+		RouteByLatency:   conf.RouteByLatency,
+		RouteRandomly:    conf.RouteRandomly,
+		PoolSize:         conf.PoolSize,
+		MinIdleConns:     conf.MinIdleConns,
+		DialTimeout:      time.Duration(conf.DialTimeoutMs) * time.Millisecond,
+		ReadTimeout:      time.Duration(conf.ReadTimeoutMs) * time.Millisecond,
+		WriteTimeout:     time.Duration(conf.WriteTimeoutMs) * time.Millisecond,
 	}
 	if conf.User != "" {
 		opts.Username = conf.User
@@ -26,26 +57,27 @@ func newRedis(conf *config.RedisConfig, telemetryReporter telemetry.Reporter, lo
 	if conf.Tls.Enabled {
 		t, err := conf.Tls.LoadTlsOptions()
 		if err != nil {
-			log.Errorf("failed to configure TLS for Redis: %s", err)
 			return nil, err
 		}
 		opts.TLSConfig = t
 	}
-	rdb := redis.NewUniversalClient(opts)
-	telemetryReporter.InstrumentRedis(rdb)
-	log.Reportf("using Redis for cache storage")
-	return &redisStore{
-		redisDb: rdb,
-		log:     log,
-	}, nil
+	return opts, nil
 }
 
 func (r *redisStore) Get(ctx context.Context, key string) ([]byte, error) {
-	return r.redisDb.Get(ctx, key).Bytes()
+	data, err := r.redisDb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, notFound(key)
+	}
+	return data, err
 }
 
 func (r *redisStore) Set(ctx context.Context, key string, value []byte) error {
-	return r.redisDb.Set(ctx, key, value, 0).Err()
+	return r.redisDb.Set(ctx, key, value, r.ttl).Err()
+}
+
+func (r *redisStore) Ping(ctx context.Context) error {
+	return r.redisDb.Ping(ctx).Err()
 }
 
 func (r *redisStore) Shutdown() {
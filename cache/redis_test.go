@@ -48,7 +48,7 @@ func TestRedisSuite(t *testing.T) {
 }
 
 func (s *redisTestSuite) TestRedisStorage() {
-	store, err := newRedis(&config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	store, err := newRedis(&config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	srv := store.(*redisStore)
 	defer srv.Shutdown()
@@ -65,7 +65,7 @@ func (s *redisTestSuite) TestRedisStorage() {
 }
 
 func (s *redisTestSuite) TestRedisStorage_Unavailable() {
-	store, err := newRedis(&config.RedisConfig{Addresses: []string{"nonexisting"}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	store, err := newRedis(&config.RedisConfig{Addresses: []string{"nonexisting"}}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	srv := store.(*redisStore)
 	defer srv.Shutdown()
@@ -127,7 +127,7 @@ func TestRedisStorage_TLS(t *testing.T) {
 							{Key: key, Cert: cert},
 						},
 					},
-				}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+				}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 				assert.NoError(t, err)
 				assert.NotNil(t, store)
 			})
@@ -143,8 +143,39 @@ func TestRedisStorage_TLS(t *testing.T) {
 					{Key: "nonexisting", Cert: "nonexisting"},
 				},
 			},
-		}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+		}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 		assert.ErrorContains(t, err, "failed to load certificate and key files")
 		assert.Nil(t, store)
 	})
 }
+
+func TestRedisOptions(t *testing.T) {
+	opts, err := redisOptions(&config.RedisConfig{
+		Addresses:        []string{"sentinel1:26379", "sentinel2:26379"},
+		Password:         "pass",
+		User:             "user",
+		MasterName:       "master",
+		SentinelUser:     "s_user",
+		SentinelPassword: "s_pass",
+		ReadOnly:         true,
+		PoolSize:         20,
+		MinIdleConns:     5,
+		DialTimeoutMs:    500,
+		ReadTimeoutMs:    200,
+		WriteTimeoutMs:   300,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sentinel1:26379", "sentinel2:26379"}, opts.Addrs)
+	assert.Equal(t, "user", opts.Username)
+	assert.Equal(t, "pass", opts.Password)
+	assert.Equal(t, "master", opts.MasterName)
+	assert.Equal(t, "s_user", opts.SentinelUsername)
+	assert.Equal(t, "s_pass", opts.SentinelPassword)
+	assert.True(t, opts.ReadOnly)
+	assert.Equal(t, 20, opts.PoolSize)
+	assert.Equal(t, 5, opts.MinIdleConns)
+	assert.Equal(t, 500*time.Millisecond, opts.DialTimeout)
+	assert.Equal(t, 200*time.Millisecond, opts.ReadTimeout)
+	assert.Equal(t, 300*time.Millisecond, opts.WriteTimeout)
+	assert.True(t, opts.Failover().ReplicaOnly)
+}
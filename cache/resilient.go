@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/log"
+)
+
+// ErrCircuitOpen is returned without touching the cache while its circuit breaker is open.
+var ErrCircuitOpen = errors.New("cache circuit breaker is open")
+
+// resilientStore puts a timeout on each cache operation, fails fast with a circuit breaker when
+// the cache keeps failing, and pings the cache periodically to keep its status up to date.
+type resilientStore struct {
+	External
+	timeout        time.Duration
+	breaker        *circuitBreaker
+	component      string
+	statusReporter status.Reporter
+	log            log.Logger
+	stop           chan struct{}
+	wg             sync.WaitGroup
+}
+
+func newResilient(inner External, conf *config.CacheConfig, component string, statusReporter status.Reporter, log log.Logger) External {
+	if conf.OperationTimeoutMs == 0 && conf.PingInterval == 0 && !conf.CircuitBreaker.Enabled {
+		return inner
+	}
+	r := &resilientStore{
+		External:       inner,
+		timeout:        time.Duration(conf.OperationTimeoutMs) * time.Millisecond,
+		component:      component,
+		statusReporter: statusReporter,
+		log:            log,
+		stop:           make(chan struct{}),
+	}
+	if conf.CircuitBreaker.Enabled {
+		r.breaker = &circuitBreaker{
+			threshold:    conf.CircuitBreaker.FailureThreshold,
+			openDuration: time.Duration(conf.CircuitBreaker.OpenDuration) * time.Second,
+		}
+	}
+	if conf.PingInterval > 0 {
+		r.wg.Add(1)
+		go r.runPing(time.Duration(conf.PingInterval) * time.Second)
+	}
+	return r
+}
+
+func (r *resilientStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if !r.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	data, err := r.External.Get(ctx, key)
+	r.record(err)
+	return data, err
+}
+
+func (r *resilientStore) Set(ctx context.Context, key string, value []byte) error {
+	if !r.breaker.allow() {
+		return ErrCircuitOpen
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	err := r.External.Set(ctx, key, value)
+	r.record(err)
+	return err
+}
+
+// Ping bypasses the circuit breaker, so a successful ping closes it before the open duration elapses.
+func (r *resilientStore) Ping(ctx context.Context) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	err := r.External.Ping(ctx)
+	r.record(err)
+	if err != nil {
+		r.log.Debugf("cache ping failed: %s", err)
+		r.statusReporter.ReportError(r.component, "cache ping failed")
+	} else {
+		r.statusReporter.ReportOk(r.component, "cache ping succeeded")
+	}
+	return err
+}
+
+func (r *resilientStore) Shutdown() {
+	close(r.stop)
+	r.wg.Wait()
+	r.External.Shutdown()
+}
+
+func (r *resilientStore) unwrap() External {
+	return r.External
+}
+
+func (r *resilientStore) runPing(interval time.Duration) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = r.Ping(context.Background())
+		select {
+		case <-ticker.C:
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *resilientStore) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+func (r *resilientStore) record(err error) {
+	if err == nil || errors.Is(err, ErrNotFound) {
+		if r.breaker.success() {
+			r.log.Reportf("cache recovered, circuit breaker closed")
+		}
+		return
+	}
+	if r.breaker.failure() {
+		r.log.Warnf("cache is failing, circuit breaker opened for %s: %s", r.breaker.openDuration, err)
+	}
+}
+
+// circuitBreaker opens after a given number of consecutive failures. Once the open duration elapses,
+// it lets a single trial operation through, which either closes it or opens it again. A nil breaker is always closed.
+type circuitBreaker struct {
+	threshold    int
+	openDuration time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+func (c *circuitBreaker) allow() bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures < c.threshold {
+		return true
+	}
+	if c.trial || time.Now().Before(c.openUntil) {
+		return false
+	}
+	c.trial = true
+	return true
+}
+
+// success reports whether the breaker was closed by this call.
+func (c *circuitBreaker) success() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wasOpen := c.failures >= c.threshold
+	c.failures = 0
+	c.trial = false
+	return wasOpen
+}
+
+// failure reports whether the breaker was opened by this call.
+func (c *circuitBreaker) failure() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wasOpen := c.failures >= c.threshold
+	c.failures++
+	c.trial = false
+	if c.failures >= c.threshold {
+		c.openUntil = time.Now().Add(c.openDuration)
+		return !wasOpen
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResilientStore_Timeout(t *testing.T) {
+	inner := &fakeStore{delay: time.Second}
+	store := newResilient(inner, &config.CacheConfig{OperationTimeoutMs: 50}, status.Cache, status.NewEmptyReporter(), log.NewNullLogger())
+	defer store.Shutdown()
+
+	start := time.Now()
+	_, err := store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	err = store.Set(t.Context(), "key", []byte("test"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestResilientStore_CircuitBreaker(t *testing.T) {
+	inner := &fakeStore{}
+	inner.fail.Store(true)
+	store := newResilient(inner, &config.CacheConfig{CircuitBreaker: config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 2, OpenDuration: 1}}, status.Cache, status.NewEmptyReporter(), log.NewNullLogger())
+	defer store.Shutdown()
+
+	_, err := store.Get(t.Context(), "key")
+	assert.ErrorContains(t, err, "cache failure")
+	err = store.Set(t.Context(), "key", []byte("test"))
+	assert.ErrorContains(t, err, "cache failure")
+	assert.Equal(t, int32(2), inner.calls.Load())
+
+	_, err = store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	err = store.Set(t.Context(), "key", []byte("test"))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), inner.calls.Load())
+
+	time.Sleep(1100 * time.Millisecond)
+
+	// trial operation fails, opens again
+	_, err = store.Get(t.Context(), "key")
+	assert.ErrorContains(t, err, "cache failure")
+	_, err = store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), inner.calls.Load())
+
+	time.Sleep(1100 * time.Millisecond)
+	inner.fail.Store(false)
+
+	// trial operation succeeds, closes
+	err = store.Set(t.Context(), "key", []byte("test"))
+	assert.NoError(t, err)
+	res, err := store.Get(t.Context(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("test"), res)
+}
+
+func TestResilientStore_NotFound(t *testing.T) {
+	inner := &fakeStore{}
+	store := newResilient(inner, &config.CacheConfig{CircuitBreaker: config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 1, OpenDuration: 10}}, status.Cache, status.NewEmptyReporter(), log.NewNullLogger())
+	defer store.Shutdown()
+
+	_, err := store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestResilientStore_Ping(t *testing.T) {
+	inner := &fakeStore{}
+	inner.fail.Store(true)
+	reporter := status.NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true}})
+	store := newResilient(inner, &config.CacheConfig{PingInterval: 1, CircuitBreaker: config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 1, OpenDuration: 10}}, status.Cache, reporter, log.NewNullLogger())
+	defer store.Shutdown()
+
+	assert.Eventually(t, func() bool {
+		return reporter.GetStatus().Cache.Status == status.Degraded
+	}, 3*time.Second, 50*time.Millisecond)
+	_, err := store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	inner.fail.Store(false)
+	assert.Eventually(t, func() bool {
+		return reporter.GetStatus().Cache.Status == status.Healthy
+	}, 3*time.Second, 50*time.Millisecond)
+	assert.Contains(t, reporter.GetStatus().Cache.Records[0], "cache ping failed")
+
+	// a successful ping closes the breaker before the open duration elapses
+	_, err = store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestResilientStore_Disabled(t *testing.T) {
+	inner := &fakeStore{}
+	store := newResilient(inner, &config.CacheConfig{}, status.Cache, status.NewEmptyReporter(), log.NewNullLogger())
+	assert.Same(t, inner, store)
+}
+
+type fakeStore struct {
+	delay time.Duration
+	fail  atomic.Bool
+	calls atomic.Int32
+	value atomic.Pointer[[]byte]
+}
+
+func (f *fakeStore) Get(ctx context.Context, key string) ([]byte, error) {
+	f.calls.Add(1)
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	if v := f.value.Load(); v != nil {
+		return *v, nil
+	}
+	return nil, notFound(key)
+}
+
+func (f *fakeStore) Set(ctx context.Context, _ string, value []byte) error {
+	f.calls.Add(1)
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	f.value.Store(&value)
+	return nil
+}
+
+func (f *fakeStore) Ping(ctx context.Context) error {
+	return f.wait(ctx)
+}
+
+func (f *fakeStore) Shutdown() {}
+
+func (f *fakeStore) wait(ctx context.Context) error {
+	if f.fail.Load() {
+		return errors.New("cache failure")
+	}
+	select {
+	case <-time.After(f.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
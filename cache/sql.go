@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+type sqlDialect struct {
+	driverName  string
+	createTable string
+	selectEntry string
+	upsertEntry string
+}
+
+// the table name is validated during config validation, so it's safe to format it into the queries
+var sqlDialects = map[string]sqlDialect{
+	"postgres": {
+		driverName:  "pgx",
+		createTable: "CREATE TABLE IF NOT EXISTS %s (cache_key VARCHAR(255) PRIMARY KEY, payload BYTEA NOT NULL)",
+		selectEntry: "SELECT payload FROM %s WHERE cache_key = $1",
+		upsertEntry: "INSERT INTO %s (cache_key, payload) VALUES ($1, $2) ON CONFLICT (cache_key) DO UPDATE SET payload = EXCLUDED.payload",
+	},
+	"mysql": {
+		driverName:  "mysql",
+		createTable: "CREATE TABLE IF NOT EXISTS %s (cache_key VARCHAR(255) NOT NULL PRIMARY KEY, payload LONGBLOB NOT NULL)",
+		selectEntry: "SELECT payload FROM %s WHERE cache_key = ?",
+		upsertEntry: "INSERT INTO %s (cache_key, payload) VALUES (?, ?) ON DUPLICATE KEY UPDATE payload = VALUES(payload)",
+	},
+	"sqlite": {
+		driverName:  "sqlite",
+		createTable: "CREATE TABLE IF NOT EXISTS %s (cache_key TEXT PRIMARY KEY, payload BLOB NOT NULL)",
+		selectEntry: "SELECT payload FROM %s WHERE cache_key = ?",
+		upsertEntry: "INSERT INTO %s (cache_key, payload) VALUES (?, ?) ON CONFLICT (cache_key) DO UPDATE SET payload = excluded.payload",
+	},
+}
+
+type sqlStore struct {
+	db          *sql.DB
+	selectEntry string
+	upsertEntry string
+	log         log.Logger
+}
+
+func newSql(ctx context.Context, conf *config.SqlConfig, telemetryReporter telemetry.Reporter, log log.Logger) (External, error) {
+	dialect, ok := sqlDialects[conf.Driver]
+	if !ok {
+		err := fmt.Errorf("unsupported SQL driver: %s", conf.Driver)
+		log.Errorf("%s", err)
+		return nil, err
+	}
+	db, err := sql.Open(telemetryReporter.InstrumentSql(dialect.driverName), conf.DSN)
+	if err != nil {
+		log.Errorf("couldn't connect to the SQL database: %s", err)
+		return nil, err
+	}
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		log.Errorf("couldn't connect to the SQL database: %s", err)
+		return nil, err
+	}
+	if conf.AutoMigrate {
+		if _, err = db.ExecContext(ctx, fmt.Sprintf(dialect.createTable, conf.Table)); err != nil {
+			_ = db.Close()
+			log.Errorf("couldn't create the '%s' SQL table: %s", conf.Table, err)
+			return nil, err
+		}
+	}
+	log.Reportf("using %s for cache storage", conf.Driver)
+	return &sqlStore{
+		db:          db,
+		selectEntry: fmt.Sprintf(dialect.selectEntry, conf.Table),
+		upsertEntry: fmt.Sprintf(dialect.upsertEntry, conf.Table),
+		log:         log,
+	}, nil
+}
+
+func (s *sqlStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var payload []byte
+	err := s.db.QueryRowContext(ctx, s.selectEntry, key).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound(key)
+	}
+	return payload, err
+}
+
+func (s *sqlStore) Set(ctx context.Context, key string, value []byte) error {
+	_, err := s.db.ExecContext(ctx, s.upsertEntry, key, value)
+	return err
+}
+
+func (s *sqlStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *sqlStore) Shutdown() {
+	err := s.db.Close()
+	if err != nil {
+		s.log.Errorf("shutdown error: %s", err)
+	}
+	s.log.Reportf("shutdown complete")
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSqliteConfig(t *testing.T) *config.SqlConfig {
+	return &config.SqlConfig{
+		Enabled:     true,
+		Driver:      "sqlite",
+		DSN:         filepath.Join(t.TempDir(), "cache.db"),
+		Table:       "configcat_proxy_cache",
+		AutoMigrate: true,
+	}
+}
+
+func TestSqlStore(t *testing.T) {
+	store, err := newSql(t.Context(), newSqliteConfig(t), telemetry.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	cacheEntry := configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test`))
+
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	res, err := store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+
+	cacheEntry = configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test2`))
+
+	err = store.Set(t.Context(), "k1", cacheEntry)
+	assert.NoError(t, err)
+
+	res, err = store.Get(t.Context(), "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, cacheEntry, res)
+}
+
+func TestSqlStore_Empty(t *testing.T) {
+	store, err := newSql(t.Context(), newSqliteConfig(t), telemetry.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	_, err = store.Get(t.Context(), "k2")
+	assert.ErrorContains(t, err, "cache item not found for key 'k2'")
+}
+
+func TestSqlStore_NoAutoMigrate(t *testing.T) {
+	conf := newSqliteConfig(t)
+	conf.AutoMigrate = false
+	store, err := newSql(t.Context(), conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	err = store.Set(t.Context(), "k1", []byte("test"))
+	assert.ErrorContains(t, err, "no such table")
+}
+
+func TestSqlStore_Invalid(t *testing.T) {
+	conf := newSqliteConfig(t)
+	conf.Driver = "invalid"
+	_, err := newSql(t.Context(), conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	assert.ErrorContains(t, err, "unsupported SQL driver: invalid")
+
+	conf = newSqliteConfig(t)
+	conf.DSN = filepath.Join(t.TempDir(), "nonexisting", "cache.db")
+	_, err = newSql(t.Context(), conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	assert.Error(t, err)
+}
+
+func TestSetupExternalCache_Sql(t *testing.T) {
+	store, err := SetupExternalCache(&config.CacheConfig{Sql: *newSqliteConfig(t)}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	require.NoError(t, err)
+	defer store.Shutdown()
+	assert.IsType(t, &instrumentedStore{}, store)
+	assert.IsType(t, &sqlStore{}, store.(*instrumentedStore).External)
+}
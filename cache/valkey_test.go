@@ -49,7 +49,7 @@ func TestValkeySuite(t *testing.T) {
 }
 
 func (s *valkeyTestSuite) TestValkeyStorage() {
-	store, err := newRedis(&config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	store, err := newRedis(&config.RedisConfig{Addresses: []string{"localhost:" + s.dbPort}}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	srv := store.(*redisStore)
 	defer srv.Shutdown()
@@ -66,7 +66,7 @@ func (s *valkeyTestSuite) TestValkeyStorage() {
 }
 
 func (s *valkeyTestSuite) TestValkeyStorage_Unavailable() {
-	store, err := newRedis(&config.RedisConfig{Addresses: []string{"nonexisting"}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	store, err := newRedis(&config.RedisConfig{Addresses: []string{"nonexisting"}}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(s.T(), err)
 	srv := store.(*redisStore)
 	defer srv.Shutdown()
@@ -128,7 +128,7 @@ func TestValkeyStorage_TLS(t *testing.T) {
 							{Key: key, Cert: cert},
 						},
 					},
-				}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+				}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 				assert.NoError(t, err)
 				assert.NotNil(t, store)
 			})
@@ -144,7 +144,7 @@ func TestValkeyStorage_TLS(t *testing.T) {
 					{Key: "nonexisting", Cert: "nonexisting"},
 				},
 			},
-		}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+		}, 0, telemetry.NewEmptyReporter(), log.NewNullLogger())
 		assert.ErrorContains(t, err, "failed to load certificate and key files")
 		assert.Nil(t, store)
 	})
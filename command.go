@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/configcat/configcat-proxy/cache"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/configcat-proxy/sdk"
+)
+
+const (
+	validateCommand = "validate"
+	evalCommand     = "eval"
+	exportCommand   = "export"
+	importCommand   = "import"
+
+	cliSdkId = "cli"
+)
+
+type evalResult struct {
+	Key          string          `json:"key"`
+	Value        interface{}     `json:"value"`
+	VariationId  string          `json:"variationId"`
+	IsTargeting  bool            `json:"isTargeting"`
+	IsOverridden bool            `json:"isOverridden,omitempty"`
+	User         model.UserAttrs `json:"user,omitempty"`
+	Error        string          `json:"error,omitempty"`
+}
+
+func isCommand(args []string) bool {
+	if len(args) < 2 {
+		return false
+	}
+	switch args[1] {
+	case validateCommand, evalCommand, exportCommand, importCommand:
+		return true
+	}
+	return false
+}
+
+func runCommand(args []string, out io.Writer, errOut io.Writer) int {
+	switch args[1] {
+	case validateCommand:
+		return runValidate(args[2:], out, errOut)
+	case evalCommand:
+		return runEval(args[2:], out, errOut)
+	case exportCommand:
+		return runExport(args[2:], out, errOut)
+	case importCommand:
+		return runImport(args[2:], out, errOut)
+	}
+	_, _ = fmt.Fprintf(errOut, "unknown command: %s\n", args[1])
+	return exitFailure
+}
+
+func runValidate(args []string, out io.Writer, errOut io.Writer) int {
+	flags := flag.NewFlagSet(validateCommand, flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	var configFile string
+	flags.StringVar(&configFile, "c", "", "path to the configuration file")
+	if err := flags.Parse(args); err != nil {
+		return exitFailure
+	}
+
+	conf, err := config.LoadConfigFromFileAndEnvironment(configFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+	for _, env := range conf.UnknownEnvVars() {
+		_, _ = fmt.Fprintf(errOut, "warning: unknown environment variable: %s\n", env)
+	}
+	if unknown := conf.UnknownKeys(); len(unknown) > 0 {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", strings.Join(unknown, ", "))
+		return exitFailure
+	}
+	if err = conf.Validate(); err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+	redacted, err := conf.RedactedYaml()
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "failed to produce the effective configuration: %s\n", err)
+		return exitFailure
+	}
+	_, _ = fmt.Fprintf(out, "configuration is valid\n\n%s", redacted)
+	return exitOk
+}
+
+func runEval(args []string, out io.Writer, errOut io.Writer) int {
+	flags := flag.NewFlagSet(evalCommand, flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	var configFile, sdkId, configJsonFile, sdkKey, flagKey, userJson string
+	flags.StringVar(&configFile, "c", "", "path to the configuration file")
+	flags.StringVar(&sdkId, "sdk", "", "id of a configured SDK to evaluate with")
+	flags.StringVar(&configJsonFile, "file", "", "path to a config JSON file to evaluate with")
+	flags.StringVar(&sdkKey, "sdk-key", "", "SDK key of a config JSON stored in the configured cache to evaluate with")
+	flags.StringVar(&flagKey, "flag", "", "key of the feature flag or setting to evaluate")
+	flags.StringVar(&userJson, "user", "", "user attributes as a JSON object, e.g. {\"Identifier\":\"user1\"}")
+	if err := flags.Parse(args); err != nil {
+		return exitFailure
+	}
+	if flagKey == "" {
+		_, _ = fmt.Fprintf(errOut, "the -flag argument is required\n")
+		return exitFailure
+	}
+	sources := 0
+	for _, source := range []string{sdkId, configJsonFile, sdkKey} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		_, _ = fmt.Fprintf(errOut, "exactly one of the -sdk, -file, or -sdk-key arguments is required\n")
+		return exitFailure
+	}
+	var user model.UserAttrs
+	if userJson != "" {
+		if err := json.Unmarshal([]byte(userJson), &user); err != nil {
+			_, _ = fmt.Fprintf(errOut, "failed to parse user attributes: %s\n", err)
+			return exitFailure
+		}
+	}
+
+	conf, err := config.LoadConfigFromFileAndEnvironment(configFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+	logger := log.NewLogger(errOut, errOut, conf.Log.GetLevel())
+
+	switch {
+	case sdkId != "" && !conf.Profile.IsSet():
+		sdkConf, ok := conf.SDKs[sdkId]
+		if !ok {
+			_, _ = fmt.Fprintf(errOut, "SDK '%s' not found in the configuration\n", sdkId)
+			return exitFailure
+		}
+		// only the requested SDK is initialized
+		conf.SDKs = map[string]*config.SDKConfig{sdkId: sdkConf}
+	case configJsonFile != "":
+		conf.Profile = config.ProfileConfig{}
+		conf.SDKs = map[string]*config.SDKConfig{cliSdkId: {Key: cliSdkId, Offline: config.OfflineConfig{Enabled: true, Local: config.LocalConfig{FilePath: configJsonFile}}}}
+	case sdkKey != "":
+		conf.Profile = config.ProfileConfig{}
+		conf.SDKs = map[string]*config.SDKConfig{cliSdkId: {Key: sdkKey, Offline: config.OfflineConfig{Enabled: true, UseCache: true}}}
+	}
+	if sdkId == "" {
+		sdkId = cliSdkId
+		if err = conf.InitSdkConfig(sdkId, conf.SDKs[sdkId]); err != nil {
+			_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+			return exitFailure
+		}
+	}
+	if err = conf.Validate(); err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+
+	sdkRegistrar, shutdown, err := setupRegistrar(&conf, logger)
+	if err != nil {
+		return exitFailure
+	}
+	defer shutdown()
+
+	sdkClient := sdkRegistrar.GetSdkOrNil(sdkId)
+	if sdkClient == nil {
+		_, _ = fmt.Fprintf(errOut, "SDK '%s' not found\n", sdkId)
+		return exitFailure
+	}
+	evalData := sdkClient.Eval(flagKey, user)
+	res := evalResult{
+		Key:          flagKey,
+		Value:        evalData.Value,
+		VariationId:  evalData.VariationId,
+		IsTargeting:  evalData.IsTargeting,
+		IsOverridden: evalData.IsOverridden,
+	}
+	if attrs, ok := evalData.User.(model.UserAttrs); ok {
+		res.User = attrs
+	}
+	if evalData.Error != nil {
+		res.Error = evalData.Error.Error()
+	}
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "failed to produce the evaluation result: %s\n", err)
+		return exitFailure
+	}
+	_, _ = fmt.Fprintf(out, "%s\n", data)
+	if evalData.Error != nil {
+		return exitFailure
+	}
+	return exitOk
+}
+
+func runExport(args []string, out io.Writer, errOut io.Writer) int {
+	flags := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	var configFile, target string
+	var timeout time.Duration
+	flags.StringVar(&configFile, "c", "", "path to the configuration file")
+	flags.StringVar(&target, "out", "", "directory or .tar.gz archive to write the snapshot to")
+	flags.DurationVar(&timeout, "timeout", 30*time.Second, "how long to wait for the SDKs to initialize")
+	if err := flags.Parse(args); err != nil {
+		return exitFailure
+	}
+	if target == "" {
+		_, _ = fmt.Fprintf(errOut, "the -out argument is required\n")
+		return exitFailure
+	}
+
+	conf, err := config.LoadConfigFromFileAndEnvironment(configFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+	if err = conf.Validate(); err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+	logger := log.NewLogger(errOut, errOut, conf.Log.GetLevel())
+
+	sdkRegistrar, shutdown, err := setupRegistrar(&conf, logger)
+	if err != nil {
+		return exitFailure
+	}
+	defer shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	snapshot, err := sdk.TakeSnapshot(ctx, sdkRegistrar)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "failed to export: %s\n", err)
+		return exitFailure
+	}
+	if err = writeSnapshot(target, snapshot); err != nil {
+		_, _ = fmt.Fprintf(errOut, "failed to write snapshot to %s: %s\n", target, err)
+		return exitFailure
+	}
+	_, _ = fmt.Fprintf(out, "exported %d SDK(s) to %s\n", len(snapshot.SDKs), target)
+	return exitOk
+}
+
+func runImport(args []string, out io.Writer, errOut io.Writer) int {
+	flags := flag.NewFlagSet(importCommand, flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	var configFile, source string
+	flags.StringVar(&configFile, "c", "", "path to the configuration file")
+	flags.StringVar(&source, "in", "", "directory or .tar.gz archive to read the snapshot from")
+	if err := flags.Parse(args); err != nil {
+		return exitFailure
+	}
+	if source == "" {
+		_, _ = fmt.Fprintf(errOut, "the -in argument is required\n")
+		return exitFailure
+	}
+
+	conf, err := config.LoadConfigFromFileAndEnvironment(configFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: %s\n", err)
+		return exitFailure
+	}
+	if !conf.Cache.IsSet() {
+		_, _ = fmt.Fprintf(errOut, "invalid configuration: no cache is configured to import into\n")
+		return exitFailure
+	}
+	snapshot, err := readSnapshot(source)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "failed to read snapshot from %s: %s\n", source, err)
+		return exitFailure
+	}
+	logger := log.NewLogger(errOut, errOut, conf.Log.GetLevel())
+	externalCache, err := cache.SetupExternalCache(&conf.Cache, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), logger)
+	if err != nil {
+		return exitFailure
+	}
+	defer externalCache.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err = snapshot.WriteToCache(ctx, externalCache); err != nil {
+		_, _ = fmt.Fprintf(errOut, "failed to import: %s\n", err)
+		return exitFailure
+	}
+	_, _ = fmt.Fprintf(out, "imported %d SDK(s) from %s\n", len(snapshot.SDKs), source)
+	return exitOk
+}
+
+func setupRegistrar(conf *config.Config, logger log.Logger) (sdk.Registrar, func(), error) {
+	telemetryReporter := telemetry.NewEmptyReporter()
+	statusReporter := status.NewReporter(&conf.Cache)
+	var externalCache cache.External
+	var err error
+	if conf.Cache.IsSet() {
+		externalCache, err = cache.SetupExternalCache(&conf.Cache, telemetryReporter, statusReporter, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sdkRegistrar, err := sdk.NewRegistrar(conf, telemetryReporter, statusReporter, externalCache, logger)
+	if err != nil {
+		if externalCache != nil {
+			externalCache.Shutdown()
+		}
+		return nil, nil, err
+	}
+	return sdkRegistrar, func() {
+		sdkRegistrar.Close()
+		if externalCache != nil {
+			externalCache.Shutdown()
+		}
+	}, nil
+}
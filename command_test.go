@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/configcat/go-sdk/v9"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/configcat/go-sdk/v9/configcattest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_IsCommand(t *testing.T) {
+	assert.True(t, isCommand([]string{"app", "validate"}))
+	assert.False(t, isCommand([]string{"app", "-c", "options.yml"}))
+	assert.False(t, isCommand([]string{"app"}))
+}
+
+func TestCommand_Validate(t *testing.T) {
+	testutils.UseTempFile(`
+sdks:
+  sdk1:
+    key: "XxPbCKmzIUGORk4vsufpzw/iC_KABprDEueeQs3yovVnQ"
+cache:
+  redis:
+    enabled: true
+    addresses: ["localhost:6379"]
+    password: "redis-pass"
+`, func(file string) {
+		t.Setenv("CONFIGCAT_UNKNOWN_VAR", "value")
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "validate", "-c", file}, &out, &errOut)
+
+		assert.Equal(t, exitOk, exitCode)
+		assert.Contains(t, out.String(), "configuration is valid")
+		assert.Contains(t, out.String(), "key: <redacted>")
+		assert.Contains(t, out.String(), "password: <redacted>")
+		assert.NotContains(t, out.String(), "XxPbCKmzIUGORk4vsufpzw")
+		assert.NotContains(t, out.String(), "redis-pass")
+		assert.Contains(t, errOut.String(), "warning: unknown environment variable: CONFIGCAT_UNKNOWN_VAR")
+	})
+}
+
+func TestCommand_Validate_Invalid(t *testing.T) {
+	t.Run("unknown key", func(t *testing.T) {
+		testutils.UseTempFile(`
+sdks:
+  sdk1:
+    key: "key"
+    poll_intervall: 30
+`, func(file string) {
+			var out, errOut bytes.Buffer
+			exitCode := runCommand([]string{"app", "validate", "-c", file}, &out, &errOut)
+
+			assert.Equal(t, exitFailure, exitCode)
+			assert.Contains(t, errOut.String(), "line 5: unknown key 'sdks.sdk1.poll_intervall'")
+			assert.Empty(t, out.String())
+		})
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		testutils.UseTempFile(`
+sdks:
+  sdk1:
+    key: "key"
+    data_governance: "invalid"
+`, func(file string) {
+			var out, errOut bytes.Buffer
+			exitCode := runCommand([]string{"app", "validate", "-c", file}, &out, &errOut)
+
+			assert.Equal(t, exitFailure, exitCode)
+			assert.Contains(t, errOut.String(), "invalid configuration: sdk-sdk1: invalid data governance value")
+		})
+	})
+	t.Run("unknown command", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "non-existing"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+	})
+}
+
+func TestCommand_Eval(t *testing.T) {
+	key := configcattest.RandomSDKKey()
+	var h configcattest.Handler
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {
+			Default: true,
+			Rules: []configcattest.Rule{
+				{
+					Comparator:          configcat.OpEq,
+					Value:               false,
+					ComparisonValue:     "test",
+					ComparisonAttribute: "Identifier",
+				},
+			},
+		},
+	})
+	srv := httptest.NewServer(&h)
+	defer srv.Close()
+
+	testutils.UseTempFile(`
+sdks:
+  sdk1:
+    key: "`+key+`"
+    base_url: "`+srv.URL+`"
+default_user_attributes:
+  Identifier: "test"
+`, func(file string) {
+		t.Run("default attributes", func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			exitCode := runCommand([]string{"app", "eval", "-c", file, "-sdk", "sdk1", "-flag", "flag"}, &out, &errOut)
+
+			assert.Equal(t, exitOk, exitCode)
+			var res evalResult
+			require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+			assert.Equal(t, false, res.Value)
+			assert.True(t, res.IsTargeting)
+			assert.Equal(t, "test", res.User["Identifier"])
+		})
+		t.Run("user attributes", func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			exitCode := runCommand([]string{"app", "eval", "-c", file, "-sdk", "sdk1", "-flag", "flag", "-user", `{"Identifier":"other"}`}, &out, &errOut)
+
+			assert.Equal(t, exitOk, exitCode)
+			var res evalResult
+			require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+			assert.Equal(t, true, res.Value)
+			assert.False(t, res.IsTargeting)
+			assert.Equal(t, "other", res.User["Identifier"])
+		})
+		t.Run("flag not found", func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			exitCode := runCommand([]string{"app", "eval", "-c", file, "-sdk", "sdk1", "-flag", "non-existing"}, &out, &errOut)
+
+			assert.Equal(t, exitFailure, exitCode)
+			var res evalResult
+			require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+			assert.Contains(t, res.Error, "the key was not found in config JSON")
+		})
+		t.Run("sdk not found", func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			exitCode := runCommand([]string{"app", "eval", "-c", file, "-sdk", "sdk2", "-flag", "flag"}, &out, &errOut)
+
+			assert.Equal(t, exitFailure, exitCode)
+			assert.Contains(t, errOut.String(), "SDK 'sdk2' not found in the configuration")
+		})
+	})
+}
+
+func TestCommand_Eval_File(t *testing.T) {
+	testutils.UseTempFile(`{"f":{"flag":{"i":"","v":{"s":"value"},"t":1}}}`, func(path string) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "eval", "-file", path, "-flag", "flag"}, &out, &errOut)
+
+		assert.Equal(t, exitOk, exitCode)
+		var res evalResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+		assert.Equal(t, "flag", res.Key)
+		assert.Equal(t, "value", res.Value)
+	})
+}
+
+func TestCommand_Eval_Cache(t *testing.T) {
+	s := miniredis.RunT(t)
+	sdkKey := configcattest.RandomSDKKey()
+	cacheKey := configcatcache.ProduceCacheKey(sdkKey, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion)
+	_ = s.Set(cacheKey, string(configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`{"f":{"flag":{"i":"","v":{"i":42},"t":2}}}`))))
+	t.Setenv("CONFIGCAT_CACHE_REDIS_ENABLED", "true")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_ADDRESSES", "[\""+s.Addr()+"\"]")
+
+	var out, errOut bytes.Buffer
+	exitCode := runCommand([]string{"app", "eval", "-sdk-key", sdkKey, "-flag", "flag"}, &out, &errOut)
+
+	assert.Equal(t, exitOk, exitCode)
+	var res evalResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+	assert.Equal(t, float64(42), res.Value)
+}
+
+func TestCommand_Eval_Invalid(t *testing.T) {
+	t.Run("missing flag", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "eval", "-sdk", "sdk1"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "the -flag argument is required")
+	})
+	t.Run("multiple sources", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "eval", "-sdk", "sdk1", "-file", "config.json", "-flag", "flag"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "exactly one of the -sdk, -file, or -sdk-key arguments is required")
+	})
+	t.Run("invalid user", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "eval", "-sdk", "sdk1", "-flag", "flag", "-user", "{"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "failed to parse user attributes")
+	})
+	t.Run("sdk key without cache", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "eval", "-sdk-key", "key", "-flag", "flag"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "offline mode enabled with cache, but no cache is configured")
+	})
+}
+
+func TestCommand_ExportImport(t *testing.T) {
+	key := configcattest.RandomSDKKey()
+	var h configcattest.Handler
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {Default: "exported"},
+	})
+	srv := httptest.NewServer(&h)
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{"directory", filepath.Join(t.TempDir(), "snapshot")},
+		{"archive", filepath.Join(t.TempDir(), "snapshot.tar.gz")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("CONFIGCAT_SDKS", `{"sdk1":"`+key+`"}`)
+			t.Setenv("CONFIGCAT_SDK1_BASE_URL", srv.URL)
+
+			// every command gets its own buffers, so nothing reads a buffer a previous command may still write
+			run := func(args ...string) *bytes.Buffer {
+				var out, errOut bytes.Buffer
+				exitCode := runCommand(append([]string{"app"}, args...), &out, &errOut)
+				assert.Equal(t, exitOk, exitCode, errOut.String())
+				return &out
+			}
+
+			out := run("export", "-out", test.target)
+			assert.Equal(t, "exported 1 SDK(s) to "+test.target+"\n", out.String())
+
+			s := miniredis.RunT(t)
+			t.Setenv("CONFIGCAT_CACHE_REDIS_ENABLED", "true")
+			t.Setenv("CONFIGCAT_CACHE_REDIS_ADDRESSES", `["`+s.Addr()+`"]`)
+
+			out = run("import", "-in", test.target)
+			assert.Equal(t, "imported 1 SDK(s) from "+test.target+"\n", out.String())
+
+			out = run("eval", "-sdk-key", key, "-flag", "flag")
+			var res evalResult
+			require.NoError(t, json.Unmarshal(out.Bytes(), &res))
+			assert.Equal(t, "exported", res.Value)
+		})
+	}
+}
+
+func TestCommand_ExportImport_Invalid(t *testing.T) {
+	t.Run("export missing out", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "export"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "the -out argument is required")
+	})
+	t.Run("import missing in", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "import"}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "the -in argument is required")
+	})
+	t.Run("import without cache", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		exitCode := runCommand([]string{"app", "import", "-in", t.TempDir()}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "no cache is configured to import into")
+	})
+	t.Run("import empty snapshot", func(t *testing.T) {
+		s := miniredis.RunT(t)
+		t.Setenv("CONFIGCAT_CACHE_REDIS_ENABLED", "true")
+		t.Setenv("CONFIGCAT_CACHE_REDIS_ADDRESSES", `["`+s.Addr()+`"]`)
+
+		var out, errOut bytes.Buffer
+		dir := t.TempDir()
+		exitCode := runCommand([]string{"app", "import", "-in", dir}, &out, &errOut)
+
+		assert.Equal(t, exitFailure, exitCode)
+		assert.Contains(t, errOut.String(), "no snapshot found at "+dir)
+	})
+}
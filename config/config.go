@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
 	"time"
@@ -27,8 +28,33 @@ const (
 	DefaultSdkPollInterval     = 60
 	DefaultCachePollInterval   = 5
 	DefaultAutoSdkPollInterval = 300
+
+	DefaultUpstreamMaxReconnectDelay = 60
+	DefaultUpstreamReadTimeout       = 90
+	DefaultSseHeartBeatInterval      = 30
+
+	DefaultHistorySize = 10
+
+	CacheRedis      = "redis"
+	CacheMongoDb    = "mongodb"
+	CacheDynamoDb   = "dynamodb"
+	CacheSql        = "sql"
+	CacheFilesystem = "filesystem"
+
+	OverrideLocalOverRemote = "local_over_remote"
+	OverrideRemoteOverLocal = "remote_over_local"
+
+	DegradedReady    = "ready"
+	DegradedNotReady = "not_ready"
+
+	RedisStandalone = "standalone"
+	RedisSentinel   = "sentinel"
+	RedisCluster    = "cluster"
 )
 
+// CacheBackends lists the cache backend names in the order they are picked when no chain is configured.
+var CacheBackends = []string{CacheRedis, CacheMongoDb, CacheDynamoDb, CacheSql, CacheFilesystem}
+
 var allowedLogLevels = map[string]log.Level{
 	"debug": log.Debug,
 	"info":  log.Info,
@@ -55,33 +81,41 @@ type Config struct {
 	GlobalOfflineConfig GlobalOfflineConfig `yaml:"offline"`
 	DefaultAttrs        model.UserAttrs     `yaml:"default_user_attributes"`
 	Profile             ProfileConfig       `yaml:"profile"`
+	LocalSnapshot       LocalSnapshotConfig `yaml:"local_snapshot"`
+
+	unknownEnvVars []string
+	unknownKeys    []string
 }
 
 type SDKConfig struct {
-	Key                      string          `yaml:"key"`
+	Key                      string          `yaml:"key" secret:"true"`
 	BaseUrl                  string          `yaml:"base_url"`
 	PollInterval             int             `yaml:"poll_interval"`
 	DataGovernance           string          `yaml:"data_governance"`
 	WebhookSignatureValidFor int             `yaml:"webhook_signature_valid_for"`
-	WebhookSigningKey        string          `yaml:"webhook_signing_key"`
+	WebhookSigningKey        string          `yaml:"webhook_signing_key" secret:"true"`
 	DefaultAttrs             model.UserAttrs `yaml:"default_user_attributes"`
 	Offline                  OfflineConfig
+	Overrides                OverrideConfig
+	History                  HistoryConfig
+	Init                     InitConfig
 	Log                      LogConfig
 }
 
 type ProfileConfig struct {
-	Key                      string
-	Secret                   string
+	Key                      string `secret:"true"`
+	Secret                   string `secret:"true"`
 	BaseUrl                  string `yaml:"base_url"`
 	PollInterval             int    `yaml:"poll_interval"`
 	WebhookSignatureValidFor int    `yaml:"webhook_signature_valid_for"`
-	WebhookSigningKey        string `yaml:"webhook_signing_key"`
+	WebhookSigningKey        string `yaml:"webhook_signing_key" secret:"true"`
 	Log                      LogConfig
 	SDKs                     ProfileSDKConfig
 }
 
 type ProfileSDKConfig struct {
 	BaseUrl string `yaml:"base_url"`
+	Init    InitConfig
 	Log     LogConfig
 }
 
@@ -105,6 +139,7 @@ type KeepAliveConfig struct {
 type SseConfig struct {
 	Enabled           bool              `yaml:"enabled"`
 	Headers           map[string]string `yaml:"headers"`
+	AuthHeaders       map[string]string `yaml:"auth_headers" secret:"true"`
 	HeartBeatInterval int               `yaml:"heart_beat_interval"`
 	Log               LogConfig
 	CORS              CORSConfig
@@ -128,7 +163,7 @@ type HttpConfig struct {
 }
 
 type WebhookConfig struct {
-	AuthHeaders map[string]string `yaml:"auth_headers"`
+	AuthHeaders map[string]string `yaml:"auth_headers" secret:"true"`
 	Enabled     bool              `yaml:"enabled"`
 	Auth        AuthConfig
 }
@@ -140,14 +175,19 @@ type CdnProxyConfig struct {
 }
 
 type ApiConfig struct {
-	AuthHeaders map[string]string `yaml:"auth_headers"`
+	AuthHeaders map[string]string `yaml:"auth_headers" secret:"true"`
 	Headers     map[string]string `yaml:"headers"`
 	Enabled     bool              `yaml:"enabled"`
 	CORS        CORSConfig
+	Overrides   ApiOverridesConfig
+}
+
+type ApiOverridesConfig struct {
+	AuthHeaders map[string]string `yaml:"auth_headers" secret:"true"`
 }
 
 type OFREPConfig struct {
-	AuthHeaders map[string]string `yaml:"auth_headers"`
+	AuthHeaders map[string]string `yaml:"auth_headers" secret:"true"`
 	Headers     map[string]string `yaml:"headers"`
 	Enabled     bool              `yaml:"enabled"`
 	CORS        CORSConfig
@@ -170,13 +210,18 @@ type LogConfig struct {
 	Level string `yaml:"level"`
 }
 
+type LocalSnapshotConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
 type HttpProxyConfig struct {
-	Url string `yaml:"url"`
+	Url string `yaml:"url" secret:"true"`
 }
 
 type AuthConfig struct {
 	User     string `yaml:"user"`
-	Password string `yaml:"password"`
+	Password string `yaml:"password" secret:"true"`
 }
 
 type OfflineConfig struct {
@@ -185,6 +230,7 @@ type OfflineConfig struct {
 	CachePollInterval int  `yaml:"cache_poll_interval"`
 	Log               LogConfig
 	Local             LocalConfig
+	Upstream          UpstreamConfig
 }
 
 type GlobalOfflineConfig struct {
@@ -194,23 +240,50 @@ type GlobalOfflineConfig struct {
 }
 
 type CacheConfig struct {
-	Redis    RedisConfig
-	MongoDb  MongoDbConfig  `yaml:"mongodb"`
-	DynamoDb DynamoDbConfig `yaml:"dynamodb"`
+	KeyPrefix          string               `yaml:"key_prefix"`
+	Ttl                int                  `yaml:"ttl"`
+	OperationTimeoutMs int                  `yaml:"operation_timeout_ms"`
+	PingInterval       int                  `yaml:"ping_interval"`
+	CircuitBreaker     CircuitBreakerConfig `yaml:"circuit_breaker"`
+	Chain              []string             `yaml:"chain"`
+	Redis              RedisConfig
+	MongoDb            MongoDbConfig    `yaml:"mongodb"`
+	DynamoDb           DynamoDbConfig   `yaml:"dynamodb"`
+	Sql                SqlConfig        `yaml:"sql"`
+	Filesystem         FilesystemConfig `yaml:"filesystem"`
+	Encryption         EncryptionConfig `yaml:"encryption"`
+}
+
+type CircuitBreakerConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	FailureThreshold int  `yaml:"failure_threshold"`
+	OpenDuration     int  `yaml:"open_duration"`
 }
 
 type RedisConfig struct {
-	Enabled   bool     `yaml:"enabled"`
-	Addresses []string `yaml:"addresses"`
-	DB        int      `yaml:"db"`
-	User      string   `yaml:"user"`
-	Password  string   `yaml:"password"`
-	Tls       TlsConfig
+	Enabled          bool     `yaml:"enabled"`
+	Addresses        []string `yaml:"addresses"`
+	DB               int      `yaml:"db"`
+	User             string   `yaml:"user"`
+	Password         string   `yaml:"password" secret:"true"`
+	MasterName       string   `yaml:"master_name"`
+	SentinelUser     string   `yaml:"sentinel_user"`
+	SentinelPassword string   `yaml:"sentinel_password" secret:"true"`
+	ClusterMode      bool     `yaml:"cluster_mode"`
+	ReadOnly         bool     `yaml:"read_only"`
+	RouteByLatency   bool     `yaml:"route_by_latency"`
+	RouteRandomly    bool     `yaml:"route_randomly"`
+	PoolSize         int      `yaml:"pool_size"`
+	MinIdleConns     int      `yaml:"min_idle_conns"`
+	DialTimeoutMs    int      `yaml:"dial_timeout_ms"`
+	ReadTimeoutMs    int      `yaml:"read_timeout_ms"`
+	WriteTimeoutMs   int      `yaml:"write_timeout_ms"`
+	Tls              TlsConfig
 }
 
 type MongoDbConfig struct {
 	Enabled    bool   `yaml:"enabled"`
-	Url        string `yaml:"url"`
+	Url        string `yaml:"url" secret:"true"`
 	Database   string `yaml:"database"`
 	Collection string `yaml:"collection"`
 	Tls        TlsConfig
@@ -222,12 +295,56 @@ type DynamoDbConfig struct {
 	Table   string `yaml:"table"`
 }
 
+type SqlConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Driver      string `yaml:"driver"`
+	DSN         string `yaml:"dsn" secret:"true"`
+	Table       string `yaml:"table"`
+	AutoMigrate bool   `yaml:"auto_migrate"`
+}
+
+type FilesystemConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type EncryptionConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	KeyFile            string `yaml:"key_file"`
+	ActiveKeyId        string `yaml:"active_key_id"`
+	AllowPlaintextRead bool   `yaml:"allow_plaintext_read"`
+}
+
 type LocalConfig struct {
 	FilePath     string `yaml:"file_path"`
 	Polling      bool   `yaml:"polling"`
 	PollInterval int    `yaml:"poll_interval"`
 }
 
+type OverrideConfig struct {
+	Behavior string                 `yaml:"behavior"`
+	FilePath string                 `yaml:"file_path"`
+	Values   map[string]interface{} `yaml:"values"`
+}
+
+type HistoryConfig struct {
+	Size     int  `yaml:"size"`
+	UseCache bool `yaml:"use_cache"`
+}
+
+type InitConfig struct {
+	Timeout       int  `yaml:"timeout"`
+	ServeDefaults bool `yaml:"serve_defaults"`
+}
+
+type UpstreamConfig struct {
+	Url               string            `yaml:"url"`
+	SdkId             string            `yaml:"sdk_id"`
+	MaxReconnectDelay int               `yaml:"max_reconnect_delay"`
+	ReadTimeout       int               `yaml:"read_timeout"`
+	Headers           map[string]string `yaml:"headers" secret:"true"`
+}
+
 type TlsConfig struct {
 	Enabled      bool    `yaml:"enabled"`
 	MinVersion   float64 `yaml:"min_version"`
@@ -241,6 +358,8 @@ type DiagConfig struct {
 	Metrics MetricsConfig `yaml:"metrics"`
 	Traces  TraceConfig   `yaml:"traces"`
 	Status  StatusConfig  `yaml:"status"`
+	Health  HealthConfig  `yaml:"health"`
+	Admin   AdminConfig   `yaml:"admin"`
 }
 
 type MetricsConfig struct {
@@ -265,7 +384,24 @@ type OtlpExporterConfig struct {
 }
 
 type StatusConfig struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled     bool              `yaml:"enabled"`
+	MaskSdkKeys bool              `yaml:"mask_sdk_keys"`
+	AuthHeaders map[string]string `yaml:"auth_headers" secret:"true"`
+	Auth        AuthConfig
+	CORS        CORSConfig
+}
+
+type HealthConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	SDKs           []string `yaml:"sdks"`
+	RequireCache   bool     `yaml:"require_cache"`
+	DegradedPolicy string   `yaml:"degraded_policy"`
+}
+
+type AdminConfig struct {
+	Enabled     bool              `yaml:"enabled"`
+	AuthHeaders map[string]string `yaml:"auth_headers" secret:"true"`
+	Auth        AuthConfig
 }
 
 func LoadConfigFromFileAndEnvironment(filePath string) (Config, error) {
@@ -285,7 +421,7 @@ func LoadConfigFromFileAndEnvironment(filePath string) (Config, error) {
 		if err != nil {
 			return Config{}, fmt.Errorf("failed to read config file %s: %s", realPath, err)
 		}
-		err = yaml.Unmarshal(data, &config)
+		err = config.loadYaml(data)
 		if err != nil {
 			return Config{}, fmt.Errorf("failed to parse YAML from config file %s: %s", realPath, err)
 		}
@@ -296,7 +432,7 @@ func LoadConfigFromFileAndEnvironment(filePath string) (Config, error) {
 			if err != nil {
 				return Config{}, fmt.Errorf("failed to read config file %s: %s", defaultPath, err)
 			}
-			err = yaml.Unmarshal(data, &config)
+			err = config.loadYaml(data)
 			if err != nil {
 				return Config{}, fmt.Errorf("failed to parse YAML from config file %s: %s", defaultPath, err)
 			}
@@ -319,6 +455,32 @@ func LoadConfigFromFileAndEnvironment(filePath string) (Config, error) {
 	return config, nil
 }
 
+func (c *Config) loadYaml(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if err := resolveReferences(&root); err != nil {
+		return err
+	}
+	if root.Kind == 0 {
+		return nil
+	}
+	c.unknownKeys = nil
+	checkUnknownKeys(&root, reflect.TypeOf(c), "", &c.unknownKeys)
+	return root.Decode(c)
+}
+
+// UnknownEnvVars returns the CONFIGCAT_ prefixed environment variables that weren't recognized during loading
+func (c *Config) UnknownEnvVars() []string {
+	return c.unknownEnvVars
+}
+
+// UnknownKeys returns the keys of the YAML config file that don't correspond to any config option, e.g. "line 5: unknown key 'sdks.sdk1.poll_intervall'"
+func (c *Config) UnknownKeys() []string {
+	return c.unknownKeys
+}
+
 func (l *LogConfig) GetLevel() log.Level {
 	if lvl, ok := allowedLogLevels[l.Level]; ok {
 		return lvl
@@ -342,6 +504,9 @@ func (c *Config) setDefaults() {
 	c.Diag.Enabled = true
 	c.Diag.Port = 8051
 	c.Diag.Status.Enabled = true
+	c.Diag.Status.MaskSdkKeys = true
+	c.Diag.Health.Enabled = true
+	c.Diag.Health.DegradedPolicy = DegradedReady
 	c.Diag.Metrics.Enabled = true
 	c.Diag.Metrics.Prometheus.Enabled = true
 	c.Diag.Metrics.Otlp.Protocol = "http"
@@ -349,6 +514,7 @@ func (c *Config) setDefaults() {
 
 	c.Http.Sse.Enabled = true
 	c.Http.Sse.CORS.Enabled = true
+	c.Http.Sse.HeartBeatInterval = DefaultSseHeartBeatInterval
 
 	c.Http.CdnProxy.Enabled = true
 	c.Http.CdnProxy.CORS.Enabled = true
@@ -362,6 +528,12 @@ func (c *Config) setDefaults() {
 	c.Http.Webhook.Enabled = true
 
 	c.Http.Status.Enabled = false
+	c.Http.Status.MaskSdkKeys = true
+
+	c.Cache.OperationTimeoutMs = 5000
+	c.Cache.PingInterval = 30
+	c.Cache.CircuitBreaker.FailureThreshold = 5
+	c.Cache.CircuitBreaker.OpenDuration = 30
 
 	c.Cache.Redis.DB = 0
 	c.Cache.Redis.Addresses = []string{"localhost:6379"}
@@ -371,26 +543,25 @@ func (c *Config) setDefaults() {
 
 	c.Cache.DynamoDb.Table = "configcat_proxy_cache"
 
+	c.Cache.Sql.Table = "configcat_proxy_cache"
+	c.Cache.Sql.AutoMigrate = true
+
 	c.Profile.BaseUrl = "https://api.configcat.com"
 }
 
+func (c *Config) InitSdkConfig(sdkId string, sdk *SDKConfig) error {
+	sdk.fixupLogLevels(c.Log.Level)
+	sdk.fixupDefaults()
+	sdk.fixupOffline(&c.GlobalOfflineConfig)
+	return sdk.validate(&c.Cache, &c.Profile, sdkId)
+}
+
 func (c *Config) fixupDefaults() {
 	for _, sdk := range c.SDKs {
 		if sdk == nil {
 			continue
 		}
-		if sdk.WebhookSignatureValidFor == 0 {
-			sdk.WebhookSignatureValidFor = DefaultWebhookSignatureValidFor
-		}
-		if sdk.PollInterval == 0 {
-			sdk.PollInterval = DefaultSdkPollInterval
-		}
-		if sdk.Offline.Local.PollInterval == 0 {
-			sdk.Offline.Local.PollInterval = DefaultCachePollInterval
-		}
-		if sdk.Offline.CachePollInterval == 0 {
-			sdk.Offline.CachePollInterval = DefaultCachePollInterval
-		}
+		sdk.fixupDefaults()
 	}
 	if c.GlobalOfflineConfig.CachePollInterval == 0 {
 		c.GlobalOfflineConfig.CachePollInterval = DefaultCachePollInterval
@@ -404,19 +575,11 @@ func (c *Config) fixupDefaults() {
 }
 
 func (c *Config) fixupOffline() {
-	if !c.GlobalOfflineConfig.Enabled {
-		return
-	}
 	for _, sdk := range c.SDKs {
 		if sdk == nil {
 			continue
 		}
-		if !sdk.Offline.Enabled {
-			sdk.Offline.Enabled = true
-			sdk.Offline.UseCache = true
-			sdk.Offline.CachePollInterval = c.GlobalOfflineConfig.CachePollInterval
-			sdk.Offline.Log = c.GlobalOfflineConfig.Log
-		}
+		sdk.fixupOffline(&c.GlobalOfflineConfig)
 	}
 }
 
@@ -431,12 +594,7 @@ func (c *Config) fixupLogLevels(defLevel string) {
 		if sdk == nil {
 			continue
 		}
-		if sdk.Log.GetLevel() == log.None {
-			sdk.Log.Level = defLevel
-		}
-		if sdk.Offline.Log.GetLevel() == log.None {
-			sdk.Offline.Log.Level = defLevel
-		}
+		sdk.fixupLogLevels(defLevel)
 	}
 	if c.Http.Log.GetLevel() == log.None {
 		c.Http.Log.Level = defLevel
@@ -452,6 +610,51 @@ func (c *Config) fixupLogLevels(defLevel string) {
 	}
 }
 
+func (s *SDKConfig) fixupDefaults() {
+	if s.WebhookSignatureValidFor == 0 {
+		s.WebhookSignatureValidFor = DefaultWebhookSignatureValidFor
+	}
+	if s.PollInterval == 0 {
+		s.PollInterval = DefaultSdkPollInterval
+	}
+	if s.Offline.Local.PollInterval == 0 {
+		s.Offline.Local.PollInterval = DefaultCachePollInterval
+	}
+	if s.Offline.CachePollInterval == 0 {
+		s.Offline.CachePollInterval = DefaultCachePollInterval
+	}
+	if s.Offline.Upstream.MaxReconnectDelay == 0 {
+		s.Offline.Upstream.MaxReconnectDelay = DefaultUpstreamMaxReconnectDelay
+	}
+	if s.Offline.Upstream.ReadTimeout == 0 {
+		s.Offline.Upstream.ReadTimeout = DefaultUpstreamReadTimeout
+	}
+	if s.Overrides.IsSet() && s.Overrides.Behavior == "" {
+		s.Overrides.Behavior = OverrideLocalOverRemote
+	}
+	if s.History.Size == 0 {
+		s.History.Size = DefaultHistorySize
+	}
+}
+
+func (s *SDKConfig) fixupOffline(g *GlobalOfflineConfig) {
+	if g.Enabled && !s.Offline.Enabled {
+		s.Offline.Enabled = true
+		s.Offline.UseCache = true
+		s.Offline.CachePollInterval = g.CachePollInterval
+		s.Offline.Log = g.Log
+	}
+}
+
+func (s *SDKConfig) fixupLogLevels(defLevel string) {
+	if s.Log.GetLevel() == log.None {
+		s.Log.Level = defLevel
+	}
+	if s.Offline.Log.GetLevel() == log.None {
+		s.Offline.Log.Level = defLevel
+	}
+}
+
 func (c *Config) fixupTlsMinVersions(defVersion float64) {
 	if _, ok := allowedTlsVersions[c.Tls.MinVersion]; !ok {
 		c.Tls.MinVersion = defVersion
@@ -474,6 +677,12 @@ func (c *Config) compileOriginRegexes() error {
 	if err := c.Http.Sse.CORS.compileRegexes(); err != nil {
 		return err
 	}
+	if err := c.Http.Status.CORS.compileRegexes(); err != nil {
+		return err
+	}
+	if err := c.Diag.Status.CORS.compileRegexes(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -518,13 +727,45 @@ func (k *KeepAliveConfig) ToParams() (keepalive.ServerParameters, bool) {
 }
 
 func (c *CacheConfig) IsSet() bool {
-	return c.Redis.Enabled || c.MongoDb.Enabled || c.DynamoDb.Enabled
+	return c.Redis.Enabled || c.MongoDb.Enabled || c.DynamoDb.Enabled || c.Sql.Enabled || c.Filesystem.Enabled
+}
+
+// IsBackendEnabled reports whether the cache backend with the given name (one of CacheBackends) is enabled.
+func (c *CacheConfig) IsBackendEnabled(name string) bool {
+	switch name {
+	case CacheRedis:
+		return c.Redis.Enabled
+	case CacheMongoDb:
+		return c.MongoDb.Enabled
+	case CacheDynamoDb:
+		return c.DynamoDb.Enabled
+	case CacheSql:
+		return c.Sql.Enabled
+	case CacheFilesystem:
+		return c.Filesystem.Enabled
+	}
+	return false
+}
+
+// Topology returns how the Redis client connects to the given addresses, following the rules of redis.NewUniversalClient.
+func (r *RedisConfig) Topology() string {
+	if r.MasterName != "" {
+		return RedisSentinel
+	}
+	if len(r.Addresses) > 1 || r.ClusterMode {
+		return RedisCluster
+	}
+	return RedisStandalone
 }
 
 func (a *ProfileConfig) IsSet() bool {
 	return a.Key != ""
 }
 
+func (o *OverrideConfig) IsSet() bool {
+	return o.FilePath != "" || len(o.Values) > 0
+}
+
 func (d *DiagConfig) IsMetricsEnabled() bool {
 	return d.Enabled && d.Metrics.Enabled
 }
@@ -541,8 +782,20 @@ func (d *DiagConfig) IsPrometheusExporterEnabled() bool {
 	return d.IsMetricsEnabled() && d.Metrics.Prometheus.Enabled
 }
 
+func (d *DiagConfig) IsHealthEnabled() bool {
+	return d.Enabled && d.Health.Enabled
+}
+
+func (d *DiagConfig) IsAdminEnabled() bool {
+	return d.Enabled && d.Admin.Enabled
+}
+
+func (h *HttpConfig) IsOverrideApiEnabled() bool {
+	return h.Enabled && h.Api.Enabled && len(h.Api.Overrides.AuthHeaders) > 0
+}
+
 func (d *DiagConfig) ShouldRunDiagServer() bool {
-	return d.Enabled && (d.IsPrometheusExporterEnabled() || d.Status.Enabled)
+	return d.Enabled && (d.IsPrometheusExporterEnabled() || d.Status.Enabled || d.Health.Enabled || d.Admin.Enabled)
 }
 
 func (t *TlsConfig) LoadTlsOptions() (*tls.Config, error) {
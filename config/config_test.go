@@ -26,6 +26,9 @@ func TestConfig_Defaults(t *testing.T) {
 	assert.Equal(t, 8051, conf.Diag.Port)
 	assert.True(t, conf.Diag.Enabled)
 	assert.True(t, conf.Diag.Status.Enabled)
+	assert.True(t, conf.Diag.Status.MaskSdkKeys)
+	assert.True(t, conf.Diag.Health.Enabled)
+	assert.Equal(t, DegradedReady, conf.Diag.Health.DegradedPolicy)
 	assert.True(t, conf.Diag.Metrics.Enabled)
 	assert.True(t, conf.Diag.Metrics.Prometheus.Enabled)
 	assert.Equal(t, "http", conf.Diag.Metrics.Otlp.Protocol)
@@ -47,6 +50,7 @@ func TestConfig_Defaults(t *testing.T) {
 	assert.True(t, conf.Http.Webhook.Enabled)
 
 	assert.False(t, conf.Http.Status.Enabled)
+	assert.True(t, conf.Http.Status.MaskSdkKeys)
 
 	assert.False(t, conf.GlobalOfflineConfig.Enabled)
 	assert.Equal(t, 5, conf.GlobalOfflineConfig.CachePollInterval)
@@ -54,11 +58,20 @@ func TestConfig_Defaults(t *testing.T) {
 	assert.Equal(t, 0, conf.Cache.Redis.DB)
 	assert.Equal(t, "localhost:6379", conf.Cache.Redis.Addresses[0])
 
+	assert.Equal(t, 5000, conf.Cache.OperationTimeoutMs)
+	assert.Equal(t, 30, conf.Cache.PingInterval)
+	assert.False(t, conf.Cache.CircuitBreaker.Enabled)
+	assert.Equal(t, 5, conf.Cache.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, 30, conf.Cache.CircuitBreaker.OpenDuration)
+
 	assert.Equal(t, "configcat_proxy", conf.Cache.MongoDb.Database)
 	assert.Equal(t, "cache", conf.Cache.MongoDb.Collection)
 
 	assert.Equal(t, "configcat_proxy_cache", conf.Cache.DynamoDb.Table)
 
+	assert.Equal(t, "configcat_proxy_cache", conf.Cache.Sql.Table)
+	assert.True(t, conf.Cache.Sql.AutoMigrate)
+
 	assert.Equal(t, 1.2, conf.Tls.MinVersion)
 	assert.Equal(t, 1.2, conf.Cache.Redis.Tls.MinVersion)
 	assert.Equal(t, 1.2, conf.Cache.MongoDb.Tls.MinVersion)
@@ -199,6 +212,26 @@ sdks:
         poll_interval: 100
       use_cache: true
       cache_poll_interval: 200
+      upstream:
+        url: "http://upstream"
+        sdk_id: "central"
+        max_reconnect_delay: 30
+        read_timeout: 45
+        headers:
+          X-SSE-KEY: "upstream-auth"
+    overrides:
+      behavior: "remote_over_local"
+      values:
+        flag1: true
+        flag2: 5
+        flag3: 1.5
+        flag4: "str"
+    history:
+      size: 20
+      use_cache: true
+    init:
+      timeout: 5
+      serve_defaults: true
 `, func(file string) {
 		conf, err := LoadConfigFromFileAndEnvironment(file)
 		require.NoError(t, err)
@@ -219,6 +252,17 @@ sdks:
 		assert.True(t, conf.SDKs["test_sdk"].Offline.UseCache)
 		assert.Equal(t, 200, conf.SDKs["test_sdk"].Offline.CachePollInterval)
 		assert.Equal(t, 200, conf.SDKs["test_sdk"].Offline.CachePollInterval)
+		assert.Equal(t, "http://upstream", conf.SDKs["test_sdk"].Offline.Upstream.Url)
+		assert.Equal(t, "central", conf.SDKs["test_sdk"].Offline.Upstream.SdkId)
+		assert.Equal(t, 30, conf.SDKs["test_sdk"].Offline.Upstream.MaxReconnectDelay)
+		assert.Equal(t, 45, conf.SDKs["test_sdk"].Offline.Upstream.ReadTimeout)
+		assert.Equal(t, "upstream-auth", conf.SDKs["test_sdk"].Offline.Upstream.Headers["X-SSE-KEY"])
+		assert.Equal(t, "remote_over_local", conf.SDKs["test_sdk"].Overrides.Behavior)
+		assert.Equal(t, map[string]interface{}{"flag1": true, "flag2": 5, "flag3": 1.5, "flag4": "str"}, conf.SDKs["test_sdk"].Overrides.Values)
+		assert.Equal(t, 20, conf.SDKs["test_sdk"].History.Size)
+		assert.True(t, conf.SDKs["test_sdk"].History.UseCache)
+		assert.Equal(t, 5, conf.SDKs["test_sdk"].Init.Timeout)
+		assert.True(t, conf.SDKs["test_sdk"].Init.ServeDefaults)
 
 		assert.Equal(t, "attr_value1", conf.SDKs["test_sdk"].DefaultAttrs["attr_1"])
 		assert.Equal(t, "attr_value2", conf.SDKs["test_sdk"].DefaultAttrs["attr2"])
@@ -241,6 +285,8 @@ profile:
     level: "debug"
   sdks:
     base_url: "https://sdk-base.com"
+    init:
+      timeout: 5
     log:
       level: "debug"
 `, func(file string) {
@@ -252,6 +298,7 @@ profile:
 		assert.Equal(t, "https://base.com", conf.Profile.BaseUrl)
 		assert.Equal(t, "https://sdk-base.com", conf.Profile.SDKs.BaseUrl)
 		assert.Equal(t, log.Debug, conf.Profile.SDKs.Log.GetLevel())
+		assert.Equal(t, 5, conf.Profile.SDKs.Init.Timeout)
 		assert.Equal(t, 300, conf.Profile.PollInterval)
 		assert.Equal(t, "key", conf.Profile.WebhookSigningKey)
 		assert.Equal(t, 600, conf.Profile.WebhookSignatureValidFor)
@@ -350,6 +397,17 @@ cache:
     password: "pass"
     user: "user"
     addresses: ["addr1", "addr2"]
+    master_name: "master"
+    sentinel_user: "s_user"
+    sentinel_password: "s_pass"
+    read_only: true
+    route_by_latency: true
+    route_randomly: true
+    pool_size: 20
+    min_idle_conns: 5
+    dial_timeout_ms: 500
+    read_timeout_ms: 200
+    write_timeout_ms: 300
     tls: 
       enabled: true
       min_version: 1.1
@@ -369,6 +427,19 @@ cache:
 		assert.Equal(t, "user", conf.Cache.Redis.User)
 		assert.Equal(t, "addr1", conf.Cache.Redis.Addresses[0])
 		assert.Equal(t, "addr2", conf.Cache.Redis.Addresses[1])
+		assert.Equal(t, "master", conf.Cache.Redis.MasterName)
+		assert.Equal(t, "s_user", conf.Cache.Redis.SentinelUser)
+		assert.Equal(t, "s_pass", conf.Cache.Redis.SentinelPassword)
+		assert.False(t, conf.Cache.Redis.ClusterMode)
+		assert.True(t, conf.Cache.Redis.ReadOnly)
+		assert.True(t, conf.Cache.Redis.RouteByLatency)
+		assert.True(t, conf.Cache.Redis.RouteRandomly)
+		assert.Equal(t, 20, conf.Cache.Redis.PoolSize)
+		assert.Equal(t, 5, conf.Cache.Redis.MinIdleConns)
+		assert.Equal(t, 500, conf.Cache.Redis.DialTimeoutMs)
+		assert.Equal(t, 200, conf.Cache.Redis.ReadTimeoutMs)
+		assert.Equal(t, 300, conf.Cache.Redis.WriteTimeoutMs)
+		assert.Equal(t, RedisSentinel, conf.Cache.Redis.Topology())
 		assert.True(t, conf.Cache.Redis.Tls.Enabled)
 		assert.Equal(t, tls.VersionTLS11, int(conf.Cache.Redis.Tls.GetVersion()))
 		assert.Equal(t, "serv", conf.Cache.Redis.Tls.ServerName)
@@ -379,6 +450,13 @@ cache:
 	})
 }
 
+func TestRedisConfig_Topology(t *testing.T) {
+	assert.Equal(t, RedisStandalone, (&RedisConfig{Addresses: []string{"addr1"}}).Topology())
+	assert.Equal(t, RedisCluster, (&RedisConfig{Addresses: []string{"addr1", "addr2"}}).Topology())
+	assert.Equal(t, RedisCluster, (&RedisConfig{Addresses: []string{"addr1"}, ClusterMode: true}).Topology())
+	assert.Equal(t, RedisSentinel, (&RedisConfig{Addresses: []string{"addr1", "addr2"}, MasterName: "master"}).Topology())
+}
+
 func TestMongoDbConfig_YAML(t *testing.T) {
 	testutils.UseTempFile(`
 cache:
@@ -431,6 +509,111 @@ cache:
 	})
 }
 
+func TestSqlConfig_YAML(t *testing.T) {
+	testutils.UseTempFile(`
+cache:
+  sql:
+    enabled: true
+    driver: "postgres"
+    dsn: "dsn"
+    table: "tbl"
+    auto_migrate: false
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+
+		assert.True(t, conf.Cache.Sql.Enabled)
+		assert.Equal(t, "postgres", conf.Cache.Sql.Driver)
+		assert.Equal(t, "dsn", conf.Cache.Sql.DSN)
+		assert.Equal(t, "tbl", conf.Cache.Sql.Table)
+		assert.False(t, conf.Cache.Sql.AutoMigrate)
+	})
+}
+
+func TestFilesystemConfig_YAML(t *testing.T) {
+	testutils.UseTempFile(`
+cache:
+  filesystem:
+    enabled: true
+    path: "/var/cache/proxy"
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+
+		assert.True(t, conf.Cache.Filesystem.Enabled)
+		assert.Equal(t, "/var/cache/proxy", conf.Cache.Filesystem.Path)
+	})
+}
+
+func TestEncryptionConfig_YAML(t *testing.T) {
+	testutils.UseTempFile(`
+cache:
+  encryption:
+    enabled: true
+    key_file: "/etc/proxy/keys"
+    active_key_id: "k2"
+    allow_plaintext_read: true
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+
+		assert.True(t, conf.Cache.Encryption.Enabled)
+		assert.Equal(t, "/etc/proxy/keys", conf.Cache.Encryption.KeyFile)
+		assert.Equal(t, "k2", conf.Cache.Encryption.ActiveKeyId)
+		assert.True(t, conf.Cache.Encryption.AllowPlaintextRead)
+	})
+}
+
+func TestCacheChainConfig_YAML(t *testing.T) {
+	testutils.UseTempFile(`
+cache:
+  key_prefix: "team-a:"
+  ttl: 3600
+  operation_timeout_ms: 1000
+  ping_interval: 10
+  circuit_breaker:
+    enabled: true
+    failure_threshold: 3
+    open_duration: 20
+  chain: ["redis", "filesystem"]
+  redis:
+    enabled: true
+  filesystem:
+    enabled: true
+    path: "/var/cache/proxy"
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+
+		assert.Equal(t, "team-a:", conf.Cache.KeyPrefix)
+		assert.Equal(t, 3600, conf.Cache.Ttl)
+		assert.Equal(t, 1000, conf.Cache.OperationTimeoutMs)
+		assert.Equal(t, 10, conf.Cache.PingInterval)
+		assert.True(t, conf.Cache.CircuitBreaker.Enabled)
+		assert.Equal(t, 3, conf.Cache.CircuitBreaker.FailureThreshold)
+		assert.Equal(t, 20, conf.Cache.CircuitBreaker.OpenDuration)
+		assert.Equal(t, []string{"redis", "filesystem"}, conf.Cache.Chain)
+		assert.True(t, conf.Cache.IsBackendEnabled(CacheRedis))
+		assert.True(t, conf.Cache.IsBackendEnabled(CacheFilesystem))
+		assert.False(t, conf.Cache.IsBackendEnabled(CacheSql))
+		assert.False(t, conf.Cache.IsBackendEnabled("unknown"))
+	})
+}
+
+func TestLocalSnapshotConfig_YAML(t *testing.T) {
+	testutils.UseTempFile(`
+local_snapshot:
+  enabled: true
+  path: "/var/lib/proxy"
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+
+		assert.True(t, conf.LocalSnapshot.Enabled)
+		assert.Equal(t, "/var/lib/proxy", conf.LocalSnapshot.Path)
+	})
+}
+
 func TestGlobalOfflineConfig_YAML(t *testing.T) {
 	testutils.UseTempFile(`
 offline:
@@ -492,6 +675,17 @@ diag:
   port: 8091
   status:
     enabled: false
+    mask_sdk_keys: false
+    auth_headers:
+      X-STATUS-KEY: "status-secret"
+    auth:
+      user: "minnie"
+      password: "status-pass"
+  health:
+    enabled: false
+    sdks: ["sdk1", "sdk2"]
+    require_cache: true
+    degraded_policy: "not_ready"
   metrics:
     enabled: false
     prometheus: 
@@ -506,7 +700,13 @@ diag:
       enabled: true
       endpoint: "http://localhost:4317"
       protocol: "grpc"
-    
+  admin:
+    enabled: true
+    auth_headers:
+      X-API-KEY: "secret"
+    auth:
+      user: "mickey"
+      password: "pass"
 `, func(file string) {
 		conf, err := LoadConfigFromFileAndEnvironment(file)
 		require.NoError(t, err)
@@ -514,6 +714,14 @@ diag:
 		assert.False(t, conf.Diag.Enabled)
 		assert.Equal(t, 8091, conf.Diag.Port)
 		assert.False(t, conf.Diag.Status.Enabled)
+		assert.False(t, conf.Diag.Status.MaskSdkKeys)
+		assert.Equal(t, "status-secret", conf.Diag.Status.AuthHeaders["X-STATUS-KEY"])
+		assert.Equal(t, "minnie", conf.Diag.Status.Auth.User)
+		assert.Equal(t, "status-pass", conf.Diag.Status.Auth.Password)
+		assert.False(t, conf.Diag.Health.Enabled)
+		assert.Equal(t, []string{"sdk1", "sdk2"}, conf.Diag.Health.SDKs)
+		assert.True(t, conf.Diag.Health.RequireCache)
+		assert.Equal(t, DegradedNotReady, conf.Diag.Health.DegradedPolicy)
 		assert.False(t, conf.Diag.Metrics.Enabled)
 		assert.False(t, conf.Diag.Metrics.Prometheus.Enabled)
 		assert.True(t, conf.Diag.Metrics.Otlp.Enabled)
@@ -523,6 +731,10 @@ diag:
 		assert.True(t, conf.Diag.Traces.Otlp.Enabled)
 		assert.Equal(t, "grpc", conf.Diag.Traces.Otlp.Protocol)
 		assert.Equal(t, "http://localhost:4317", conf.Diag.Traces.Otlp.Endpoint)
+		assert.True(t, conf.Diag.Admin.Enabled)
+		assert.Equal(t, "secret", conf.Diag.Admin.AuthHeaders["X-API-KEY"])
+		assert.Equal(t, "mickey", conf.Diag.Admin.Auth.User)
+		assert.Equal(t, "pass", conf.Diag.Admin.Auth.Password)
 	})
 }
 
@@ -556,6 +768,9 @@ http:
     auth_headers:
       X-API-KEY1: "api-auth1"
       X-API-KEY2: "api-auth2"
+    overrides:
+      auth_headers:
+        X-OVERRIDES-KEY: "overrides-auth"
     cors: 
       enabled: true
       allowed_origins:
@@ -582,8 +797,17 @@ http:
     headers:
       CUSTOM-HEADER1: "sse-val1"
       CUSTOM-HEADER2: "sse-val2"
+    auth_headers:
+      X-SSE-KEY: "sse-auth"
   status:
     enabled: true
+    mask_sdk_keys: false
+    auth_headers:
+      X-STATUS-KEY: "status-auth"
+    cors:
+      enabled: true
+      allowed_origins:
+        - https://example1.com
 `, func(file string) {
 		conf, err := LoadConfigFromFileAndEnvironment(file)
 		require.NoError(t, err)
@@ -610,6 +834,7 @@ http:
 		assert.Equal(t, "sse-val1", conf.Http.Sse.Headers["CUSTOM-HEADER1"])
 		assert.Equal(t, "sse-val2", conf.Http.Sse.Headers["CUSTOM-HEADER2"])
 		assert.Equal(t, 5, conf.Http.Sse.HeartBeatInterval)
+		assert.Equal(t, "sse-auth", conf.Http.Sse.AuthHeaders["X-SSE-KEY"])
 
 		assert.True(t, conf.Http.Api.Enabled)
 		assert.True(t, conf.Http.Api.CORS.Enabled)
@@ -619,6 +844,7 @@ http:
 		assert.Equal(t, "api-val2", conf.Http.Api.Headers["CUSTOM-HEADER2"])
 		assert.Equal(t, "api-auth1", conf.Http.Api.AuthHeaders["X-API-KEY1"])
 		assert.Equal(t, "api-auth2", conf.Http.Api.AuthHeaders["X-API-KEY2"])
+		assert.Equal(t, "overrides-auth", conf.Http.Api.Overrides.AuthHeaders["X-OVERRIDES-KEY"])
 
 		assert.True(t, conf.Http.OFREP.Enabled)
 		assert.True(t, conf.Http.OFREP.CORS.Enabled)
@@ -630,6 +856,10 @@ http:
 		assert.Equal(t, "ofrep-auth2", conf.Http.OFREP.AuthHeaders["X-API-KEY2"])
 
 		assert.True(t, conf.Http.Status.Enabled)
+		assert.False(t, conf.Http.Status.MaskSdkKeys)
+		assert.Equal(t, "status-auth", conf.Http.Status.AuthHeaders["X-STATUS-KEY"])
+		assert.True(t, conf.Http.Status.CORS.Enabled)
+		assert.Equal(t, []string{"https://example1.com"}, conf.Http.Status.CORS.AllowedOrigins)
 	})
 }
 
@@ -881,6 +1111,9 @@ func TestDiagConfig(t *testing.T) {
 		{
 			conf: &DiagConfig{Enabled: true, Status: StatusConfig{Enabled: true}}, expM: false, expP: false, expD: true, expS: true,
 		},
+		{
+			conf: &DiagConfig{Enabled: true, Health: HealthConfig{Enabled: true}}, expM: false, expP: false, expD: true, expS: false,
+		},
 		{
 			conf: &DiagConfig{Enabled: true, Metrics: MetricsConfig{Prometheus: PrometheusExporterConfig{Enabled: true}}}, expM: false, expP: false, expD: false, expS: false,
 		},
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/configcat/configcat-proxy/model"
 )
@@ -21,6 +23,13 @@ func (e envVarReadError) Error() string {
 
 var envPrefix = "CONFIGCAT"
 
+// envKeysRead collects the variables looked up during loadEnv, it's used to detect unknown CONFIGCAT_ prefixed variables
+var (
+	envLoadMu   sync.Mutex
+	envKeysRead map[string]struct{}
+)
+
+var toString = func(s string) (string, error) { return s, nil }
 var toInt = func(s string) (int, error) { return strconv.Atoi(s) }
 var toBool = func(s string) (bool, error) { return strconv.ParseBool(s) }
 var toFloat = func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
@@ -54,9 +63,53 @@ var toUserAttrs = func(s string) (model.UserAttrs, error) {
 	return r, nil
 }
 
+// toOverrideValues keeps whole numbers as int, so they match the type YAML decoding produces.
+var toOverrideValues = func(s string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	var r map[string]interface{}
+	if err := decoder.Decode(&r); err != nil {
+		return nil, err
+	}
+	for key, value := range r {
+		if num, ok := value.(json.Number); ok {
+			if i, err := num.Int64(); err == nil {
+				r[key] = int(i)
+			} else if f, err := num.Float64(); err == nil {
+				r[key] = f
+			}
+		}
+	}
+	return r, nil
+}
+
 func (c *Config) loadEnv() error {
+	envLoadMu.Lock()
+	defer envLoadMu.Unlock()
+
+	envKeysRead = make(map[string]struct{})
+	defer func() { envKeysRead = nil }()
+
+	if err := c.loadEnvVars(); err != nil {
+		return err
+	}
+	c.unknownEnvVars = nil
+	for _, env := range os.Environ() {
+		key, _, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(key, envPrefix+"_") {
+			continue
+		}
+		if _, ok := envKeysRead[key]; !ok {
+			c.unknownEnvVars = append(c.unknownEnvVars, key)
+		}
+	}
+	sort.Strings(c.unknownEnvVars)
+	return nil
+}
+
+func (c *Config) loadEnvVars() error {
 	var sdks map[string]string
-	if err := readEnv(envPrefix, "SDKS", &sdks, toStringMap); err != nil {
+	if err := readEnvSecret(envPrefix, "SDKS", &sdks, toStringMap); err != nil {
 		return err
 	}
 	if c.SDKs == nil {
@@ -97,6 +150,9 @@ func (c *Config) loadEnv() error {
 	if err := c.GlobalOfflineConfig.loadEnv(envPrefix); err != nil {
 		return err
 	}
+	if err := c.LocalSnapshot.loadEnv(envPrefix); err != nil {
+		return err
+	}
 
 	return readEnv(envPrefix, "DEFAULT_USER_ATTRIBUTES", &c.DefaultAttrs, toUserAttrs)
 }
@@ -104,7 +160,9 @@ func (c *Config) loadEnv() error {
 func (s *SDKConfig) loadEnv(prefix string) error {
 	readEnvString(prefix, "BASE_URL", &s.BaseUrl)
 	readEnvString(prefix, "DATA_GOVERNANCE", &s.DataGovernance)
-	readEnvString(prefix, "WEBHOOK_SIGNING_KEY", &s.WebhookSigningKey)
+	if err := readEnvSecret(prefix, "WEBHOOK_SIGNING_KEY", &s.WebhookSigningKey, toString); err != nil {
+		return err
+	}
 	if err := readEnv(prefix, "WEBHOOK_SIGNATURE_VALID_FOR", &s.WebhookSignatureValidFor, toInt); err != nil {
 		return err
 	}
@@ -117,15 +175,53 @@ func (s *SDKConfig) loadEnv(prefix string) error {
 	if err := s.Offline.loadEnv(prefix); err != nil {
 		return err
 	}
+	if err := s.Overrides.loadEnv(prefix); err != nil {
+		return err
+	}
+	if err := s.History.loadEnv(prefix); err != nil {
+		return err
+	}
+	if err := s.Init.loadEnv(prefix); err != nil {
+		return err
+	}
 	return s.Log.loadEnv(prefix)
 }
 
+func (i *InitConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "INIT")
+	if err := readEnv(prefix, "TIMEOUT", &i.Timeout, toInt); err != nil {
+		return err
+	}
+	return readEnv(prefix, "SERVE_DEFAULTS", &i.ServeDefaults, toBool)
+}
+
+func (h *HistoryConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "HISTORY")
+	if err := readEnv(prefix, "SIZE", &h.Size, toInt); err != nil {
+		return err
+	}
+	return readEnv(prefix, "USE_CACHE", &h.UseCache, toBool)
+}
+
+func (o *OverrideConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "OVERRIDES")
+	readEnvString(prefix, "BEHAVIOR", &o.Behavior)
+	readEnvString(prefix, "FILE_PATH", &o.FilePath)
+	return readEnv(prefix, "VALUES", &o.Values, toOverrideValues)
+}
+
 func (a *ProfileConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "PROFILE")
-	readEnvString(prefix, "KEY", &a.Key)
-	readEnvString(prefix, "SECRET", &a.Secret)
+	if err := readEnvSecret(prefix, "KEY", &a.Key, toString); err != nil {
+		return err
+	}
+	if err := readEnvSecret(prefix, "SECRET", &a.Secret, toString); err != nil {
+		return err
+	}
 	readEnvString(prefix, "BASE_URL", &a.BaseUrl)
-	readEnvString(prefix, "WEBHOOK_SIGNING_KEY", &a.WebhookSigningKey)
+	if err := readEnvSecret(prefix, "WEBHOOK_SIGNING_KEY", &a.WebhookSigningKey, toString); err != nil {
+		return err
+	}
 	if err := readEnv(prefix, "WEBHOOK_SIGNATURE_VALID_FOR", &a.WebhookSignatureValidFor, toInt); err != nil {
 		return err
 	}
@@ -141,6 +237,9 @@ func (a *ProfileConfig) loadEnv(prefix string) error {
 func (p *ProfileSDKConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "SDKS")
 	readEnvString(prefix, "BASE_URL", &p.BaseUrl)
+	if err := p.Init.loadEnv(prefix); err != nil {
+		return err
+	}
 	return p.Log.loadEnv(prefix)
 }
 
@@ -212,19 +311,52 @@ func (k *KeepAliveConfig) loadEnv(prefix string) error {
 
 func (h *HttpProxyConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "HTTP_PROXY")
-	readEnvString(prefix, "URL", &h.Url)
+	return readEnvSecret(prefix, "URL", &h.Url, toString)
+}
+
+func (l *LocalSnapshotConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "LOCAL_SNAPSHOT")
+	if err := readEnv(prefix, "ENABLED", &l.Enabled, toBool); err != nil {
+		return err
+	}
+	readEnvString(prefix, "PATH", &l.Path)
 	return nil
 }
 
 func (c *CacheConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "CACHE")
+	readEnvString(prefix, "KEY_PREFIX", &c.KeyPrefix)
+	if err := readEnv(prefix, "TTL", &c.Ttl, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "OPERATION_TIMEOUT_MS", &c.OperationTimeoutMs, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "PING_INTERVAL", &c.PingInterval, toInt); err != nil {
+		return err
+	}
+	if err := c.CircuitBreaker.loadEnv(prefix); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "CHAIN", &c.Chain, toStringSlice); err != nil {
+		return err
+	}
 	if err := c.Redis.loadEnv(prefix); err != nil {
 		return err
 	}
 	if err := c.MongoDb.loadEnv(prefix); err != nil {
 		return err
 	}
-	return c.DynamoDb.loadEnv(prefix)
+	if err := c.DynamoDb.loadEnv(prefix); err != nil {
+		return err
+	}
+	if err := c.Sql.loadEnv(prefix); err != nil {
+		return err
+	}
+	if err := c.Filesystem.loadEnv(prefix); err != nil {
+		return err
+	}
+	return c.Encryption.loadEnv(prefix)
 }
 
 func (g *GlobalOfflineConfig) loadEnv(prefix string) error {
@@ -252,9 +384,28 @@ func (o *OfflineConfig) loadEnv(prefix string) error {
 	if err := o.Local.loadEnv(prefix); err != nil {
 		return err
 	}
+	if err := o.Upstream.loadEnv(prefix); err != nil {
+		return err
+	}
 	return o.Log.loadEnv(prefix)
 }
 
+func (u *UpstreamConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "UPSTREAM")
+	if err := readEnv(prefix, "MAX_RECONNECT_DELAY", &u.MaxReconnectDelay, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "READ_TIMEOUT", &u.ReadTimeout, toInt); err != nil {
+		return err
+	}
+	if err := readEnvSecret(prefix, "HEADERS", &u.Headers, toStringMap); err != nil {
+		return err
+	}
+	readEnvString(prefix, "URL", &u.Url)
+	readEnvString(prefix, "SDK_ID", &u.SdkId)
+	return nil
+}
+
 func (l *LocalConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "LOCAL")
 	if err := readEnv(prefix, "POLLING", &l.Polling, toBool); err != nil {
@@ -267,9 +418,22 @@ func (l *LocalConfig) loadEnv(prefix string) error {
 	return nil
 }
 
+func (c *CircuitBreakerConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "CIRCUIT_BREAKER")
+	if err := readEnv(prefix, "ENABLED", &c.Enabled, toBool); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "FAILURE_THRESHOLD", &c.FailureThreshold, toInt); err != nil {
+		return err
+	}
+	return readEnv(prefix, "OPEN_DURATION", &c.OpenDuration, toInt)
+}
+
 func (r *RedisConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "REDIS")
-	readEnvString(prefix, "PASSWORD", &r.Password)
+	if err := readEnvSecret(prefix, "PASSWORD", &r.Password, toString); err != nil {
+		return err
+	}
 	readEnvString(prefix, "USER", &r.User)
 	if err := readEnv(prefix, "DB", &r.DB, toInt); err != nil {
 		return err
@@ -280,12 +444,46 @@ func (r *RedisConfig) loadEnv(prefix string) error {
 	if err := readEnv(prefix, "ADDRESSES", &r.Addresses, toStringSlice); err != nil {
 		return err
 	}
+	readEnvString(prefix, "MASTER_NAME", &r.MasterName)
+	readEnvString(prefix, "SENTINEL_USER", &r.SentinelUser)
+	if err := readEnvSecret(prefix, "SENTINEL_PASSWORD", &r.SentinelPassword, toString); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "CLUSTER_MODE", &r.ClusterMode, toBool); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "READ_ONLY", &r.ReadOnly, toBool); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "ROUTE_BY_LATENCY", &r.RouteByLatency, toBool); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "ROUTE_RANDOMLY", &r.RouteRandomly, toBool); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "POOL_SIZE", &r.PoolSize, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "MIN_IDLE_CONNS", &r.MinIdleConns, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "DIAL_TIMEOUT_MS", &r.DialTimeoutMs, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "READ_TIMEOUT_MS", &r.ReadTimeoutMs, toInt); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "WRITE_TIMEOUT_MS", &r.WriteTimeoutMs, toInt); err != nil {
+		return err
+	}
 	return r.Tls.loadEnv(prefix)
 }
 
 func (m *MongoDbConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "MONGODB")
-	readEnvString(prefix, "URL", &m.Url)
+	if err := readEnvSecret(prefix, "URL", &m.Url, toString); err != nil {
+		return err
+	}
 	readEnvString(prefix, "DATABASE", &m.Database)
 	readEnvString(prefix, "COLLECTION", &m.Collection)
 	if err := readEnv(prefix, "ENABLED", &m.Enabled, toBool); err != nil {
@@ -301,6 +499,35 @@ func (d *DynamoDbConfig) loadEnv(prefix string) error {
 	return readEnv(prefix, "ENABLED", &d.Enabled, toBool)
 }
 
+func (s *SqlConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "SQL")
+	if err := readEnvSecret(prefix, "DSN", &s.DSN, toString); err != nil {
+		return err
+	}
+	readEnvString(prefix, "DRIVER", &s.Driver)
+	readEnvString(prefix, "TABLE", &s.Table)
+	if err := readEnv(prefix, "AUTO_MIGRATE", &s.AutoMigrate, toBool); err != nil {
+		return err
+	}
+	return readEnv(prefix, "ENABLED", &s.Enabled, toBool)
+}
+
+func (f *FilesystemConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "FILESYSTEM")
+	readEnvString(prefix, "PATH", &f.Path)
+	return readEnv(prefix, "ENABLED", &f.Enabled, toBool)
+}
+
+func (e *EncryptionConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "ENCRYPTION")
+	readEnvString(prefix, "KEY_FILE", &e.KeyFile)
+	readEnvString(prefix, "ACTIVE_KEY_ID", &e.ActiveKeyId)
+	if err := readEnv(prefix, "ALLOW_PLAINTEXT_READ", &e.AllowPlaintextRead, toBool); err != nil {
+		return err
+	}
+	return readEnv(prefix, "ENABLED", &e.Enabled, toBool)
+}
+
 func (s *SseConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "SSE")
 	if err := readEnv(prefix, "ENABLED", &s.Enabled, toBool); err != nil {
@@ -309,6 +536,9 @@ func (s *SseConfig) loadEnv(prefix string) error {
 	if err := readEnv(prefix, "HEADERS", &s.Headers, toStringMap); err != nil {
 		return err
 	}
+	if err := readEnvSecret(prefix, "AUTH_HEADERS", &s.AuthHeaders, toStringMap); err != nil {
+		return err
+	}
 	if err := readEnv(prefix, "HEARTBEAT_INTERVAL", &s.HeartBeatInterval, toInt); err != nil {
 		return err
 	}
@@ -326,12 +556,20 @@ func (a *ApiConfig) loadEnv(prefix string) error {
 	if err := readEnv(prefix, "HEADERS", &a.Headers, toStringMap); err != nil {
 		return err
 	}
-	if err := readEnv(prefix, "AUTH_HEADERS", &a.AuthHeaders, toStringMap); err != nil {
+	if err := readEnvSecret(prefix, "AUTH_HEADERS", &a.AuthHeaders, toStringMap); err != nil {
+		return err
+	}
+	if err := a.Overrides.loadEnv(prefix); err != nil {
 		return err
 	}
 	return a.CORS.loadEnv(prefix)
 }
 
+func (a *ApiOverridesConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "OVERRIDES")
+	return readEnvSecret(prefix, "AUTH_HEADERS", &a.AuthHeaders, toStringMap)
+}
+
 func (o *OFREPConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "OFREP")
 	if err := readEnv(prefix, "ENABLED", &o.Enabled, toBool); err != nil {
@@ -340,7 +578,7 @@ func (o *OFREPConfig) loadEnv(prefix string) error {
 	if err := readEnv(prefix, "HEADERS", &o.Headers, toStringMap); err != nil {
 		return err
 	}
-	if err := readEnv(prefix, "AUTH_HEADERS", &o.AuthHeaders, toStringMap); err != nil {
+	if err := readEnvSecret(prefix, "AUTH_HEADERS", &o.AuthHeaders, toStringMap); err != nil {
 		return err
 	}
 	return o.CORS.loadEnv(prefix)
@@ -362,7 +600,7 @@ func (w *WebhookConfig) loadEnv(prefix string) error {
 	if err := readEnv(prefix, "ENABLED", &w.Enabled, toBool); err != nil {
 		return err
 	}
-	if err := readEnv(prefix, "AUTH_HEADERS", &w.AuthHeaders, toStringMap); err != nil {
+	if err := readEnvSecret(prefix, "AUTH_HEADERS", &w.AuthHeaders, toStringMap); err != nil {
 		return err
 	}
 	return w.Auth.loadEnv(prefix)
@@ -371,8 +609,7 @@ func (w *WebhookConfig) loadEnv(prefix string) error {
 func (a *AuthConfig) loadEnv(prefix string) error {
 	prefix = concatPrefix(prefix, "AUTH")
 	readEnvString(prefix, "USER", &a.User)
-	readEnvString(prefix, "PASSWORD", &a.Password)
-	return nil
+	return readEnvSecret(prefix, "PASSWORD", &a.Password, toString)
 }
 
 func (c *CORSConfig) loadEnv(prefix string) error {
@@ -421,12 +658,18 @@ func (d *DiagConfig) loadEnv(prefix string) error {
 	if err := d.Status.loadEnv(prefix); err != nil {
 		return err
 	}
+	if err := d.Health.loadEnv(prefix); err != nil {
+		return err
+	}
 	if err := d.Metrics.loadEnv(prefix); err != nil {
 		return err
 	}
 	if err := d.Traces.loadEnv(prefix); err != nil {
 		return err
 	}
+	if err := d.Admin.loadEnv(prefix); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -478,11 +721,47 @@ func (s *StatusConfig) loadEnv(prefix string) error {
 	if err := readEnv(prefix, "ENABLED", &s.Enabled, toBool); err != nil {
 		return err
 	}
+	if err := readEnv(prefix, "MASK_SDK_KEYS", &s.MaskSdkKeys, toBool); err != nil {
+		return err
+	}
+	if err := readEnvSecret(prefix, "AUTH_HEADERS", &s.AuthHeaders, toStringMap); err != nil {
+		return err
+	}
+	if err := s.Auth.loadEnv(prefix); err != nil {
+		return err
+	}
+	return s.CORS.loadEnv(prefix)
+}
+
+func (h *HealthConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "HEALTH")
+	if err := readEnv(prefix, "ENABLED", &h.Enabled, toBool); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "SDKS", &h.SDKs, toStringSlice); err != nil {
+		return err
+	}
+	if err := readEnv(prefix, "REQUIRE_CACHE", &h.RequireCache, toBool); err != nil {
+		return err
+	}
+	readEnvString(prefix, "DEGRADED_POLICY", &h.DegradedPolicy)
 	return nil
 }
 
+func (a *AdminConfig) loadEnv(prefix string) error {
+	prefix = concatPrefix(prefix, "ADMIN")
+	if err := readEnv(prefix, "ENABLED", &a.Enabled, toBool); err != nil {
+		return err
+	}
+	if err := readEnvSecret(prefix, "AUTH_HEADERS", &a.AuthHeaders, toStringMap); err != nil {
+		return err
+	}
+	return a.Auth.loadEnv(prefix)
+}
+
 func readEnv[T any](prefix string, key string, in *T, conv func(string) (T, error)) error {
 	envKey := prefix + "_" + key
+	markEnvKeyRead(envKey)
 	if env := os.Getenv(envKey); env != "" {
 		r, err := conv(env)
 		if err != nil {
@@ -493,12 +772,48 @@ func readEnv[T any](prefix string, key string, in *T, conv func(string) (T, erro
 	return nil
 }
 
+// readEnvSecret works like readEnv, but it also accepts a <KEY>_FILE variable pointing to a file that holds the value
+func readEnvSecret[T any](prefix string, key string, in *T, conv func(string) (T, error)) error {
+	envKey := prefix + "_" + key
+	fileEnvKey := envKey + "_FILE"
+	markEnvKeyRead(fileEnvKey)
+	env := os.Getenv(envKey)
+	path := os.Getenv(fileEnvKey)
+	if env != "" && path != "" {
+		return &envVarReadError{key: envKey, inner: fmt.Errorf("only one of '%s' and '%s' can be set", envKey, fileEnvKey)}
+	}
+	if path == "" {
+		return readEnv(prefix, key, in, conv)
+	}
+	content, err := readSecretFile(path)
+	if err != nil {
+		return &envVarReadError{key: fileEnvKey, inner: err}
+	}
+	if content == "" {
+		return nil
+	}
+	r, err := conv(content)
+	if err != nil {
+		return &envVarReadError{key: fileEnvKey, inner: err}
+	}
+	*in = r
+	return nil
+}
+
 func readEnvString(prefix string, key string, in *string) {
-	if env := os.Getenv(prefix + "_" + key); env != "" {
+	envKey := prefix + "_" + key
+	markEnvKeyRead(envKey)
+	if env := os.Getenv(envKey); env != "" {
 		*in = env
 	}
 }
 
+func markEnvKeyRead(key string) {
+	if envKeysRead != nil {
+		envKeysRead[key] = struct{}{}
+	}
+}
+
 func concatPrefix(p1 string, p2 string) string {
 	return p1 + "_" + p2
 }
@@ -23,6 +23,18 @@ func TestSDKConfig_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_SDK1_OFFLINE_LOCAL_POLL_INTERVAL", "100")
 	t.Setenv("CONFIGCAT_SDK1_OFFLINE_USE_CACHE", "true")
 	t.Setenv("CONFIGCAT_SDK1_OFFLINE_CACHE_POLL_INTERVAL", "200")
+	t.Setenv("CONFIGCAT_SDK1_OFFLINE_UPSTREAM_URL", "http://upstream")
+	t.Setenv("CONFIGCAT_SDK1_OFFLINE_UPSTREAM_SDK_ID", "central")
+	t.Setenv("CONFIGCAT_SDK1_OFFLINE_UPSTREAM_MAX_RECONNECT_DELAY", "30")
+	t.Setenv("CONFIGCAT_SDK1_OFFLINE_UPSTREAM_READ_TIMEOUT", "45")
+	t.Setenv("CONFIGCAT_SDK1_OFFLINE_UPSTREAM_HEADERS", `{"X-SSE-KEY": "upstream-auth"}`)
+	t.Setenv("CONFIGCAT_SDK1_OVERRIDES_BEHAVIOR", "remote_over_local")
+	t.Setenv("CONFIGCAT_SDK1_OVERRIDES_FILE_PATH", "./overrides.json")
+	t.Setenv("CONFIGCAT_SDK1_OVERRIDES_VALUES", `{"flag1": true, "flag2": 5, "flag3": 1.5, "flag4": "str"}`)
+	t.Setenv("CONFIGCAT_SDK1_HISTORY_SIZE", "20")
+	t.Setenv("CONFIGCAT_SDK1_HISTORY_USE_CACHE", "true")
+	t.Setenv("CONFIGCAT_SDK1_INIT_TIMEOUT", "5")
+	t.Setenv("CONFIGCAT_SDK1_INIT_SERVE_DEFAULTS", "true")
 	t.Setenv("CONFIGCAT_SDK1_WEBHOOK_SIGNING_KEY", "key")
 	t.Setenv("CONFIGCAT_SDK1_WEBHOOK_SIGNATURE_VALID_FOR", "600")
 	t.Setenv("CONFIGCAT_SDK1_DEFAULT_USER_ATTRIBUTES", `{"attr1": "attr_value1", "attr2": "attr_value2", "attr3": 5, "attr4":["a","b"]}`)
@@ -43,6 +55,18 @@ func TestSDKConfig_ENV(t *testing.T) {
 	assert.Equal(t, 100, conf.SDKs["sdk1"].Offline.Local.PollInterval)
 	assert.True(t, conf.SDKs["sdk1"].Offline.UseCache)
 	assert.Equal(t, 200, conf.SDKs["sdk1"].Offline.CachePollInterval)
+	assert.Equal(t, "http://upstream", conf.SDKs["sdk1"].Offline.Upstream.Url)
+	assert.Equal(t, "central", conf.SDKs["sdk1"].Offline.Upstream.SdkId)
+	assert.Equal(t, 30, conf.SDKs["sdk1"].Offline.Upstream.MaxReconnectDelay)
+	assert.Equal(t, 45, conf.SDKs["sdk1"].Offline.Upstream.ReadTimeout)
+	assert.Equal(t, "upstream-auth", conf.SDKs["sdk1"].Offline.Upstream.Headers["X-SSE-KEY"])
+	assert.Equal(t, "remote_over_local", conf.SDKs["sdk1"].Overrides.Behavior)
+	assert.Equal(t, "./overrides.json", conf.SDKs["sdk1"].Overrides.FilePath)
+	assert.Equal(t, map[string]interface{}{"flag1": true, "flag2": 5, "flag3": 1.5, "flag4": "str"}, conf.SDKs["sdk1"].Overrides.Values)
+	assert.Equal(t, 20, conf.SDKs["sdk1"].History.Size)
+	assert.True(t, conf.SDKs["sdk1"].History.UseCache)
+	assert.Equal(t, 5, conf.SDKs["sdk1"].Init.Timeout)
+	assert.True(t, conf.SDKs["sdk1"].Init.ServeDefaults)
 	assert.Equal(t, "key", conf.SDKs["sdk1"].WebhookSigningKey)
 	assert.Equal(t, 600, conf.SDKs["sdk1"].WebhookSignatureValidFor)
 	assert.Equal(t, "attr_value1", conf.SDKs["sdk1"].DefaultAttrs["attr1"])
@@ -57,6 +81,7 @@ func TestAutoSDKs_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_PROFILE_BASE_URL", `https://base.com`)
 	t.Setenv("CONFIGCAT_PROFILE_SDKS_BASE_URL", `https://sdk-base.com`)
 	t.Setenv("CONFIGCAT_PROFILE_SDKS_LOG_LEVEL", "info")
+	t.Setenv("CONFIGCAT_PROFILE_SDKS_INIT_TIMEOUT", "5")
 	t.Setenv("CONFIGCAT_PROFILE_POLL_INTERVAL", "300")
 	t.Setenv("CONFIGCAT_PROFILE_WEBHOOK_SIGNING_KEY", "key")
 	t.Setenv("CONFIGCAT_PROFILE_WEBHOOK_SIGNATURE_VALID_FOR", "600")
@@ -70,6 +95,7 @@ func TestAutoSDKs_ENV(t *testing.T) {
 	assert.Equal(t, "https://base.com", conf.Profile.BaseUrl)
 	assert.Equal(t, "https://sdk-base.com", conf.Profile.SDKs.BaseUrl)
 	assert.Equal(t, log.Info, conf.Profile.SDKs.Log.GetLevel())
+	assert.Equal(t, 5, conf.Profile.SDKs.Init.Timeout)
 	assert.Equal(t, 300, conf.Profile.PollInterval)
 	assert.Equal(t, "key", conf.Profile.WebhookSigningKey)
 	assert.Equal(t, 600, conf.Profile.WebhookSignatureValidFor)
@@ -112,6 +138,18 @@ func TestRedisConfig_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_CACHE_REDIS_PASSWORD", "pass")
 	t.Setenv("CONFIGCAT_CACHE_REDIS_USER", "user")
 	t.Setenv("CONFIGCAT_CACHE_REDIS_ADDRESSES", `["addr1", "addr2"]`)
+	t.Setenv("CONFIGCAT_CACHE_REDIS_MASTER_NAME", "master")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_SENTINEL_USER", "s_user")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_SENTINEL_PASSWORD", "s_pass")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_CLUSTER_MODE", "true")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_READ_ONLY", "true")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_ROUTE_BY_LATENCY", "true")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_ROUTE_RANDOMLY", "true")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_POOL_SIZE", "20")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_MIN_IDLE_CONNS", "5")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_DIAL_TIMEOUT_MS", "500")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_READ_TIMEOUT_MS", "200")
+	t.Setenv("CONFIGCAT_CACHE_REDIS_WRITE_TIMEOUT_MS", "300")
 	t.Setenv("CONFIGCAT_CACHE_REDIS_TLS_ENABLED", "true")
 	t.Setenv("CONFIGCAT_CACHE_REDIS_TLS_MIN_VERSION", "1.1")
 	t.Setenv("CONFIGCAT_CACHE_REDIS_TLS_SERVER_NAME", "serv")
@@ -126,6 +164,19 @@ func TestRedisConfig_ENV(t *testing.T) {
 	assert.Equal(t, "user", conf.Cache.Redis.User)
 	assert.Equal(t, "addr1", conf.Cache.Redis.Addresses[0])
 	assert.Equal(t, "addr2", conf.Cache.Redis.Addresses[1])
+	assert.Equal(t, "master", conf.Cache.Redis.MasterName)
+	assert.Equal(t, "s_user", conf.Cache.Redis.SentinelUser)
+	assert.Equal(t, "s_pass", conf.Cache.Redis.SentinelPassword)
+	assert.True(t, conf.Cache.Redis.ClusterMode)
+	assert.True(t, conf.Cache.Redis.ReadOnly)
+	assert.True(t, conf.Cache.Redis.RouteByLatency)
+	assert.True(t, conf.Cache.Redis.RouteRandomly)
+	assert.Equal(t, 20, conf.Cache.Redis.PoolSize)
+	assert.Equal(t, 5, conf.Cache.Redis.MinIdleConns)
+	assert.Equal(t, 500, conf.Cache.Redis.DialTimeoutMs)
+	assert.Equal(t, 200, conf.Cache.Redis.ReadTimeoutMs)
+	assert.Equal(t, 300, conf.Cache.Redis.WriteTimeoutMs)
+	assert.Empty(t, conf.UnknownEnvVars())
 	assert.True(t, conf.Cache.Redis.Tls.Enabled)
 	assert.Equal(t, tls.VersionTLS11, int(conf.Cache.Redis.Tls.GetVersion()))
 	assert.Equal(t, "serv", conf.Cache.Redis.Tls.ServerName)
@@ -174,6 +225,75 @@ func TestDynamoDbConfig_ENV(t *testing.T) {
 	assert.Equal(t, "db", conf.Cache.DynamoDb.Table)
 }
 
+func TestSqlConfig_ENV(t *testing.T) {
+	t.Setenv("CONFIGCAT_CACHE_SQL_ENABLED", "true")
+	t.Setenv("CONFIGCAT_CACHE_SQL_DRIVER", "mysql")
+	t.Setenv("CONFIGCAT_CACHE_SQL_DSN", "dsn")
+	t.Setenv("CONFIGCAT_CACHE_SQL_TABLE", "tbl")
+	t.Setenv("CONFIGCAT_CACHE_SQL_AUTO_MIGRATE", "false")
+
+	conf, err := LoadConfigFromFileAndEnvironment("")
+	require.NoError(t, err)
+
+	assert.True(t, conf.Cache.Sql.Enabled)
+	assert.Equal(t, "mysql", conf.Cache.Sql.Driver)
+	assert.Equal(t, "dsn", conf.Cache.Sql.DSN)
+	assert.Equal(t, "tbl", conf.Cache.Sql.Table)
+	assert.False(t, conf.Cache.Sql.AutoMigrate)
+	assert.Empty(t, conf.UnknownEnvVars())
+}
+
+func TestFilesystemConfig_ENV(t *testing.T) {
+	t.Setenv("CONFIGCAT_CACHE_FILESYSTEM_ENABLED", "true")
+	t.Setenv("CONFIGCAT_CACHE_FILESYSTEM_PATH", "/var/cache/proxy")
+
+	conf, err := LoadConfigFromFileAndEnvironment("")
+	require.NoError(t, err)
+
+	assert.True(t, conf.Cache.Filesystem.Enabled)
+	assert.Equal(t, "/var/cache/proxy", conf.Cache.Filesystem.Path)
+}
+
+func TestEncryptionConfig_ENV(t *testing.T) {
+	t.Setenv("CONFIGCAT_CACHE_ENCRYPTION_ENABLED", "true")
+	t.Setenv("CONFIGCAT_CACHE_ENCRYPTION_KEY_FILE", "/etc/proxy/keys")
+	t.Setenv("CONFIGCAT_CACHE_ENCRYPTION_ACTIVE_KEY_ID", "k2")
+	t.Setenv("CONFIGCAT_CACHE_ENCRYPTION_ALLOW_PLAINTEXT_READ", "true")
+
+	conf, err := LoadConfigFromFileAndEnvironment("")
+	require.NoError(t, err)
+
+	assert.True(t, conf.Cache.Encryption.Enabled)
+	assert.Equal(t, "/etc/proxy/keys", conf.Cache.Encryption.KeyFile)
+	assert.Equal(t, "k2", conf.Cache.Encryption.ActiveKeyId)
+	assert.True(t, conf.Cache.Encryption.AllowPlaintextRead)
+	assert.Empty(t, conf.UnknownEnvVars())
+}
+
+func TestCacheChainConfig_ENV(t *testing.T) {
+	t.Setenv("CONFIGCAT_CACHE_CHAIN", `["redis","filesystem"]`)
+	t.Setenv("CONFIGCAT_CACHE_KEY_PREFIX", "team-a:")
+	t.Setenv("CONFIGCAT_CACHE_TTL", "3600")
+	t.Setenv("CONFIGCAT_CACHE_OPERATION_TIMEOUT_MS", "1000")
+	t.Setenv("CONFIGCAT_CACHE_PING_INTERVAL", "10")
+	t.Setenv("CONFIGCAT_CACHE_CIRCUIT_BREAKER_ENABLED", "true")
+	t.Setenv("CONFIGCAT_CACHE_CIRCUIT_BREAKER_FAILURE_THRESHOLD", "3")
+	t.Setenv("CONFIGCAT_CACHE_CIRCUIT_BREAKER_OPEN_DURATION", "20")
+
+	conf, err := LoadConfigFromFileAndEnvironment("")
+	require.NoError(t, err)
+
+	assert.Equal(t, "team-a:", conf.Cache.KeyPrefix)
+	assert.Equal(t, 3600, conf.Cache.Ttl)
+	assert.Equal(t, 1000, conf.Cache.OperationTimeoutMs)
+	assert.Equal(t, 10, conf.Cache.PingInterval)
+	assert.True(t, conf.Cache.CircuitBreaker.Enabled)
+	assert.Equal(t, 3, conf.Cache.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, 20, conf.Cache.CircuitBreaker.OpenDuration)
+	assert.Equal(t, []string{"redis", "filesystem"}, conf.Cache.Chain)
+	assert.Empty(t, conf.UnknownEnvVars())
+}
+
 func TestTlsConfig_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_TLS_ENABLED", "true")
 	t.Setenv("CONFIGCAT_TLS_MIN_VERSION", "1.1")
@@ -206,6 +326,14 @@ func TestDiagConfig_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_DIAG_PORT", "8091")
 	t.Setenv("CONFIGCAT_DIAG_METRICS_ENABLED", "false")
 	t.Setenv("CONFIGCAT_DIAG_STATUS_ENABLED", "false")
+	t.Setenv("CONFIGCAT_DIAG_STATUS_MASK_SDK_KEYS", "false")
+	t.Setenv("CONFIGCAT_DIAG_STATUS_AUTH_HEADERS", `{"X-STATUS-KEY": "status-secret"}`)
+	t.Setenv("CONFIGCAT_DIAG_STATUS_AUTH_USER", "minnie")
+	t.Setenv("CONFIGCAT_DIAG_STATUS_AUTH_PASSWORD", "status-pass")
+	t.Setenv("CONFIGCAT_DIAG_HEALTH_ENABLED", "false")
+	t.Setenv("CONFIGCAT_DIAG_HEALTH_SDKS", `["sdk1", "sdk2"]`)
+	t.Setenv("CONFIGCAT_DIAG_HEALTH_REQUIRE_CACHE", "true")
+	t.Setenv("CONFIGCAT_DIAG_HEALTH_DEGRADED_POLICY", "not_ready")
 	t.Setenv("CONFIGCAT_DIAG_METRICS_PROMETHEUS_ENABLED", "false")
 	t.Setenv("CONFIGCAT_DIAG_METRICS_OTLP_ENABLED", "true")
 	t.Setenv("CONFIGCAT_DIAG_METRICS_OTLP_PROTOCOL", "grpc")
@@ -214,6 +342,10 @@ func TestDiagConfig_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_DIAG_TRACES_OTLP_ENABLED", "true")
 	t.Setenv("CONFIGCAT_DIAG_TRACES_OTLP_PROTOCOL", "grpc")
 	t.Setenv("CONFIGCAT_DIAG_TRACES_OTLP_ENDPOINT", "http://localhost:4317")
+	t.Setenv("CONFIGCAT_DIAG_ADMIN_ENABLED", "true")
+	t.Setenv("CONFIGCAT_DIAG_ADMIN_AUTH_HEADERS", `{"X-API-KEY": "secret"}`)
+	t.Setenv("CONFIGCAT_DIAG_ADMIN_AUTH_USER", "mickey")
+	t.Setenv("CONFIGCAT_DIAG_ADMIN_AUTH_PASSWORD", "pass")
 
 	conf, err := LoadConfigFromFileAndEnvironment("")
 	require.NoError(t, err)
@@ -221,6 +353,14 @@ func TestDiagConfig_ENV(t *testing.T) {
 	assert.False(t, conf.Diag.Enabled)
 	assert.Equal(t, 8091, conf.Diag.Port)
 	assert.False(t, conf.Diag.Status.Enabled)
+	assert.False(t, conf.Diag.Status.MaskSdkKeys)
+	assert.Equal(t, "status-secret", conf.Diag.Status.AuthHeaders["X-STATUS-KEY"])
+	assert.Equal(t, "minnie", conf.Diag.Status.Auth.User)
+	assert.Equal(t, "status-pass", conf.Diag.Status.Auth.Password)
+	assert.False(t, conf.Diag.Health.Enabled)
+	assert.Equal(t, []string{"sdk1", "sdk2"}, conf.Diag.Health.SDKs)
+	assert.True(t, conf.Diag.Health.RequireCache)
+	assert.Equal(t, DegradedNotReady, conf.Diag.Health.DegradedPolicy)
 	assert.False(t, conf.Diag.Metrics.Enabled)
 	assert.False(t, conf.Diag.Metrics.Prometheus.Enabled)
 	assert.True(t, conf.Diag.Metrics.Otlp.Enabled)
@@ -230,6 +370,21 @@ func TestDiagConfig_ENV(t *testing.T) {
 	assert.True(t, conf.Diag.Traces.Otlp.Enabled)
 	assert.Equal(t, "grpc", conf.Diag.Traces.Otlp.Protocol)
 	assert.Equal(t, "http://localhost:4317", conf.Diag.Traces.Otlp.Endpoint)
+	assert.True(t, conf.Diag.Admin.Enabled)
+	assert.Equal(t, "secret", conf.Diag.Admin.AuthHeaders["X-API-KEY"])
+	assert.Equal(t, "mickey", conf.Diag.Admin.Auth.User)
+	assert.Equal(t, "pass", conf.Diag.Admin.Auth.Password)
+}
+
+func TestLocalSnapshotConfig_ENV(t *testing.T) {
+	t.Setenv("CONFIGCAT_LOCAL_SNAPSHOT_ENABLED", "true")
+	t.Setenv("CONFIGCAT_LOCAL_SNAPSHOT_PATH", "/var/lib/proxy")
+
+	conf, err := LoadConfigFromFileAndEnvironment("")
+	require.NoError(t, err)
+
+	assert.True(t, conf.LocalSnapshot.Enabled)
+	assert.Equal(t, "/var/lib/proxy", conf.LocalSnapshot.Path)
 }
 
 func TestGlobalOfflineConfig_ENV(t *testing.T) {
@@ -259,17 +414,23 @@ func TestHttpConfig_ENV(t *testing.T) {
 	t.Setenv("CONFIGCAT_HTTP_SSE_LOG_LEVEL", "warn")
 	t.Setenv("CONFIGCAT_HTTP_SSE_HEARTBEAT_INTERVAL", "5")
 	t.Setenv("CONFIGCAT_HTTP_SSE_HEADERS", `{"CUSTOM-HEADER1": "sse-val1", "CUSTOM-HEADER2": "sse-val2"}`)
+	t.Setenv("CONFIGCAT_HTTP_SSE_AUTH_HEADERS", `{"X-SSE-KEY": "sse-auth"}`)
 	t.Setenv("CONFIGCAT_HTTP_API_ENABLED", "true")
 	t.Setenv("CONFIGCAT_HTTP_API_CORS_ENABLED", "true")
 	t.Setenv("CONFIGCAT_HTTP_API_CORS_ALLOWED_ORIGINS", `["https://example1.com","https://example2.com"]`)
 	t.Setenv("CONFIGCAT_HTTP_API_HEADERS", `{"CUSTOM-HEADER1": "api-val1", "CUSTOM-HEADER2": "api-val2"}`)
 	t.Setenv("CONFIGCAT_HTTP_API_AUTH_HEADERS", `{"X-API-KEY1": "api-auth1", "X-API-KEY2": "api-auth2"}`)
+	t.Setenv("CONFIGCAT_HTTP_API_OVERRIDES_AUTH_HEADERS", `{"X-OVERRIDES-KEY": "overrides-auth"}`)
 	t.Setenv("CONFIGCAT_HTTP_OFREP_ENABLED", "true")
 	t.Setenv("CONFIGCAT_HTTP_OFREP_CORS_ENABLED", "true")
 	t.Setenv("CONFIGCAT_HTTP_OFREP_CORS_ALLOWED_ORIGINS", `["https://example1.com","https://example2.com"]`)
 	t.Setenv("CONFIGCAT_HTTP_OFREP_HEADERS", `{"CUSTOM-HEADER1": "ofrep-val1", "CUSTOM-HEADER2": "ofrep-val2"}`)
 	t.Setenv("CONFIGCAT_HTTP_OFREP_AUTH_HEADERS", `{"X-API-KEY1": "ofrep-auth1", "X-API-KEY2": "ofrep-auth2"}`)
 	t.Setenv("CONFIGCAT_HTTP_STATUS_ENABLED", "true")
+	t.Setenv("CONFIGCAT_HTTP_STATUS_MASK_SDK_KEYS", "false")
+	t.Setenv("CONFIGCAT_HTTP_STATUS_AUTH_HEADERS", `{"X-STATUS-KEY": "status-auth"}`)
+	t.Setenv("CONFIGCAT_HTTP_STATUS_CORS_ENABLED", "true")
+	t.Setenv("CONFIGCAT_HTTP_STATUS_CORS_ALLOWED_ORIGINS", `["https://example1.com"]`)
 
 	conf, err := LoadConfigFromFileAndEnvironment("")
 	require.NoError(t, err)
@@ -296,6 +457,7 @@ func TestHttpConfig_ENV(t *testing.T) {
 	assert.Equal(t, "sse-val1", conf.Http.Sse.Headers["CUSTOM-HEADER1"])
 	assert.Equal(t, "sse-val2", conf.Http.Sse.Headers["CUSTOM-HEADER2"])
 	assert.Equal(t, 5, conf.Http.Sse.HeartBeatInterval)
+	assert.Equal(t, "sse-auth", conf.Http.Sse.AuthHeaders["X-SSE-KEY"])
 
 	assert.True(t, conf.Http.Api.Enabled)
 	assert.True(t, conf.Http.Api.CORS.Enabled)
@@ -305,6 +467,7 @@ func TestHttpConfig_ENV(t *testing.T) {
 	assert.Equal(t, "api-val2", conf.Http.Api.Headers["CUSTOM-HEADER2"])
 	assert.Equal(t, "api-auth1", conf.Http.Api.AuthHeaders["X-API-KEY1"])
 	assert.Equal(t, "api-auth2", conf.Http.Api.AuthHeaders["X-API-KEY2"])
+	assert.Equal(t, "overrides-auth", conf.Http.Api.Overrides.AuthHeaders["X-OVERRIDES-KEY"])
 
 	assert.True(t, conf.Http.OFREP.Enabled)
 	assert.True(t, conf.Http.OFREP.CORS.Enabled)
@@ -316,6 +479,10 @@ func TestHttpConfig_ENV(t *testing.T) {
 	assert.Equal(t, "ofrep-auth2", conf.Http.OFREP.AuthHeaders["X-API-KEY2"])
 
 	assert.True(t, conf.Http.Status.Enabled)
+	assert.False(t, conf.Http.Status.MaskSdkKeys)
+	assert.Equal(t, "status-auth", conf.Http.Status.AuthHeaders["X-STATUS-KEY"])
+	assert.True(t, conf.Http.Status.CORS.Enabled)
+	assert.Equal(t, []string{"https://example1.com"}, conf.Http.Status.CORS.AllowedOrigins)
 }
 
 func TestCORSConfig_ENV(t *testing.T) {
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	fileRefPrefix = "file:"
+	envRefPrefix  = "env:"
+)
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	// files created by editors and secret managers usually end with a line break that isn't part of the secret
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveReferences replaces 'file:<path>' and 'env:<variable>' scalar values with the referenced content
+func resolveReferences(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := resolveReferences(child); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		// only values are resolved, keys are at the even indexes
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := resolveReferences(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return resolveScalarReference(node)
+	}
+	return nil
+}
+
+func resolveScalarReference(node *yaml.Node) error {
+	if node.ShortTag() != "!!str" {
+		return nil
+	}
+	var value string
+	switch {
+	case strings.HasPrefix(node.Value, fileRefPrefix):
+		path := strings.TrimPrefix(node.Value, fileRefPrefix)
+		content, err := readSecretFile(path)
+		if err != nil {
+			return fmt.Errorf("line %d: failed to read file referenced as '%s': %s", node.Line, node.Value, err)
+		}
+		value = content
+	case strings.HasPrefix(node.Value, envRefPrefix):
+		name := strings.TrimPrefix(node.Value, envRefPrefix)
+		env, ok := os.LookupEnv(name)
+		if !ok {
+			return fmt.Errorf("line %d: environment variable referenced as '%s' is not set", node.Line, node.Value)
+		}
+		value = env
+	default:
+		return nil
+	}
+	// let the YAML decoder resolve the type of the referenced value (e.g. for numbers and booleans)
+	node.Value = value
+	node.Style = 0
+	node.Tag = ""
+	if node.ShortTag() == "!!null" {
+		node.Tag = "!!str"
+	}
+	return nil
+}
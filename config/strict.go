@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// checkUnknownKeys collects the mapping keys of the YAML document that don't have a corresponding field in the target type
+func checkUnknownKeys(node *yaml.Node, t reflect.Type, path string, unknown *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			checkUnknownKeys(child, t, path, unknown)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			checkUnknownKeys(node.Alias, t, path, unknown)
+		}
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, child := range node.Content {
+			checkUnknownKeys(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	case yaml.MappingNode:
+		switch t.Kind() {
+		case reflect.Map:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				checkUnknownKeys(node.Content[i+1], t.Elem(), joinKeyPath(path, node.Content[i].Value), unknown)
+			}
+		case reflect.Struct:
+			fields := yamlFields(t)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if key.ShortTag() == "!!merge" {
+					checkUnknownKeys(value, t, path, unknown)
+					continue
+				}
+				fieldType, ok := fields[key.Value]
+				if !ok {
+					*unknown = append(*unknown, fmt.Sprintf("line %d: unknown key '%s'", key.Line, joinKeyPath(path, key.Value)))
+					continue
+				}
+				checkUnknownKeys(value, fieldType, joinKeyPath(path, key.Value), unknown)
+			}
+		}
+	}
+}
+
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+func joinKeyPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrict_YAML_UnknownKeys(t *testing.T) {
+	testutils.UseTempFile(`
+sdks:
+  sdk1:
+    key: "key"
+    poll_intervall: 30
+    offline:
+      local:
+        file_pat: "./local.json"
+http:
+  sse:
+    enabled: true
+    cors:
+      allowed_origin: ["https://example.com"]
+tls:
+  certificates:
+    - key: "./key"
+      cert: "./cert"
+      chain: "./chain"
+unknown_root: true
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"line 5: unknown key 'sdks.sdk1.poll_intervall'",
+			"line 8: unknown key 'sdks.sdk1.offline.local.file_pat'",
+			"line 13: unknown key 'http.sse.cors.allowed_origin'",
+			"line 18: unknown key 'tls.certificates[0].chain'",
+			"line 19: unknown key 'unknown_root'",
+		}, conf.UnknownKeys())
+	})
+}
+
+func TestStrict_YAML_KnownKeys(t *testing.T) {
+	testutils.UseTempFile(`
+defaults: &defaults
+  poll_interval: 30
+sdks:
+  sdk1:
+    <<: *defaults
+    key: "key"
+    default_user_attributes:
+      any_attribute: "value"
+http:
+  api:
+    auth_headers:
+      X-API-KEY: "secret"
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"line 2: unknown key 'defaults'"}, conf.UnknownKeys())
+		assert.Equal(t, 30, conf.SDKs["sdk1"].PollInterval)
+	})
+	testutils.UseTempFile(`
+sdks:
+  sdk1:
+    key: "key"
+    default_user_attributes:
+      any_attribute: "value"
+http:
+  api:
+    auth_headers:
+      X-API-KEY: "secret"
+`, func(file string) {
+		conf, err := LoadConfigFromFileAndEnvironment(file)
+		require.NoError(t, err)
+		assert.Empty(t, conf.UnknownKeys())
+		assert.Equal(t, "value", conf.SDKs["sdk1"].DefaultAttrs["any_attribute"])
+	})
+}
+
+func TestStrict_ENV_UnknownVars(t *testing.T) {
+	t.Setenv("CONFIGCAT_SDKS", `{"sdk1": "key"}`)
+	t.Setenv("CONFIGCAT_SDK1_POLL_INTERVAL", "30")
+	t.Setenv("CONFIGCAT_SDK1_POLL_INTERVALL", "30")
+	t.Setenv("CONFIGCAT_SDK2_POLL_INTERVAL", "30")
+	t.Setenv("CONFIGCAT_PROFILE_SECRET_FILE", "")
+	t.Setenv("CONFIGCAT_HTTP_PORTT", "8080")
+
+	conf, err := LoadConfigFromFileAndEnvironment("")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"CONFIGCAT_HTTP_PORTT", "CONFIGCAT_SDK1_POLL_INTERVALL", "CONFIGCAT_SDK2_POLL_INTERVAL"}, conf.UnknownEnvVars())
+}
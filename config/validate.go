@@ -3,9 +3,15 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 )
 
+var sqlIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 func (c *Config) Validate() error {
 	if len(c.SDKs) == 0 && !c.Profile.IsSet() {
 		return fmt.Errorf("sdk: at least 1 SDK or a proxy profile must be configured")
@@ -32,15 +38,30 @@ func (c *Config) Validate() error {
 	if err := c.Grpc.validate(); err != nil {
 		return err
 	}
+	if err := c.Cache.validate(); err != nil {
+		return err
+	}
 	if err := c.Cache.Redis.validate(); err != nil {
 		return err
 	}
 	if err := c.Cache.MongoDb.validate(); err != nil {
 		return err
 	}
+	if err := c.Cache.Sql.validate(); err != nil {
+		return err
+	}
+	if err := c.Cache.Filesystem.validate(); err != nil {
+		return err
+	}
+	if err := c.Cache.Encryption.validate(); err != nil {
+		return err
+	}
 	if err := c.GlobalOfflineConfig.validate(&c.Cache); err != nil {
 		return err
 	}
+	if c.LocalSnapshot.Enabled && len(c.LocalSnapshot.Path) == 0 {
+		return fmt.Errorf("local_snapshot: directory path is required")
+	}
 	return nil
 }
 
@@ -57,6 +78,50 @@ func (s *SDKConfig) validate(c *CacheConfig, p *ProfileConfig, sdkId string) err
 	if err := s.Offline.validate(c, sdkId); err != nil {
 		return err
 	}
+	if err := s.Overrides.validate(sdkId); err != nil {
+		return err
+	}
+	if s.History.Size < 0 {
+		return fmt.Errorf("sdk-%s: history size must not be negative", sdkId)
+	}
+	if s.History.UseCache && !c.IsSet() {
+		return fmt.Errorf("sdk-%s: storing the config history requires a configured cache", sdkId)
+	}
+	if s.Init.Timeout < 0 {
+		return fmt.Errorf("sdk-%s: init timeout must not be negative", sdkId)
+	}
+	return nil
+}
+
+func (c *CacheConfig) validate() error {
+	if c.Ttl < 0 {
+		return fmt.Errorf("cache: ttl must not be negative")
+	}
+	if c.OperationTimeoutMs < 0 {
+		return fmt.Errorf("cache: operation timeout must not be negative")
+	}
+	if c.PingInterval < 0 {
+		return fmt.Errorf("cache: ping interval must not be negative")
+	}
+	if c.CircuitBreaker.Enabled && c.CircuitBreaker.FailureThreshold < 1 {
+		return fmt.Errorf("cache: circuit breaker failure threshold must be greater than 0")
+	}
+	if c.CircuitBreaker.Enabled && c.CircuitBreaker.OpenDuration < 1 {
+		return fmt.Errorf("cache: circuit breaker open duration must be greater than 0 seconds")
+	}
+	seen := make(map[string]struct{}, len(c.Chain))
+	for _, name := range c.Chain {
+		if !slices.Contains(CacheBackends, name) {
+			return fmt.Errorf("cache: invalid chain entry '%s', it must be one of %s", name, strings.Join(CacheBackends, ", "))
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("cache: '%s' is listed multiple times in the chain", name)
+		}
+		seen[name] = struct{}{}
+		if !c.IsBackendEnabled(name) {
+			return fmt.Errorf("cache: '%s' is listed in the chain but it's not enabled", name)
+		}
+	}
 	return nil
 }
 
@@ -67,6 +132,21 @@ func (r *RedisConfig) validate() error {
 	if len(r.Addresses) == 0 {
 		return fmt.Errorf("redis: at least 1 server address required")
 	}
+	if r.MasterName != "" && r.ClusterMode {
+		return fmt.Errorf("redis: sentinel master name and cluster mode can't be used together")
+	}
+	if r.MasterName == "" && (r.SentinelUser != "" || r.SentinelPassword != "") {
+		return fmt.Errorf("redis: sentinel credentials require a sentinel master name")
+	}
+	if (r.ReadOnly || r.RouteByLatency || r.RouteRandomly) && r.Topology() == RedisStandalone {
+		return fmt.Errorf("redis: replica reads require a sentinel or cluster setup")
+	}
+	if r.PoolSize < 0 || r.MinIdleConns < 0 {
+		return fmt.Errorf("redis: pool size and min idle connections must not be negative")
+	}
+	if r.DialTimeoutMs < 0 || r.ReadTimeoutMs < 0 || r.WriteTimeoutMs < 0 {
+		return fmt.Errorf("redis: timeouts must not be negative")
+	}
 	if err := r.Tls.validate(); err != nil {
 		return err
 	}
@@ -86,21 +166,65 @@ func (m *MongoDbConfig) validate() error {
 	return nil
 }
 
+func (s *SqlConfig) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.Driver != "postgres" && s.Driver != "mysql" && s.Driver != "sqlite" {
+		return fmt.Errorf("sql: invalid driver, it must be 'postgres', 'mysql', or 'sqlite'")
+	}
+	if len(s.DSN) == 0 {
+		return fmt.Errorf("sql: invalid data source name")
+	}
+	if !sqlIdentifierRegex.MatchString(s.Table) {
+		return fmt.Errorf("sql: invalid table name, it must contain only letters, digits, and underscores")
+	}
+	return nil
+}
+
+func (f *FilesystemConfig) validate() error {
+	if !f.Enabled {
+		return nil
+	}
+	if len(f.Path) == 0 {
+		return fmt.Errorf("filesystem: cache directory path is required")
+	}
+	return nil
+}
+
+func (e *EncryptionConfig) validate() error {
+	if !e.Enabled {
+		return nil
+	}
+	if len(e.KeyFile) == 0 {
+		return fmt.Errorf("encryption: key file path is required")
+	}
+	return nil
+}
+
 func (o *OfflineConfig) validate(c *CacheConfig, sdkId string) error {
 	if !o.Enabled {
 		return nil
 	}
-	if o.Local.FilePath == "" && !o.UseCache {
-		return fmt.Errorf("sdk-%s: offline mode requires either a configured cache or a local file", sdkId)
+	if o.Local.FilePath == "" && !o.UseCache && o.Upstream.Url == "" {
+		return fmt.Errorf("sdk-%s: offline mode requires either a configured cache, a local file or an upstream proxy", sdkId)
 	}
 	if o.Local.FilePath != "" && o.UseCache {
 		return fmt.Errorf("sdk-%s: can't use both local file and cache for offline mode", sdkId)
 	}
+	if o.Upstream.Url != "" && (o.UseCache || o.Local.FilePath != "") {
+		return fmt.Errorf("sdk-%s: can't use an upstream proxy together with a local file or cache for offline mode", sdkId)
+	}
 	if o.Local.FilePath != "" {
 		if err := o.Local.validate(sdkId); err != nil {
 			return err
 		}
 	}
+	if o.Upstream.Url != "" {
+		if err := o.Upstream.validate(sdkId); err != nil {
+			return err
+		}
+	}
 	if o.UseCache && !c.IsSet() {
 		return fmt.Errorf("sdk-%s: offline mode enabled with cache, but no cache is configured", sdkId)
 	}
@@ -114,7 +238,7 @@ func (g *GlobalOfflineConfig) validate(c *CacheConfig) error {
 	if !g.Enabled {
 		return nil
 	}
-	if !c.Redis.Enabled {
+	if !c.IsSet() {
 		return fmt.Errorf("offline: global offline mode enabled, but no cache is configured")
 	}
 	if g.CachePollInterval < 1 {
@@ -154,9 +278,21 @@ func (h *HttpConfig) validate() error {
 	if err := h.CdnProxy.CORS.validate(); err != nil {
 		return err
 	}
+	if h.Status.Enabled {
+		if err := h.Status.validate("http"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (s *StatusConfig) validate(section string) error {
+	if (s.Auth.User != "" && s.Auth.Password == "") || (s.Auth.Password != "" && s.Auth.User == "") {
+		return fmt.Errorf("%s: both status basic auth user and password required", section)
+	}
+	return s.CORS.validate()
+}
+
 func (w *WebhookConfig) validate() error {
 	if !w.Enabled {
 		return nil
@@ -194,6 +330,45 @@ func (l *LocalConfig) validate(sdkId string) error {
 	return nil
 }
 
+func (o *OverrideConfig) validate(sdkId string) error {
+	if !o.IsSet() {
+		return nil
+	}
+	if o.Behavior != OverrideLocalOverRemote && o.Behavior != OverrideRemoteOverLocal {
+		return fmt.Errorf("sdk-%s: invalid overrides behavior, it must be '%s' or '%s'", sdkId, OverrideLocalOverRemote, OverrideRemoteOverLocal)
+	}
+	if o.FilePath != "" && len(o.Values) > 0 {
+		return fmt.Errorf("sdk-%s: can't use both override file and override values", sdkId)
+	}
+	if o.FilePath != "" {
+		if _, err := os.Stat(o.FilePath); errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("sdk-%s: couldn't find the override file %s", sdkId, o.FilePath)
+		}
+	}
+	for key, value := range o.Values {
+		switch value.(type) {
+		case bool, string, int, float64:
+		default:
+			return fmt.Errorf("sdk-%s: invalid override value for '%s', it must be a bool, string, int or float", sdkId, key)
+		}
+	}
+	return nil
+}
+
+func (u *UpstreamConfig) validate(sdkId string) error {
+	parsed, err := url.Parse(u.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("sdk-%s: invalid upstream proxy url, it must be an absolute http or https url", sdkId)
+	}
+	if u.MaxReconnectDelay < 1 {
+		return fmt.Errorf("sdk-%s: upstream max reconnect delay must be greater than 1 seconds", sdkId)
+	}
+	if u.ReadTimeout < 1 {
+		return fmt.Errorf("sdk-%s: upstream read timeout must be greater than 1 seconds", sdkId)
+	}
+	return nil
+}
+
 func (d *DiagConfig) validate() error {
 	if d.Port < 1 || d.Port > 65535 {
 		return fmt.Errorf("diag: invalid port %d", d.Port)
@@ -208,6 +383,29 @@ func (d *DiagConfig) validate() error {
 			return err
 		}
 	}
+	if d.IsStatusEnabled() {
+		if err := d.Status.validate("diag"); err != nil {
+			return err
+		}
+	}
+	if d.IsHealthEnabled() && d.Health.DegradedPolicy != DegradedReady && d.Health.DegradedPolicy != DegradedNotReady {
+		return fmt.Errorf("diag: invalid health degraded policy %s (only '%s' or '%s' allowed)", d.Health.DegradedPolicy, DegradedReady, DegradedNotReady)
+	}
+	if d.IsAdminEnabled() {
+		if err := d.Admin.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AdminConfig) validate() error {
+	if (a.Auth.User != "" && a.Auth.Password == "") || (a.Auth.Password != "" && a.Auth.User == "") {
+		return fmt.Errorf("diag: both admin basic auth user and password required")
+	}
+	if len(a.AuthHeaders) == 0 && a.Auth.User == "" {
+		return fmt.Errorf("diag: admin API requires either auth headers or basic auth to be configured")
+	}
 	return nil
 }
 
@@ -235,5 +433,8 @@ func (a *ProfileConfig) validate(c *GlobalOfflineConfig) error {
 	if a.WebhookSigningKey != "" && a.WebhookSignatureValidFor < 5 {
 		return fmt.Errorf("profile: webhook signature validity check must be greater than 5 seconds")
 	}
+	if a.SDKs.Init.Timeout < 0 {
+		return fmt.Errorf("profile: sdk init timeout must not be negative")
+	}
 	return nil
 }
@@ -64,13 +64,72 @@ func TestConfig_Validate(t *testing.T) {
 	t.Run("offline enabled without file and cache", func(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true}}}}
 		conf.setDefaults()
-		require.ErrorContains(t, conf.Validate(), "sdk-env1: offline mode requires either a configured cache or a local file")
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: offline mode requires either a configured cache, a local file or an upstream proxy")
 	})
 	t.Run("offline both local file and cache", func(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true, UseCache: true, Local: LocalConfig{FilePath: "file"}}}}}
 		conf.setDefaults()
 		require.ErrorContains(t, conf.Validate(), "sdk-env1: can't use both local file and cache for offline mode")
 	})
+	t.Run("offline both upstream and cache", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true, UseCache: true, Upstream: UpstreamConfig{Url: "http://upstream"}}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: can't use an upstream proxy together with a local file or cache for offline mode")
+	})
+	t.Run("offline upstream invalid url", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true, Upstream: UpstreamConfig{Url: "upstream:8050"}}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: invalid upstream proxy url, it must be an absolute http or https url")
+	})
+	t.Run("offline upstream invalid reconnect delay", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true, Upstream: UpstreamConfig{Url: "http://upstream"}}}}}
+		conf.setDefaults()
+		conf.SDKs["env1"].Offline.Upstream.MaxReconnectDelay = -1
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: upstream max reconnect delay must be greater than 1 seconds")
+	})
+	t.Run("offline upstream invalid read timeout", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true, Upstream: UpstreamConfig{Url: "http://upstream", MaxReconnectDelay: 1}}}}}
+		conf.setDefaults()
+		conf.SDKs["env1"].Offline.Upstream.ReadTimeout = -1
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: upstream read timeout must be greater than 1 seconds")
+	})
+	t.Run("overrides invalid behavior", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Overrides: OverrideConfig{Behavior: "local_only", Values: map[string]interface{}{"flag": true}}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: invalid overrides behavior, it must be 'local_over_remote' or 'remote_over_local'")
+	})
+	t.Run("overrides both file and values", func(t *testing.T) {
+		testutils.UseTempFile("", func(path string) {
+			conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Overrides: OverrideConfig{Behavior: OverrideLocalOverRemote, FilePath: path, Values: map[string]interface{}{"flag": true}}}}}
+			conf.setDefaults()
+			require.ErrorContains(t, conf.Validate(), "sdk-env1: can't use both override file and override values")
+		})
+	})
+	t.Run("overrides invalid file path", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Overrides: OverrideConfig{Behavior: OverrideLocalOverRemote, FilePath: "nonexisting"}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: couldn't find the override file nonexisting")
+	})
+	t.Run("overrides invalid value", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Overrides: OverrideConfig{Behavior: OverrideLocalOverRemote, Values: map[string]interface{}{"flag": []string{"a"}}}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: invalid override value for 'flag', it must be a bool, string, int or float")
+	})
+	t.Run("history negative size", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", History: HistoryConfig{Size: -1}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: history size must not be negative")
+	})
+	t.Run("history cache without cache", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", History: HistoryConfig{UseCache: true}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: storing the config history requires a configured cache")
+	})
+	t.Run("negative init timeout", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Init: InitConfig{Timeout: -1}}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sdk-env1: init timeout must not be negative")
+	})
 	t.Run("offline cache without redis", func(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key", Offline: OfflineConfig{Enabled: true, UseCache: true}}}}
 		conf.setDefaults()
@@ -95,6 +154,88 @@ func TestConfig_Validate(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{MongoDb: MongoDbConfig{Enabled: true}}, Grpc: GrpcConfig{Port: 100}, Diag: DiagConfig{Port: 90}, Http: HttpConfig{Port: 80}}
 		require.ErrorContains(t, conf.Validate(), "mongodb: invalid connection uri")
 	})
+	t.Run("sql invalid driver", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Sql: SqlConfig{Enabled: true, Driver: "oracle", DSN: "dsn"}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sql: invalid driver, it must be 'postgres', 'mysql', or 'sqlite'")
+	})
+	t.Run("sql missing dsn", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Sql: SqlConfig{Enabled: true, Driver: "sqlite"}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "sql: invalid data source name")
+	})
+	t.Run("sql invalid table", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Sql: SqlConfig{Enabled: true, Driver: "sqlite", DSN: "dsn"}}}
+		conf.setDefaults()
+		conf.Cache.Sql.Table = "cache; DROP TABLE users"
+		require.ErrorContains(t, conf.Validate(), "sql: invalid table name, it must contain only letters, digits, and underscores")
+	})
+	t.Run("filesystem missing path", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Filesystem: FilesystemConfig{Enabled: true}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "filesystem: cache directory path is required")
+	})
+	t.Run("local snapshot missing path", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, LocalSnapshot: LocalSnapshotConfig{Enabled: true}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "local_snapshot: directory path is required")
+	})
+	t.Run("negative cache ttl", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Ttl: -1}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "cache: ttl must not be negative")
+	})
+	t.Run("negative cache operation timeout", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}}
+		conf.setDefaults()
+		conf.Cache.OperationTimeoutMs = -1
+		require.ErrorContains(t, conf.Validate(), "cache: operation timeout must not be negative")
+	})
+	t.Run("negative cache ping interval", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}}
+		conf.setDefaults()
+		conf.Cache.PingInterval = -1
+		require.ErrorContains(t, conf.Validate(), "cache: ping interval must not be negative")
+	})
+	t.Run("invalid circuit breaker", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}}
+		conf.setDefaults()
+		conf.Cache.CircuitBreaker = CircuitBreakerConfig{Enabled: true, FailureThreshold: 0, OpenDuration: 10}
+		require.ErrorContains(t, conf.Validate(), "cache: circuit breaker failure threshold must be greater than 0")
+
+		conf.Cache.CircuitBreaker = CircuitBreakerConfig{Enabled: true, FailureThreshold: 1, OpenDuration: 0}
+		require.ErrorContains(t, conf.Validate(), "cache: circuit breaker open duration must be greater than 0 seconds")
+	})
+	t.Run("encryption missing key file", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Encryption: EncryptionConfig{Enabled: true}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "encryption: key file path is required")
+	})
+	t.Run("cache chain unknown entry", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Chain: []string{"memcached"}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "cache: invalid chain entry 'memcached', it must be one of redis, mongodb, dynamodb, sql, filesystem")
+	})
+	t.Run("cache chain duplicate entry", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Chain: []string{"filesystem", "filesystem"}, Filesystem: FilesystemConfig{Enabled: true, Path: "/tmp"}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "cache: 'filesystem' is listed multiple times in the chain")
+	})
+	t.Run("cache chain entry not enabled", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Chain: []string{"filesystem", "redis"}, Filesystem: FilesystemConfig{Enabled: true, Path: "/tmp"}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "cache: 'redis' is listed in the chain but it's not enabled")
+	})
+	t.Run("cache chain", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Chain: []string{"redis", "filesystem"}, Redis: RedisConfig{Enabled: true}, Filesystem: FilesystemConfig{Enabled: true, Path: "/tmp"}}}
+		conf.setDefaults()
+		require.NoError(t, conf.Validate())
+	})
+	t.Run("global offline with sql cache", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Sql: SqlConfig{Enabled: true, Driver: "sqlite", DSN: "dsn"}}, GlobalOfflineConfig: GlobalOfflineConfig{Enabled: true, CachePollInterval: 5}}
+		conf.setDefaults()
+		require.NoError(t, conf.Validate())
+	})
 	t.Run("mongodb invalid tls config", func(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{MongoDb: MongoDbConfig{Enabled: true, Url: "uri", Tls: TlsConfig{Enabled: true, Certificates: []CertConfig{{Key: "key"}}}}}}
 		conf.setDefaults()
@@ -108,6 +249,36 @@ func TestConfig_Validate(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true}}, Grpc: GrpcConfig{Port: 100}, Diag: DiagConfig{Port: 90}, Http: HttpConfig{Port: 80}}
 		require.ErrorContains(t, conf.Validate(), "redis: at least 1 server address required")
 	})
+	t.Run("redis sentinel with cluster mode", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost"}, MasterName: "master", ClusterMode: true}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "redis: sentinel master name and cluster mode can't be used together")
+	})
+	t.Run("redis sentinel credentials without master name", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost"}, SentinelPassword: "pass"}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "redis: sentinel credentials require a sentinel master name")
+	})
+	t.Run("redis replica reads on standalone", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost"}, ReadOnly: true}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "redis: replica reads require a sentinel or cluster setup")
+	})
+	t.Run("redis negative pool size", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost"}, PoolSize: -1}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "redis: pool size and min idle connections must not be negative")
+	})
+	t.Run("redis negative timeout", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost"}, ReadTimeoutMs: -1}}}
+		conf.setDefaults()
+		require.ErrorContains(t, conf.Validate(), "redis: timeouts must not be negative")
+	})
+	t.Run("redis sentinel", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost:26379"}, MasterName: "master", SentinelPassword: "pass", ReadOnly: true}}}
+		conf.setDefaults()
+		require.NoError(t, conf.Validate())
+	})
 	t.Run("redis invalid tls config", func(t *testing.T) {
 		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Cache: CacheConfig{Redis: RedisConfig{Enabled: true, Addresses: []string{"localhost"}, Tls: TlsConfig{Enabled: true, Certificates: []CertConfig{{Key: "key"}}}}}}
 		conf.setDefaults()
@@ -169,4 +340,29 @@ func TestConfig_Validate(t *testing.T) {
 			require.ErrorContains(t, conf.Validate(), "diag: invalid otlp protocol test (only 'http', 'https', or 'grpc' allowed)")
 		})
 	})
+	t.Run("health invalid degraded policy", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Diag: DiagConfig{Port: 90, Enabled: true, Health: HealthConfig{Enabled: true, DegradedPolicy: "test"}}, Http: HttpConfig{Port: 80}}
+		require.ErrorContains(t, conf.Validate(), "diag: invalid health degraded policy test (only 'ready' or 'not_ready' allowed)")
+	})
+	t.Run("status basic auth password missing", func(t *testing.T) {
+		conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Http: HttpConfig{Port: 80, Status: StatusConfig{Enabled: true, Auth: AuthConfig{User: "user"}}}}
+		require.ErrorContains(t, conf.Validate(), "http: both status basic auth user and password required")
+
+		conf = Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Diag: DiagConfig{Port: 90, Enabled: true, Status: StatusConfig{Enabled: true, Auth: AuthConfig{Password: "pass"}}}, Http: HttpConfig{Port: 80}}
+		require.ErrorContains(t, conf.Validate(), "diag: both status basic auth user and password required")
+	})
+	t.Run("admin", func(t *testing.T) {
+		t.Run("auth missing", func(t *testing.T) {
+			conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Diag: DiagConfig{Port: 90, Enabled: true, Admin: AdminConfig{Enabled: true}}, Http: HttpConfig{Port: 80}}
+			require.ErrorContains(t, conf.Validate(), "diag: admin API requires either auth headers or basic auth to be configured")
+		})
+		t.Run("basic auth password missing", func(t *testing.T) {
+			conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Diag: DiagConfig{Port: 90, Enabled: true, Admin: AdminConfig{Enabled: true, Auth: AuthConfig{User: "user"}}}, Http: HttpConfig{Port: 80}}
+			require.ErrorContains(t, conf.Validate(), "diag: both admin basic auth user and password required")
+		})
+		t.Run("valid", func(t *testing.T) {
+			conf := Config{SDKs: map[string]*SDKConfig{"env1": {Key: "Key"}}, Diag: DiagConfig{Port: 90, Enabled: true, Admin: AdminConfig{Enabled: true, AuthHeaders: map[string]string{"X-API-KEY": "secret"}}}, Http: HttpConfig{Port: 80}, Grpc: GrpcConfig{Port: 50051}}
+			require.NoError(t, conf.Validate())
+		})
+	})
 }
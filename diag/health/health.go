@@ -0,0 +1,158 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/sdk"
+)
+
+type Readiness struct {
+	Ready  bool                     `json:"ready"`
+	Reason string                   `json:"reason,omitempty"`
+	SDKs   map[string]*SdkReadiness `json:"sdks"`
+	Cache  *CacheReadiness          `json:"cache,omitempty"`
+}
+
+type SdkReadiness struct {
+	Ready       bool                `json:"ready"`
+	Required    bool                `json:"required"`
+	Initialized bool                `json:"initialized"`
+	ValidConfig bool                `json:"validConfig"`
+	Status      status.HealthStatus `json:"status"`
+	Reason      string              `json:"reason,omitempty"`
+}
+
+type CacheReadiness struct {
+	Ready  bool                `json:"ready"`
+	Status status.HealthStatus `json:"status"`
+}
+
+// Checker decides whether the Proxy is alive and whether it's ready to serve evaluation requests.
+type Checker struct {
+	conf           *config.HealthConfig
+	statusReporter status.Reporter
+	sdkRegistrar   atomic.Value
+}
+
+func NewChecker(conf *config.HealthConfig, statusReporter status.Reporter) *Checker {
+	return &Checker{
+		conf:           conf,
+		statusReporter: statusReporter,
+	}
+}
+
+// SetRegistrar makes the SDKs known to the readiness check. Until it's called, the Proxy is reported as not ready.
+func (c *Checker) SetRegistrar(sdkRegistrar sdk.Registrar) {
+	c.sdkRegistrar.Store(sdkRegistrar)
+}
+
+func (c *Checker) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJson(w, http.StatusOK, map[string]string{"status": "alive"})
+	}
+}
+
+func (c *Checker) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		readiness := c.Check()
+		code := http.StatusOK
+		if !readiness.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeJson(w, code, readiness)
+	}
+}
+
+func (c *Checker) Check() Readiness {
+	result := Readiness{Ready: true, SDKs: map[string]*SdkReadiness{}}
+	sdkRegistrar, ok := c.sdkRegistrar.Load().(sdk.Registrar)
+	if !ok {
+		result.Ready = false
+		result.Reason = "SDKs are not initialized yet"
+		return result
+	}
+	stat := c.statusReporter.GetStatus()
+	clients := sdkRegistrar.GetAll()
+	for sdkId, client := range clients {
+		result.SDKs[sdkId] = c.checkSdk(sdkId, client, &stat)
+	}
+	for _, sdkId := range c.conf.SDKs {
+		if _, ok := clients[sdkId]; !ok {
+			result.SDKs[sdkId] = &SdkReadiness{Required: true, Status: status.NA, Reason: "SDK not found"}
+		}
+	}
+	for _, sdkReadiness := range result.SDKs {
+		if sdkReadiness.Required && !sdkReadiness.Ready {
+			result.Ready = false
+			result.Reason = "not all required SDKs are ready"
+		}
+	}
+	if c.conf.RequireCache {
+		result.Cache = &CacheReadiness{Status: stat.Cache.Status}
+		result.Cache.Ready = stat.Cache.Status == status.Healthy || stat.Cache.Status == status.NA ||
+			(c.allowsDegraded(stat.Cache.Status) && hasSucceeded(stat.Cache.History))
+		if !result.Cache.Ready && result.Ready {
+			result.Ready = false
+			result.Reason = "cache is not reachable"
+		}
+	}
+	return result
+}
+
+func (c *Checker) checkSdk(sdkId string, client sdk.Client, stat *status.Status) *SdkReadiness {
+	res := &SdkReadiness{Required: len(c.conf.SDKs) == 0, Status: status.NA}
+	for _, id := range c.conf.SDKs {
+		if id == sdkId {
+			res.Required = true
+		}
+	}
+	if sdkStatus, ok := stat.SDKs[sdkId]; ok {
+		res.Status = sdkStatus.Source.Status
+	}
+	select {
+	case <-client.Ready():
+		res.Initialized = true
+	default:
+		res.Reason = "SDK is initializing"
+		return res
+	}
+	res.ValidConfig = client.IsInValidState()
+	switch {
+	case !res.ValidConfig:
+		res.Reason = "SDK has no valid config"
+	case res.Status == status.Down || (res.Status == status.Degraded && !c.allowsDegraded(res.Status)):
+		res.Reason = "SDK config source is " + string(res.Status)
+	default:
+		res.Ready = true
+	}
+	return res
+}
+
+// allowsDegraded reports whether the configured degraded policy lets the given state count as ready.
+// A component that is down is never ready.
+func (c *Checker) allowsDegraded(stat status.HealthStatus) bool {
+	return c.conf.DegradedPolicy != config.DegradedNotReady && stat == status.Degraded
+}
+
+// hasSucceeded reports whether the component came up at least once, a degraded component that never did is down.
+func hasSucceeded(history []status.Record) bool {
+	return slices.ContainsFunc(history, func(record status.Record) bool {
+		return !record.Error
+	})
+}
+
+func writeJson(w http.ResponseWriter, code int, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Liveness(t *testing.T) {
+	checker := NewChecker(&config.HealthConfig{Enabled: true}, status.NewEmptyReporter())
+
+	res := httptest.NewRecorder()
+	checker.LivenessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `{"status":"alive"}`, res.Body.String())
+}
+
+func TestChecker_Readiness(t *testing.T) {
+	t.Run("registrar not set", func(t *testing.T) {
+		checker := NewChecker(&config.HealthConfig{Enabled: true}, status.NewEmptyReporter())
+
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, readiness.Ready)
+		assert.Equal(t, "SDKs are not initialized yet", readiness.Reason)
+	})
+	t.Run("ready", func(t *testing.T) {
+		reporter := status.NewEmptyReporter()
+		reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+		<-reg.GetSdkOrNil("test").Ready()
+		checker := NewChecker(&config.HealthConfig{Enabled: true}, reporter)
+		checker.SetRegistrar(reg)
+
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, readiness.Ready)
+		assert.Equal(t, &SdkReadiness{Ready: true, Required: true, Initialized: true, ValidConfig: true, Status: status.Healthy}, readiness.SDKs["test"])
+		assert.Nil(t, readiness.Cache)
+	})
+	t.Run("no valid config", func(t *testing.T) {
+		reg := sdk.NewTestRegistrarTWithErrorServer(t)
+		<-reg.GetSdkOrNil("test").Ready()
+		checker := NewChecker(&config.HealthConfig{Enabled: true}, status.NewEmptyReporter())
+		checker.SetRegistrar(reg)
+
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, readiness.SDKs["test"].ValidConfig)
+		assert.Equal(t, "SDK has no valid config", readiness.SDKs["test"].Reason)
+	})
+	t.Run("required subset", func(t *testing.T) {
+		reg, _, _ := sdk.NewTestRegistrarT(t)
+		<-reg.GetSdkOrNil("test").Ready()
+		checker := NewChecker(&config.HealthConfig{Enabled: true, SDKs: []string{"missing"}}, status.NewEmptyReporter())
+		checker.SetRegistrar(reg)
+
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not all required SDKs are ready", readiness.Reason)
+		assert.False(t, readiness.SDKs["test"].Required)
+		assert.True(t, readiness.SDKs["test"].Ready)
+		assert.Equal(t, &SdkReadiness{Required: true, Status: status.NA, Reason: "SDK not found"}, readiness.SDKs["missing"])
+	})
+	t.Run("degraded policy", func(t *testing.T) {
+		reporter := status.NewEmptyReporter()
+		reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+		<-reg.GetSdkOrNil("test").Ready()
+		reporter.ReportError("test", "config fetch failed")
+		reporter.ReportError("test", "config fetch failed")
+
+		checker := NewChecker(&config.HealthConfig{Enabled: true, DegradedPolicy: config.DegradedReady}, reporter)
+		checker.SetRegistrar(reg)
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, status.Degraded, readiness.SDKs["test"].Status)
+
+		checker = NewChecker(&config.HealthConfig{Enabled: true, DegradedPolicy: config.DegradedNotReady}, reporter)
+		checker.SetRegistrar(reg)
+		readiness, code = checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "SDK config source is degraded", readiness.SDKs["test"].Reason)
+	})
+	t.Run("require cache", func(t *testing.T) {
+		reporter := status.NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true}})
+		reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+		<-reg.GetSdkOrNil("test").Ready()
+		checker := NewChecker(&config.HealthConfig{Enabled: true, RequireCache: true}, reporter)
+		checker.SetRegistrar(reg)
+
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "cache is not reachable", readiness.Reason)
+		assert.Equal(t, &CacheReadiness{Status: status.Initializing}, readiness.Cache)
+
+		reporter.ReportOk(status.Cache, "cache read succeeded")
+		readiness, code = checkReadiness(t, checker)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, &CacheReadiness{Ready: true, Status: status.Healthy}, readiness.Cache)
+	})
+	t.Run("require cache never reachable", func(t *testing.T) {
+		reporter := status.NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true}})
+		reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+		<-reg.GetSdkOrNil("test").Ready()
+		reporter.ReportError(status.Cache, "cache read failed")
+		reporter.ReportError(status.Cache, "cache read failed")
+		checker := NewChecker(&config.HealthConfig{Enabled: true, RequireCache: true, DegradedPolicy: config.DegradedReady}, reporter)
+		checker.SetRegistrar(reg)
+
+		readiness, code := checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "cache is not reachable", readiness.Reason)
+		assert.Equal(t, &CacheReadiness{Status: status.Degraded}, readiness.Cache)
+
+		reporter.ReportOk(status.Cache, "cache read succeeded")
+		reporter.ReportOk(status.Cache, "cache read succeeded")
+		reporter.ReportError(status.Cache, "cache read failed")
+		reporter.ReportError(status.Cache, "cache read failed")
+		readiness, code = checkReadiness(t, checker)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, &CacheReadiness{Ready: true, Status: status.Degraded}, readiness.Cache)
+
+		checker = NewChecker(&config.HealthConfig{Enabled: true, RequireCache: true, DegradedPolicy: config.DegradedNotReady}, reporter)
+		checker.SetRegistrar(reg)
+		readiness, code = checkReadiness(t, checker)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, &CacheReadiness{Status: status.Degraded}, readiness.Cache)
+	})
+}
+
+func checkReadiness(t *testing.T, checker *Checker) (Readiness, int) {
+	res := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody))
+	var result Readiness
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &result))
+	return result, res.Code
+}
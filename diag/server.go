@@ -9,15 +9,22 @@ import (
 	"time"
 
 	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/health"
 	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/stream"
+	"github.com/configcat/configcat-proxy/web/admin"
+	"github.com/configcat/configcat-proxy/web/mware"
 )
 
 type Server struct {
 	httpServer   *http.Server
+	mux          *http.ServeMux
 	log          log.Logger
 	conf         *config.DiagConfig
+	health       *health.Checker
 	errorChannel chan error
 }
 
@@ -30,10 +37,18 @@ func NewServer(conf *config.DiagConfig, telemetryReporter telemetry.Reporter, st
 	}
 
 	if conf.IsStatusEnabled() {
-		mux.Handle("/status", statusReporter.HttpHandler())
+		mux.Handle("/status", mware.SecureStatus(&conf.Status, diagLog, statusReporter.HttpHandler(&conf.Status)))
 		diagLog.Reportf("status enabled, accepting requests on path: /status")
 	}
 
+	var healthChecker *health.Checker
+	if conf.IsHealthEnabled() {
+		healthChecker = health.NewChecker(&conf.Health, statusReporter)
+		mux.Handle("/healthz", healthChecker.LivenessHandler())
+		mux.Handle("/readyz", healthChecker.ReadinessHandler())
+		diagLog.Reportf("health checks enabled, accepting requests on paths: /healthz, /readyz")
+	}
+
 	setupDebugEndpoints(mux)
 
 	httpServer := &http.Server{
@@ -47,11 +62,70 @@ func NewServer(conf *config.DiagConfig, telemetryReporter telemetry.Reporter, st
 	return &Server{
 		log:          diagLog,
 		httpServer:   httpServer,
+		mux:          mux,
 		conf:         conf,
+		health:       healthChecker,
 		errorChannel: errorChan,
 	}
 }
 
+// SetupReadiness hands the SDKs over to the readiness check, /readyz reports not ready until it's called.
+func (s *Server) SetupReadiness(sdkRegistrar sdk.Registrar) {
+	if s.health == nil {
+		return
+	}
+	s.health.SetRegistrar(sdkRegistrar)
+}
+
+func (s *Server) SetupAdminRoutes(sdkRegistrar sdk.Registrar, statusReporter status.Reporter, streamServers map[string]stream.Server) {
+	if !s.conf.IsAdminEnabled() {
+		return
+	}
+	adminServer := admin.NewServer(sdkRegistrar, statusReporter, streamServers, s.log)
+	sdkPath := "/admin/sdks/{" + admin.SdkIdPathVariable + "}"
+	logLevelPath := "/admin/log-levels/{" + admin.ComponentPathVariable + "...}"
+	endpoints := []struct {
+		path    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{path: "/admin/sdks", method: http.MethodGet, handler: adminServer.ListSdks},
+		{path: sdkPath, method: http.MethodGet, handler: adminServer.GetSdk},
+		{path: sdkPath, method: http.MethodPost, handler: adminServer.AddSdk},
+		{path: sdkPath, method: http.MethodPut, handler: adminServer.ResetSdk},
+		{path: sdkPath, method: http.MethodDelete, handler: adminServer.RemoveSdk},
+		{path: sdkPath + "/refresh", method: http.MethodPost, handler: adminServer.RefreshSdk},
+		{path: sdkPath + "/history", method: http.MethodGet, handler: adminServer.History},
+		{path: sdkPath + "/history/diff", method: http.MethodGet, handler: adminServer.DiffVersions},
+		{path: sdkPath + "/history/{" + admin.VersionPathVariable + "}", method: http.MethodGet, handler: adminServer.ConfigVersion},
+		{path: sdkPath + "/changes", method: http.MethodGet, handler: adminServer.Changes},
+		{path: sdkPath + "/pin", method: http.MethodPut, handler: adminServer.Pin},
+		{path: sdkPath + "/pin", method: http.MethodDelete, handler: adminServer.Unpin},
+		{path: "/admin/refresh", method: http.MethodPost, handler: adminServer.RefreshAll},
+		{path: "/admin/streams", method: http.MethodGet, handler: adminServer.StreamConnections},
+		{path: "/admin/log-levels", method: http.MethodGet, handler: adminServer.GetLogLevels},
+		{path: logLevelPath, method: http.MethodPut, handler: adminServer.SetLogLevel},
+		{path: logLevelPath, method: http.MethodDelete, handler: adminServer.ResetLogLevel},
+	}
+	for _, endpoint := range endpoints {
+		s.mux.HandleFunc(endpoint.method+" "+endpoint.path, s.secureAdminHandler(endpoint.handler))
+	}
+	s.log.Reportf("admin API enabled, accepting requests on path: /admin/*")
+}
+
+func (s *Server) secureAdminHandler(handler http.HandlerFunc) http.HandlerFunc {
+	if s.conf.Admin.Auth.User != "" && s.conf.Admin.Auth.Password != "" {
+		handler = mware.BasicAuth(s.conf.Admin.Auth.User, s.conf.Admin.Auth.Password, s.log, handler)
+	}
+	if len(s.conf.Admin.AuthHeaders) > 0 {
+		handler = mware.HeaderAuth(s.conf.Admin.AuthHeaders, s.log, handler)
+	}
+	if s.log.Level() == log.Debug {
+		handler = mware.DebugLog(s.log, handler)
+	}
+	return handler
+}
+
 func (s *Server) Listen() {
 	if s.httpServer == nil {
 		return
@@ -2,6 +2,7 @@ package diag
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/sdk"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,6 +20,7 @@ func TestNewServer(t *testing.T) {
 		Port:    5051,
 		Enabled: true,
 		Status:  config.StatusConfig{Enabled: true},
+		Health:  config.HealthConfig{Enabled: true},
 		Metrics: config.MetricsConfig{Enabled: true, Prometheus: config.PrometheusExporterConfig{Enabled: true}},
 	}
 
@@ -37,6 +40,24 @@ func TestNewServer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5051/healthz", http.NoBody)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5051/readyz", http.NoBody)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+	<-reg.GetSdkOrNil("test").Ready()
+	srv.SetupReadiness(reg)
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5051/readyz", http.NoBody)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
 	srv.Shutdown()
 
 	assert.Nil(t, readFromErrChan(errChan))
@@ -67,6 +88,109 @@ func TestNewServer_NotEnabled(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5052/readyz", http.NoBody)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	srv.Shutdown()
+
+	assert.Nil(t, readFromErrChan(errChan))
+}
+
+func TestNewServer_StatusAuth(t *testing.T) {
+	errChan := make(chan error)
+	conf := config.DiagConfig{
+		Port:    5054,
+		Enabled: true,
+		Status:  config.StatusConfig{Enabled: true, Auth: config.AuthConfig{User: "user", Password: "pass"}},
+	}
+
+	reporter := status.NewEmptyReporter()
+	srv := NewServer(&conf, telemetry.NewEmptyReporter(), reporter, log.NewNullLogger(), errChan)
+	srv.Listen()
+	time.Sleep(500 * time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:5054/status", http.NoBody)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5054/status", http.NoBody)
+	req.SetBasicAuth("user", "pass")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	srv.Shutdown()
+
+	assert.Nil(t, readFromErrChan(errChan))
+}
+
+func TestNewServer_Admin(t *testing.T) {
+	errChan := make(chan error)
+	conf := config.DiagConfig{
+		Port:    5053,
+		Enabled: true,
+		Admin:   config.AdminConfig{Enabled: true, AuthHeaders: map[string]string{"X-Admin-Token": "secret"}},
+	}
+
+	reporter := status.NewEmptyReporter()
+	reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+	srv := NewServer(&conf, telemetry.NewEmptyReporter(), reporter, log.NewNullLogger(), errChan)
+	srv.SetupAdminRoutes(reg, reporter, nil)
+	srv.Listen()
+	time.Sleep(500 * time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost:5053/admin/sdks/new", strings.NewReader(`{"key":"new-key"}`))
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodPost, "http://localhost:5053/admin/sdks/new", strings.NewReader(`{"key":"new-key"}`))
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.NotNil(t, reg.GetSdkOrNil("new"))
+
+	req, _ = http.NewRequest(http.MethodDelete, "http://localhost:5053/admin/sdks/new", http.NoBody)
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, reg.GetSdkOrNil("new"))
+
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5053/admin/sdks", http.NoBody)
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodPost, "http://localhost:5053/admin/sdks/test/refresh", http.NoBody)
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodGet, "http://localhost:5053/admin/sdks/test/history/diff?from=1", http.NoBody)
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodPut, "http://localhost:5053/admin/sdks/test/pin", strings.NewReader(`{"version":1}`))
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodPut, "http://localhost:5053/admin/log-levels/sdk-registrar", strings.NewReader(`{"level":"debug"}`))
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
 	srv.Shutdown()
 
 	assert.Nil(t, readFromErrChan(errChan))
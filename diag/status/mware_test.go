@@ -13,7 +13,7 @@ func TestInterceptSdk(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		reporter := NewEmptyReporter().(*reporter)
 		reporter.RegisterSdk("test", &config.SDKConfig{Key: "key"})
-		repSrv := httptest.NewServer(reporter.HttpHandler())
+		repSrv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		h := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			writer.WriteHeader(http.StatusOK)
 		})
@@ -37,7 +37,7 @@ func TestInterceptSdk(t *testing.T) {
 	t.Run("not modified", func(t *testing.T) {
 		reporter := NewEmptyReporter().(*reporter)
 		reporter.RegisterSdk("test", &config.SDKConfig{Key: "key"})
-		repSrv := httptest.NewServer(reporter.HttpHandler())
+		repSrv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		h := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			writer.WriteHeader(http.StatusNotModified)
 		})
@@ -61,7 +61,7 @@ func TestInterceptSdk(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		reporter := NewEmptyReporter().(*reporter)
 		reporter.RegisterSdk("test", &config.SDKConfig{Key: "key"})
-		repSrv := httptest.NewServer(reporter.HttpHandler())
+		repSrv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		h := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			writer.WriteHeader(http.StatusBadRequest)
 		})
@@ -2,7 +2,10 @@ package status
 
 import (
 	"encoding/json"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,11 +18,13 @@ type HealthStatus string
 type SDKMode string
 
 const (
-	Cache = "cache"
+	Cache   = "cache"
+	Profile = "profile"
 
-	FileSrc   SDKSource = "file"
-	RemoteSrc SDKSource = "remote"
-	CacheSrc  SDKSource = "cache"
+	FileSrc     SDKSource = "file"
+	RemoteSrc   SDKSource = "remote"
+	CacheSrc    SDKSource = "cache"
+	UpstreamSrc SDKSource = "upstream"
 
 	Healthy      HealthStatus = "healthy"
 	Degraded     HealthStatus = "degraded"
@@ -32,6 +37,8 @@ const (
 )
 
 const maxRecordCount = 5
+const maxHistoryCount = 25
+const cacheTierPrefix = Cache + "/"
 const maxLastErrorsMeaningDegraded = 2
 
 type Reporter interface {
@@ -40,45 +47,79 @@ type Reporter interface {
 	RemoveSdk(sdkId string)
 	ReportOk(component string, message string)
 	ReportError(component string, message string)
+	ReportOverrides(sdkId string, overrides map[string]time.Time)
+	ReportConfig(sdkId string, etag string, fetchTime time.Time, flagCount int)
 	GetStatus() Status
 
-	HttpHandler() http.HandlerFunc
+	HttpHandler(conf *config.StatusConfig) http.HandlerFunc
 }
 
 type Status struct {
-	Status HealthStatus          `json:"status"`
-	SDKs   map[string]*SdkStatus `json:"sdks"`
-	Cache  CacheStatus           `json:"cache"`
+	Status  HealthStatus          `json:"status"`
+	SDKs    map[string]*SdkStatus `json:"sdks"`
+	Cache   CacheStatus           `json:"cache"`
+	Profile *ProfileStatus        `json:"profile,omitempty"`
 }
 
 type SdkStatus struct {
-	SdkKey string          `json:"key"`
-	Mode   SDKMode         `json:"mode"`
-	Source SdkSourceStatus `json:"source"`
+	SdkKey              string               `json:"key"`
+	Mode                SDKMode              `json:"mode"`
+	Source              SdkSourceStatus      `json:"source"`
+	Overrides           map[string]time.Time `json:"overrides,omitempty"`
+	ConfigETag          string               `json:"configEtag,omitempty"`
+	FlagCount           int                  `json:"flagCount"`
+	LastSuccessfulFetch *time.Time           `json:"lastSuccessfulFetch,omitempty"`
+	ConfigAge           string               `json:"configAge,omitempty"`
+
+	sdkKey          string
+	configFetchTime time.Time
 }
 
 type SdkSourceStatus struct {
-	Type    SDKSource    `json:"type"`
-	Status  HealthStatus `json:"status"`
-	Records []string     `json:"records"`
+	Type                SDKSource    `json:"type"`
+	Status              HealthStatus `json:"status"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	Records             []string     `json:"records"`
+	History             []Record     `json:"history"`
 }
 
 type CacheStatus struct {
-	Status  HealthStatus `json:"status"`
-	Records []string     `json:"records"`
+	Status   HealthStatus      `json:"status"`
+	Topology string            `json:"topology,omitempty"`
+	Records  []string          `json:"records"`
+	History  []Record          `json:"history"`
+	Tiers    []CacheTierStatus `json:"tiers,omitempty"`
 }
 
-type record struct {
-	time    time.Time
-	isError bool
-	message string
+type CacheTierStatus struct {
+	Name     string       `json:"name"`
+	Status   HealthStatus `json:"status"`
+	Topology string       `json:"topology,omitempty"`
+	Records  []string     `json:"records"`
+	History  []Record     `json:"history"`
+}
+
+// ProfileStatus describes the health of the Proxy profile polling in auto configuration mode.
+type ProfileStatus struct {
+	Status              HealthStatus `json:"status"`
+	LastSuccessfulFetch *time.Time   `json:"lastSuccessfulFetch,omitempty"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	Records             []string     `json:"records"`
+	History             []Record     `json:"history"`
+}
+
+type Record struct {
+	Time    time.Time `json:"time"`
+	Error   bool      `json:"error"`
+	Message string    `json:"message"`
 }
 
 type reporter struct {
-	records map[string][]record
-	mu      sync.RWMutex
-	status  Status
-	conf    *config.CacheConfig
+	records  map[string][]Record
+	failures map[string]int
+	mu       sync.RWMutex
+	status   Status
+	conf     *config.CacheConfig
 }
 
 func NewEmptyReporter() Reporter {
@@ -87,8 +128,9 @@ func NewEmptyReporter() Reporter {
 
 func NewReporter(conf *config.CacheConfig) Reporter {
 	r := &reporter{
-		conf:    conf,
-		records: make(map[string][]record),
+		conf:     conf,
+		records:  make(map[string][]Record),
+		failures: make(map[string]int),
 		status: Status{
 			Status: Initializing,
 			Cache: CacheStatus{
@@ -97,9 +139,27 @@ func NewReporter(conf *config.CacheConfig) Reporter {
 			SDKs: map[string]*SdkStatus{},
 		},
 	}
+	for _, name := range conf.Chain {
+		r.status.Cache.Tiers = append(r.status.Cache.Tiers, CacheTierStatus{Name: name, Status: Initializing, Topology: topology(conf, name)})
+	}
+	if len(conf.Chain) == 0 && conf.Redis.Enabled {
+		r.status.Cache.Topology = conf.Redis.Topology()
+	}
 	return r
 }
 
+func topology(conf *config.CacheConfig, name string) string {
+	if name == config.CacheRedis {
+		return conf.Redis.Topology()
+	}
+	return ""
+}
+
+// CacheTier returns the component name used to report the status of a cache chain tier.
+func CacheTier(name string) string {
+	return cacheTierPrefix + name
+}
+
 func (r *reporter) RegisterSdk(sdkId string, conf *config.SDKConfig) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -107,6 +167,7 @@ func (r *reporter) RegisterSdk(sdkId string, conf *config.SDKConfig) {
 	status := &SdkStatus{
 		Mode:   Online,
 		SdkKey: utils.Obfuscate(conf.Key, 5),
+		sdkKey: conf.Key,
 		Source: SdkSourceStatus{
 			Type:   RemoteSrc,
 			Status: Initializing,
@@ -118,6 +179,8 @@ func (r *reporter) RegisterSdk(sdkId string, conf *config.SDKConfig) {
 		if conf.Offline.Local.FilePath != "" {
 			status.Source.Type = FileSrc
 			r.status.Cache.Status = NA
+		} else if conf.Offline.Upstream.Url != "" {
+			status.Source.Type = UpstreamSrc
 		} else {
 			status.Source.Type = CacheSrc
 		}
@@ -136,6 +199,7 @@ func (r *reporter) UpdateSdk(sdkId string, conf *config.SDKConfig) {
 
 	status := r.status.SDKs[sdkId]
 	status.SdkKey = utils.Obfuscate(conf.Key, 5)
+	status.sdkKey = conf.Key
 }
 
 func (r *reporter) RemoveSdk(sdkId string) {
@@ -144,6 +208,7 @@ func (r *reporter) RemoveSdk(sdkId string) {
 
 	delete(r.status.SDKs, sdkId)
 	delete(r.records, sdkId)
+	delete(r.failures, sdkId)
 	r.reCalcStatus()
 }
 
@@ -161,9 +226,46 @@ func (r *reporter) ReportError(component string, message string) {
 	r.appendRecord(component, message, true)
 }
 
-func (r *reporter) HttpHandler() http.HandlerFunc {
+// ReportOverrides records the active flag overrides of an SDK with their expiry.
+func (r *reporter) ReportOverrides(sdkId string, overrides map[string]time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status, ok := r.status.SDKs[sdkId]
+	if !ok {
+		return
+	}
+	if len(overrides) == 0 {
+		status.Overrides = nil
+	} else {
+		status.Overrides = maps.Clone(overrides)
+	}
+}
+
+// ReportConfig records the metadata of the config JSON an SDK currently serves.
+func (r *reporter) ReportConfig(sdkId string, etag string, fetchTime time.Time, flagCount int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status, ok := r.status.SDKs[sdkId]
+	if !ok {
+		return
+	}
+	status.ConfigETag = etag
+	status.FlagCount = flagCount
+	status.configFetchTime = fetchTime
+}
+
+// HttpHandler serves the status as JSON. SDK keys are only shown unmasked when the given config explicitly allows it.
+func (r *reporter) HttpHandler(conf *config.StatusConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
-		status, err := json.Marshal(r.GetStatus())
+		stat := r.GetStatus()
+		if !conf.MaskSdkKeys {
+			for _, sdk := range stat.SDKs {
+				sdk.SdkKey = sdk.sdkKey
+			}
+		}
+		status, err := json.Marshal(stat)
 		if err != nil {
 			http.Error(w, "Error producing status", http.StatusInternalServerError)
 		}
@@ -176,47 +278,115 @@ func (r *reporter) GetStatus() Status {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.status
+	sdks := make(map[string]*SdkStatus, len(r.status.SDKs))
+	for sdkId, sdk := range r.status.SDKs {
+		sdkCopy := *sdk
+		sdkCopy.ConfigAge = configAge(sdk)
+		sdks[sdkId] = &sdkCopy
+	}
+	cache := r.status.Cache
+	cache.Tiers = slices.Clone(cache.Tiers)
+	var profile *ProfileStatus
+	if r.status.Profile != nil {
+		profileCopy := *r.status.Profile
+		profile = &profileCopy
+	}
+	return Status{Status: r.status.Status, SDKs: sdks, Cache: cache, Profile: profile}
 }
 
-func (r *reporter) appendRecord(component string, message string, isError bool) {
-	if isError {
-		message = "[error] " + message
-	} else {
-		message = "[ok] " + message
+// configAge tells how long ago the served config was last confirmed by its source.
+func configAge(sdk *SdkStatus) string {
+	confirmed := sdk.configFetchTime
+	if sdk.LastSuccessfulFetch != nil && sdk.LastSuccessfulFetch.After(confirmed) {
+		confirmed = *sdk.LastSuccessfulFetch
 	}
-
-	recs, ok := r.records[component]
-	if !ok {
-		recs = make([]record, 0, maxRecordCount)
+	if confirmed.IsZero() {
+		return ""
 	}
-	recs = append(recs, record{time: time.Now(), isError: isError, message: message})
-	if len(recs) > maxRecordCount {
+	return time.Since(confirmed).Round(time.Second).String()
+}
+
+func (r *reporter) appendRecord(component string, message string, isError bool) {
+	now := time.Now()
+	recs := append(r.records[component], Record{Time: now, Error: isError, Message: message})
+	if len(recs) > maxHistoryCount {
 		recs = recs[1:]
 	}
 	r.records[component] = recs
+	if isError {
+		r.failures[component]++
+	} else {
+		r.failures[component] = 0
+	}
 	rec, stat := r.checkStatus(recs)
+	history := slices.Clone(recs)
 	if component == Cache {
 		r.status.Cache.Records = rec
+		r.status.Cache.History = history
 		r.status.Cache.Status = stat
+	} else if tier := r.cacheTier(component); tier != nil {
+		tier.Records = rec
+		tier.History = history
+		tier.Status = stat
+	} else if component == Profile {
+		if r.status.Profile == nil {
+			r.status.Profile = &ProfileStatus{Status: Initializing}
+		}
+		profile := r.status.Profile
+		profile.Records = rec
+		profile.History = history
+		profile.ConsecutiveFailures = r.failures[component]
+		if !isError {
+			profile.LastSuccessfulFetch = &now
+		}
+		profile.Status = downIfNeverRecovered(stat, profile.Status)
 	} else if sdk, ok := r.status.SDKs[component]; ok {
 		sdk.Source.Records = rec
-		if stat == Degraded && (sdk.Source.Status == Initializing || sdk.Source.Status == Down) {
-			stat = Down
+		sdk.Source.History = history
+		sdk.Source.ConsecutiveFailures = r.failures[component]
+		if !isError {
+			sdk.LastSuccessfulFetch = &now
 		}
-		sdk.Source.Status = stat
+		sdk.Source.Status = downIfNeverRecovered(stat, sdk.Source.Status)
 		r.reCalcStatus()
 	}
 }
 
-func (r *reporter) checkStatus(records []record) ([]string, HealthStatus) {
+// downIfNeverRecovered escalates a degraded state to down when the previous state wasn't healthy either.
+func downIfNeverRecovered(stat HealthStatus, previous HealthStatus) HealthStatus {
+	if stat == Degraded && (previous == Initializing || previous == Down) {
+		return Down
+	}
+	return stat
+}
+
+func (r *reporter) cacheTier(component string) *CacheTierStatus {
+	name, ok := strings.CutPrefix(component, cacheTierPrefix)
+	if !ok {
+		return nil
+	}
+	for i := range r.status.Cache.Tiers {
+		if r.status.Cache.Tiers[i].Name == name {
+			return &r.status.Cache.Tiers[i]
+		}
+	}
+	return nil
+}
+
+func (r *reporter) checkStatus(records []Record) ([]string, HealthStatus) {
 	length := len(records)
-	targetRecords := make([]string, length)
+	targetRecords := make([]string, 0, min(length, maxRecordCount))
 	var errorCount = 0
 	for i, msg := range records {
-		targetRecords[i] = msg.time.UTC().Format(time.RFC1123) + ": " + msg.message
+		if i >= length-maxRecordCount {
+			prefix := "[ok] "
+			if msg.Error {
+				prefix = "[error] "
+			}
+			targetRecords = append(targetRecords, msg.Time.UTC().Format(time.RFC1123)+": "+prefix+msg.Message)
+		}
 		if i >= length-maxLastErrorsMeaningDegraded {
-			if msg.isError {
+			if msg.Error {
 				errorCount++
 			} else {
 				errorCount--
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/stretchr/testify/assert"
@@ -15,7 +16,7 @@ func TestReporter_Online(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t", &config.SDKConfig{})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		stat := readStatus(srv.URL)
 
 		assert.Equal(t, Initializing, stat.Status)
@@ -40,7 +41,7 @@ func TestReporter_Online(t *testing.T) {
 	t.Run("down after 1 error, then ok, then degraded", func(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t", &config.SDKConfig{})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportError("t", "")
 		stat := readStatus(srv.URL)
 
@@ -79,7 +80,7 @@ func TestReporter_Online(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t1", &config.SDKConfig{})
 		reporter.RegisterSdk("t2", &config.SDKConfig{})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportOk("t1", "")
 		reporter.ReportError("t2", "")
 		stat := readStatus(srv.URL)
@@ -111,7 +112,7 @@ func TestReporter_Online(t *testing.T) {
 	t.Run("1 sdk, ok then remove", func(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t1", &config.SDKConfig{})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportOk("t1", "")
 		stat := readStatus(srv.URL)
 
@@ -135,7 +136,7 @@ func TestReporter_Online(t *testing.T) {
 	t.Run("max 5 records", func(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t", &config.SDKConfig{})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportOk("t", "m1")
 		reporter.ReportOk("t", "m2")
 		reporter.ReportOk("t", "m3")
@@ -155,7 +156,7 @@ func TestReporter_Online(t *testing.T) {
 
 func TestReporter_Report_NonExisting(t *testing.T) {
 	reporter := NewEmptyReporter()
-	srv := httptest.NewServer(reporter.HttpHandler())
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 
 	reporter.ReportOk("t1", "")
 	reporter.ReportError("t1", "")
@@ -183,7 +184,7 @@ func TestReporter_Report_NonExisting(t *testing.T) {
 
 func TestReporter_Key_Obfuscation(t *testing.T) {
 	reporter := NewEmptyReporter()
-	srv := httptest.NewServer(reporter.HttpHandler())
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 
 	reporter.RegisterSdk("t", &config.SDKConfig{Key: "XxPbCKmzIUGORk4vsufpzw/iC_KABprDEueeQs3yovVnQ"})
 	stat := readStatus(srv.URL)
@@ -196,11 +197,29 @@ func TestReporter_Key_Obfuscation(t *testing.T) {
 	assert.Equal(t, "****************************************rZM9w", stat.SDKs["t"].SdkKey)
 }
 
+func TestReporter_Overrides(t *testing.T) {
+	reporter := NewEmptyReporter()
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	reporter.ReportOverrides("t", map[string]time.Time{"flag": expiresAt}) // not registered, ignored
+	reporter.RegisterSdk("t", &config.SDKConfig{})
+	reporter.ReportOverrides("t", map[string]time.Time{"flag": expiresAt})
+	stat := readStatus(srv.URL)
+
+	assert.Equal(t, map[string]time.Time{"flag": expiresAt}, stat.SDKs["t"].Overrides)
+
+	reporter.ReportOverrides("t", map[string]time.Time{})
+	stat = readStatus(srv.URL)
+
+	assert.Nil(t, stat.SDKs["t"].Overrides)
+}
+
 func TestReporter_Offline(t *testing.T) {
 	t.Run("file", func(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, Local: config.LocalConfig{FilePath: "test"}}})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportOk("t", "")
 		stat := readStatus(srv.URL)
 
@@ -212,10 +231,23 @@ func TestReporter_Offline(t *testing.T) {
 		assert.Equal(t, NA, stat.Cache.Status)
 		assert.Equal(t, 0, len(stat.Cache.Records))
 	})
+	t.Run("upstream", func(t *testing.T) {
+		reporter := NewEmptyReporter()
+		reporter.RegisterSdk("t", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, Upstream: config.UpstreamConfig{Url: "http://upstream"}}})
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
+		reporter.ReportOk("t", "")
+		stat := readStatus(srv.URL)
+
+		assert.Equal(t, Healthy, stat.Status)
+		assert.Equal(t, Healthy, stat.SDKs["t"].Source.Status)
+		assert.Equal(t, Offline, stat.SDKs["t"].Mode)
+		assert.Equal(t, 1, len(stat.SDKs["t"].Source.Records))
+		assert.Equal(t, UpstreamSrc, stat.SDKs["t"].Source.Type)
+	})
 	t.Run("cache invalid", func(t *testing.T) {
 		reporter := NewEmptyReporter()
 		reporter.RegisterSdk("t", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, UseCache: true}})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		stat := readStatus(srv.URL)
 
 		assert.Equal(t, Down, stat.Status)
@@ -229,7 +261,7 @@ func TestReporter_Offline(t *testing.T) {
 	t.Run("cache err", func(t *testing.T) {
 		reporter := NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true}})
 		reporter.RegisterSdk("t", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, UseCache: true}})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportError("t", "")
 		reporter.ReportError("t", "")
 		stat := readStatus(srv.URL)
@@ -243,7 +275,7 @@ func TestReporter_Offline(t *testing.T) {
 	t.Run("cache valid", func(t *testing.T) {
 		reporter := NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true}})
 		reporter.RegisterSdk("t", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, UseCache: true}})
-		srv := httptest.NewServer(reporter.HttpHandler())
+		srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
 		reporter.ReportOk("t", "")
 		reporter.ReportOk(Cache, "")
 		stat := readStatus(srv.URL)
@@ -258,6 +290,51 @@ func TestReporter_Offline(t *testing.T) {
 	})
 }
 
+func TestReporter_CacheTiers(t *testing.T) {
+	reporter := NewReporter(&config.CacheConfig{
+		Chain:      []string{"redis", "filesystem"},
+		Redis:      config.RedisConfig{Enabled: true},
+		Filesystem: config.FilesystemConfig{Enabled: true},
+	})
+	reporter.RegisterSdk("t", &config.SDKConfig{})
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
+	stat := readStatus(srv.URL)
+
+	assert.Equal(t, 2, len(stat.Cache.Tiers))
+	assert.Equal(t, "redis", stat.Cache.Tiers[0].Name)
+	assert.Equal(t, Initializing, stat.Cache.Tiers[0].Status)
+	assert.Equal(t, "standalone", stat.Cache.Tiers[0].Topology)
+	assert.Equal(t, "filesystem", stat.Cache.Tiers[1].Name)
+	assert.Equal(t, Initializing, stat.Cache.Tiers[1].Status)
+	assert.Empty(t, stat.Cache.Tiers[1].Topology)
+	assert.Empty(t, stat.Cache.Topology)
+
+	reporter.ReportError(CacheTier("redis"), "")
+	reporter.ReportError(CacheTier("redis"), "")
+	reporter.ReportOk(CacheTier("filesystem"), "")
+	reporter.ReportOk(Cache, "")
+	reporter.ReportOk(CacheTier("unknown"), "")
+	stat = readStatus(srv.URL)
+
+	assert.Equal(t, Healthy, stat.Cache.Status)
+	assert.Equal(t, 1, len(stat.Cache.Records))
+	assert.Equal(t, Degraded, stat.Cache.Tiers[0].Status)
+	assert.Equal(t, 2, len(stat.Cache.Tiers[0].Records))
+	assert.Equal(t, Healthy, stat.Cache.Tiers[1].Status)
+	assert.Equal(t, 1, len(stat.Cache.Tiers[1].Records))
+}
+
+func TestReporter_CacheTopology(t *testing.T) {
+	reporter := NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{"sentinel:26379"}, MasterName: "master"}})
+	assert.Equal(t, "sentinel", reporter.GetStatus().Cache.Topology)
+
+	reporter = NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{"node1:6379", "node2:6379"}}})
+	assert.Equal(t, "cluster", reporter.GetStatus().Cache.Topology)
+
+	reporter = NewEmptyReporter()
+	assert.Empty(t, reporter.GetStatus().Cache.Topology)
+}
+
 func TestReporter_Degraded_Calc(t *testing.T) {
 	t.Run("1 record first, 1 error", func(t *testing.T) {
 		reporter := NewEmptyReporter().(*reporter)
@@ -356,6 +433,75 @@ func TestNewNullReporter(t *testing.T) {
 	assert.Empty(t, rep.GetStatus().SDKs)
 }
 
+func TestReporter_History(t *testing.T) {
+	reporter := NewEmptyReporter()
+	reporter.RegisterSdk("t", &config.SDKConfig{})
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
+	for i := 0; i < maxHistoryCount; i++ {
+		reporter.ReportOk("t", "ok")
+	}
+	reporter.ReportError("t", "e1")
+	reporter.ReportError("t", "e2")
+	stat := readStatus(srv.URL)
+
+	assert.Equal(t, maxRecordCount, len(stat.SDKs["t"].Source.Records))
+	assert.Equal(t, maxHistoryCount, len(stat.SDKs["t"].Source.History))
+	assert.Equal(t, Record{Time: stat.SDKs["t"].Source.History[maxHistoryCount-1].Time, Error: true, Message: "e2"}, stat.SDKs["t"].Source.History[maxHistoryCount-1])
+	assert.Equal(t, 2, stat.SDKs["t"].Source.ConsecutiveFailures)
+	assert.NotNil(t, stat.SDKs["t"].LastSuccessfulFetch)
+	assert.NotEmpty(t, stat.SDKs["t"].ConfigAge)
+
+	reporter.ReportOk("t", "ok")
+	stat = readStatus(srv.URL)
+
+	assert.Equal(t, 0, stat.SDKs["t"].Source.ConsecutiveFailures)
+}
+
+func TestReporter_Config(t *testing.T) {
+	reporter := NewEmptyReporter()
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
+
+	reporter.ReportConfig("t", "etag", time.Now(), 3) // not registered, ignored
+	reporter.RegisterSdk("t", &config.SDKConfig{})
+	stat := readStatus(srv.URL)
+
+	assert.Empty(t, stat.SDKs["t"].ConfigETag)
+	assert.Empty(t, stat.SDKs["t"].ConfigAge)
+
+	reporter.ReportConfig("t", "etag", time.Now().Add(-time.Minute), 3)
+	stat = readStatus(srv.URL)
+
+	assert.Equal(t, "etag", stat.SDKs["t"].ConfigETag)
+	assert.Equal(t, 3, stat.SDKs["t"].FlagCount)
+	assert.Equal(t, "1m0s", stat.SDKs["t"].ConfigAge)
+	assert.Nil(t, stat.SDKs["t"].LastSuccessfulFetch)
+}
+
+func TestReporter_Profile(t *testing.T) {
+	reporter := NewEmptyReporter()
+	srv := httptest.NewServer(reporter.HttpHandler(&config.StatusConfig{MaskSdkKeys: true}))
+	stat := readStatus(srv.URL)
+
+	assert.Nil(t, stat.Profile)
+
+	reporter.ReportError(Profile, "profile fetch failed")
+	stat = readStatus(srv.URL)
+
+	assert.Equal(t, Down, stat.Profile.Status)
+	assert.Equal(t, 1, stat.Profile.ConsecutiveFailures)
+	assert.Nil(t, stat.Profile.LastSuccessfulFetch)
+	assert.Equal(t, 1, len(stat.Profile.Records))
+
+	reporter.ReportOk(Profile, "profile fetched")
+	stat = readStatus(srv.URL)
+
+	assert.Equal(t, Healthy, stat.Profile.Status)
+	assert.Equal(t, 0, stat.Profile.ConsecutiveFailures)
+	assert.NotNil(t, stat.Profile.LastSuccessfulFetch)
+	assert.Equal(t, 2, len(stat.Profile.History))
+	assert.Equal(t, Initializing, stat.Status)
+}
+
 func readStatus(url string) Status {
 	client := http.Client{}
 	req, _ := http.NewRequest(http.MethodGet, url, http.NoBody)
@@ -7,6 +7,7 @@ import (
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/prometheus/otlptranslator"
+	"github.com/puzpuzpuz/xsync/v3"
 	otelhost "go.opentelemetry.io/contrib/instrumentation/host"
 	otelruntime "go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel/attribute"
@@ -21,15 +22,28 @@ import (
 type metricsHandler struct {
 	connections       otelmetric.Int64Gauge
 	streamMessageSent otelmetric.Int64Counter
+	configFetches     otelmetric.Int64Counter
+	cacheDuration     otelmetric.Float64Histogram
+	cacheErrors       otelmetric.Int64Counter
+	profilePolls      otelmetric.Int64Counter
+	evaluations       otelmetric.Int64Counter
 	provider          *metric.MeterProvider
 	log               log.Logger
 
+	fetchTimes *xsync.MapOf[string, time.Time]
+	evalFlags  *xsync.MapOf[string, *xsync.MapOf[string, struct{}]]
+
 	ctx       context.Context
 	ctxCancel func()
 }
 
 const (
 	meterName = "github.com/configcat/configcat-proxy"
+
+	// maxEvalFlagsPerSdk caps the number of distinct flag keys recorded per SDK in the evaluation counter,
+	// evaluations of further keys are recorded under otherFlag.
+	maxEvalFlagsPerSdk = 500
+	otherFlag          = "_other"
 )
 
 func newMetricsHandler(ctx context.Context, resource *resource.Resource, conf *config.MetricsConfig, log log.Logger) *metricsHandler {
@@ -116,13 +130,72 @@ func newMetricsHandlerWithOpts(opts []metric.Option, logger log.Logger) *metrics
 		return nil
 	}
 
+	configFetches, err := meter.Int64Counter("config.fetch.total",
+		otelmetric.WithDescription("Total number of config JSON downloads per SDK and result."))
+	if err != nil {
+		logger.Errorf("failed to configure config fetch counter: %s", err)
+		return nil
+	}
+
+	cacheDuration, err := meter.Float64Histogram("cache.operation.duration",
+		otelmetric.WithDescription("Duration of cache operations per backend."),
+		otelmetric.WithUnit("s"))
+	if err != nil {
+		logger.Errorf("failed to configure cache operation histogram: %s", err)
+		return nil
+	}
+
+	cacheErrors, err := meter.Int64Counter("cache.operation.errors.total",
+		otelmetric.WithDescription("Total number of failed cache operations per backend."))
+	if err != nil {
+		logger.Errorf("failed to configure cache error counter: %s", err)
+		return nil
+	}
+
+	profilePolls, err := meter.Int64Counter("profile.poll.total",
+		otelmetric.WithDescription("Total number of Proxy profile polls per result."))
+	if err != nil {
+		logger.Errorf("failed to configure profile poll counter: %s", err)
+		return nil
+	}
+
+	evaluations, err := meter.Int64Counter("eval.total",
+		otelmetric.WithDescription("Total number of feature flag evaluations per SDK and flag."))
+	if err != nil {
+		logger.Errorf("failed to configure evaluation counter: %s", err)
+		return nil
+	}
+
+	fetchTimes := xsync.NewMapOf[string, time.Time]()
+	_, err = meter.Float64ObservableGauge("config.age",
+		otelmetric.WithDescription("Time elapsed since the currently used config JSON was fetched, per SDK."),
+		otelmetric.WithUnit("s"),
+		otelmetric.WithFloat64Callback(func(_ context.Context, o otelmetric.Float64Observer) error {
+			fetchTimes.Range(func(sdkId string, fetchTime time.Time) bool {
+				o.Observe(time.Since(fetchTime).Seconds(), otelmetric.WithAttributes(attribute.Key("sdk").String(sdkId)))
+				return true
+			})
+			return nil
+		}))
+	if err != nil {
+		logger.Errorf("failed to configure config age gauge: %s", err)
+		return nil
+	}
+
 	ctx, ctxCancel := context.WithCancel(context.Background())
 
 	return &metricsHandler{
 		connections:       connections,
 		streamMessageSent: streamMessageSent,
+		configFetches:     configFetches,
+		cacheDuration:     cacheDuration,
+		cacheErrors:       cacheErrors,
+		profilePolls:      profilePolls,
+		evaluations:       evaluations,
 		provider:          provider,
 		log:               logger,
+		fetchTimes:        fetchTimes,
+		evalFlags:         xsync.NewMapOf[string, *xsync.MapOf[string, struct{}]](),
 		ctx:               ctx,
 		ctxCancel:         ctxCancel,
 	}
@@ -144,6 +217,54 @@ func (r *metricsHandler) addSentMessageCount(count int, sdkId string, streamType
 	))
 }
 
+func (r *metricsHandler) addConfigFetch(sdkId string, result string) {
+	r.configFetches.Add(r.ctx, 1, otelmetric.WithAttributes(
+		attribute.Key("sdk").String(sdkId),
+		attribute.Key("result").String(result),
+	))
+}
+
+func (r *metricsHandler) recordConfigFetchTime(sdkId string, fetchTime time.Time) {
+	r.fetchTimes.Store(sdkId, fetchTime)
+}
+
+func (r *metricsHandler) recordCacheOperation(backend string, operation string, duration time.Duration, err error) {
+	attrs := otelmetric.WithAttributes(
+		attribute.Key("backend").String(backend),
+		attribute.Key("operation").String(operation),
+	)
+	r.cacheDuration.Record(r.ctx, duration.Seconds(), attrs)
+	if err != nil {
+		r.cacheErrors.Add(r.ctx, 1, attrs)
+	}
+}
+
+func (r *metricsHandler) addProfilePoll(result string) {
+	r.profilePolls.Add(r.ctx, 1, otelmetric.WithAttributes(attribute.Key("result").String(result)))
+}
+
+func (r *metricsHandler) addEvaluation(sdkId string, flag string) {
+	flags, _ := r.evalFlags.LoadOrCompute(sdkId, func() *xsync.MapOf[string, struct{}] {
+		return xsync.NewMapOf[string, struct{}]()
+	})
+	if _, ok := flags.Load(flag); !ok {
+		if flags.Size() >= maxEvalFlagsPerSdk {
+			flag = otherFlag
+		} else {
+			flags.Store(flag, struct{}{})
+		}
+	}
+	r.evaluations.Add(r.ctx, 1, otelmetric.WithAttributes(
+		attribute.Key("sdk").String(sdkId),
+		attribute.Key("flag").String(flag),
+	))
+}
+
+func (r *metricsHandler) removeSdk(sdkId string) {
+	r.fetchTimes.Delete(sdkId)
+	r.evalFlags.Delete(sdkId)
+}
+
 func (r *metricsHandler) shutdown() {
 	r.log.Reportf("initiating server shutdown")
 	r.ctxCancel()
@@ -2,8 +2,11 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/log"
@@ -122,6 +125,123 @@ func TestConnection(t *testing.T) {
 		}}, m1, metricdatatest.IgnoreTimestamp())
 }
 
+func TestConfigFetchMetrics(t *testing.T) {
+	reader := metric.NewManualReader()
+	handler := newMetricsHandlerWithOpts([]metric.Option{metric.WithReader(reader)}, log.NewNullLogger())
+	defer handler.shutdown()
+
+	handler.addConfigFetch("test", FetchSuccess)
+	handler.addConfigFetch("test", FetchNotModified)
+	handler.addConfigFetch("test", FetchNotModified)
+	handler.recordConfigFetchTime("test", time.Now().Add(-time.Minute))
+
+	fetches := collectMetric(t, reader, "config.fetch.total")
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "config.fetch.total",
+		Description: "Total number of config JSON downloads per SDK and result.",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Value: 1, Attributes: attribute.NewSet(attribute.Key("sdk").String("test"), attribute.Key("result").String(FetchSuccess))},
+				{Value: 2, Attributes: attribute.NewSet(attribute.Key("sdk").String("test"), attribute.Key("result").String(FetchNotModified))},
+			},
+		}}, fetches, metricdatatest.IgnoreTimestamp())
+
+	age := collectMetric(t, reader, "config.age").Data.(metricdata.Gauge[float64])
+	assert.Len(t, age.DataPoints, 1)
+	assert.GreaterOrEqual(t, age.DataPoints[0].Value, 60.0)
+
+	handler.removeSdk("test")
+	// a gauge without data points isn't exported at all
+	assert.Nil(t, collectMetric(t, reader, "config.age").Data)
+}
+
+func TestCacheMetrics(t *testing.T) {
+	reader := metric.NewManualReader()
+	handler := newMetricsHandlerWithOpts([]metric.Option{metric.WithReader(reader)}, log.NewNullLogger())
+	defer handler.shutdown()
+
+	handler.recordCacheOperation("redis", CacheGet, time.Millisecond, nil)
+	handler.recordCacheOperation("redis", CacheSet, time.Millisecond, errors.New("failed"))
+
+	duration := collectMetric(t, reader, "cache.operation.duration").Data.(metricdata.Histogram[float64])
+	assert.Len(t, duration.DataPoints, 2)
+
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "cache.operation.errors.total",
+		Description: "Total number of failed cache operations per backend.",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Value: 1, Attributes: attribute.NewSet(attribute.Key("backend").String("redis"), attribute.Key("operation").String(CacheSet))},
+			},
+		}}, collectMetric(t, reader, "cache.operation.errors.total"), metricdatatest.IgnoreTimestamp())
+}
+
+func TestProfilePollMetrics(t *testing.T) {
+	reader := metric.NewManualReader()
+	handler := newMetricsHandlerWithOpts([]metric.Option{metric.WithReader(reader)}, log.NewNullLogger())
+	defer handler.shutdown()
+
+	handler.addProfilePoll(FetchSuccess)
+	handler.addProfilePoll(FetchFailure)
+
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "profile.poll.total",
+		Description: "Total number of Proxy profile polls per result.",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Value: 1, Attributes: attribute.NewSet(attribute.Key("result").String(FetchSuccess))},
+				{Value: 1, Attributes: attribute.NewSet(attribute.Key("result").String(FetchFailure))},
+			},
+		}}, collectMetric(t, reader, "profile.poll.total"), metricdatatest.IgnoreTimestamp())
+}
+
+func TestEvaluationMetrics(t *testing.T) {
+	reader := metric.NewManualReader()
+	handler := newMetricsHandlerWithOpts([]metric.Option{metric.WithReader(reader)}, log.NewNullLogger())
+	defer handler.shutdown()
+
+	for i := 0; i < maxEvalFlagsPerSdk+10; i++ {
+		handler.addEvaluation("test", "flag"+strconv.Itoa(i))
+	}
+	handler.addEvaluation("test", "flag0")
+
+	evals := collectMetric(t, reader, "eval.total").Data.(metricdata.Sum[int64])
+	assert.Len(t, evals.DataPoints, maxEvalFlagsPerSdk+1)
+	for _, dp := range evals.DataPoints {
+		flag, _ := dp.Attributes.Value("flag")
+		switch flag.AsString() {
+		case otherFlag:
+			assert.Equal(t, int64(10), dp.Value)
+		case "flag0":
+			assert.Equal(t, int64(2), dp.Value)
+		default:
+			assert.Equal(t, int64(1), dp.Value)
+		}
+	}
+}
+
+func collectMetric(t *testing.T, reader metric.Reader, name string) metricdata.Metrics {
+	rm := metricdata.ResourceMetrics{}
+	assert.NoError(t, reader.Collect(t.Context(), &rm))
+	for _, s := range rm.ScopeMetrics {
+		if s.Scope.Name != meterName {
+			continue
+		}
+		for _, m := range s.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	return metricdata.Metrics{}
+}
+
 func TestOtlpMetricsExporterGrpc(t *testing.T) {
 	collector, err := newInMemoryMetricGrpcCollector()
 	assert.NoError(t, err)
@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"net/http"
+)
+
+type clientInterceptor struct {
+	http.RoundTripper
+
+	reporter Reporter
+	sdkId    string
+}
+
+// InterceptSdk counts the config JSON downloads of an SDK by their outcome.
+func InterceptSdk(sdkId string, reporter Reporter, transport http.RoundTripper) http.RoundTripper {
+	return &clientInterceptor{reporter: reporter, RoundTripper: transport, sdkId: sdkId}
+}
+
+func (i *clientInterceptor) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := i.RoundTripper.RoundTrip(r)
+	switch {
+	case err != nil:
+		i.reporter.RecordConfigFetch(i.sdkId, FetchFailure)
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		i.reporter.RecordConfigFetch(i.sdkId, FetchSuccess)
+	case resp.StatusCode == http.StatusNotModified:
+		i.reporter.RecordConfigFetch(i.sdkId, FetchNotModified)
+	default:
+		i.reporter.RecordConfigFetch(i.sdkId, FetchFailure)
+	}
+	return resp, err
+}
@@ -0,0 +1,41 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fetchRecorder struct {
+	Reporter
+
+	results []string
+}
+
+func (f *fetchRecorder) RecordConfigFetch(_ string, result string) {
+	f.results = append(f.results, result)
+}
+
+func TestInterceptSdk(t *testing.T) {
+	code := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(code)
+	}))
+	defer srv.Close()
+
+	recorder := &fetchRecorder{}
+	client := http.Client{Transport: InterceptSdk("test", recorder, http.DefaultTransport)}
+	for _, c := range []int{http.StatusOK, http.StatusNotModified, http.StatusForbidden} {
+		code = c
+		resp, err := client.Get(srv.URL)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	srv.Close()
+	_, err := client.Get(srv.URL)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{FetchSuccess, FetchNotModified, FetchFailure, FetchFailure}, recorder.results)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/log"
@@ -46,6 +47,12 @@ type Reporter interface {
 
 	RecordConnections(count int64, sdkId string, streamType string, flag string)
 	AddSentMessageCount(count int, sdkId string, streamType string, flag string)
+	RecordConfigFetch(sdkId string, result string)
+	RecordConfigFetchTime(sdkId string, fetchTime time.Time)
+	RecordCacheOperation(backend string, operation string, duration time.Duration, err error)
+	RecordProfilePoll(result string)
+	RecordEvaluation(sdkId string, flag string)
+	RemoveSdk(sdkId string)
 
 	StartSpan(ctx context.Context, name string, attributes ...KV) (context.Context, trace.Span)
 	ForceFlush(ctx context.Context)
@@ -57,12 +64,20 @@ type Reporter interface {
 	InstrumentRedis(rdb redis.UniversalClient)
 	InstrumentMongoDb(opts *options.ClientOptions)
 	InstrumentAws(opts *aws.Config)
+	InstrumentSql(driverName string) string
 
 	Shutdown()
 }
 
 const (
 	traceName = "github.com/configcat/configcat-proxy"
+
+	FetchSuccess     = "success"
+	FetchNotModified = "not_modified"
+	FetchFailure     = "failure"
+
+	CacheGet = "get"
+	CacheSet = "set"
 )
 
 type reporter struct {
@@ -142,6 +157,48 @@ func (r *reporter) AddSentMessageCount(count int, sdkId string, streamType strin
 	r.metricsHandler.addSentMessageCount(count, sdkId, streamType, flag)
 }
 
+func (r *reporter) RecordConfigFetch(sdkId string, result string) {
+	if r.metricsHandler == nil {
+		return
+	}
+	r.metricsHandler.addConfigFetch(sdkId, result)
+}
+
+func (r *reporter) RecordConfigFetchTime(sdkId string, fetchTime time.Time) {
+	if r.metricsHandler == nil {
+		return
+	}
+	r.metricsHandler.recordConfigFetchTime(sdkId, fetchTime)
+}
+
+func (r *reporter) RecordCacheOperation(backend string, operation string, duration time.Duration, err error) {
+	if r.metricsHandler == nil {
+		return
+	}
+	r.metricsHandler.recordCacheOperation(backend, operation, duration, err)
+}
+
+func (r *reporter) RecordProfilePoll(result string) {
+	if r.metricsHandler == nil {
+		return
+	}
+	r.metricsHandler.addProfilePoll(result)
+}
+
+func (r *reporter) RecordEvaluation(sdkId string, flag string) {
+	if r.metricsHandler == nil {
+		return
+	}
+	r.metricsHandler.addEvaluation(sdkId, flag)
+}
+
+func (r *reporter) RemoveSdk(sdkId string) {
+	if r.metricsHandler == nil {
+		return
+	}
+	r.metricsHandler.removeSdk(sdkId)
+}
+
 func (r *reporter) StartSpan(ctx context.Context, name string, attributes ...KV) (context.Context, trace.Span) {
 	if r.tracer == nil {
 		return noop.NewTracerProvider().Tracer("noop").Start(ctx, "noop", trace.WithAttributes(toAttributeArray(attributes...)...))
@@ -226,6 +283,18 @@ func (r *reporter) InstrumentAws(opts *aws.Config) {
 	}
 }
 
+func (r *reporter) InstrumentSql(driverName string) string {
+	if r.traceHandler != nil {
+		instrumented, err := otelsql.Register(driverName, otelsql.WithTracerProvider(r.traceHandler.provider))
+		if err != nil {
+			r.log.Errorf("failed to instrument sql: %v", err)
+			return driverName
+		}
+		return instrumented
+	}
+	return driverName
+}
+
 func (r *reporter) Shutdown() {
 	if r.metricsHandler != nil {
 		r.metricsHandler.shutdown()
@@ -1,6 +1,7 @@
 package telemetry
 
 import (
+	"database/sql"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -21,6 +22,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/grpc"
+	_ "modernc.org/sqlite"
 )
 
 func TestHandler_Metrics_Prometheus_Export(t *testing.T) {
@@ -303,6 +305,15 @@ func TestHandler_Traces_Otlp_Export(t *testing.T) {
 
 		assert.True(t, collector.hasTrace("DynamoDB.DescribeTable"))
 	})
+	t.Run("sql", func(t *testing.T) {
+		db, _ := sql.Open(handler.InstrumentSql("sqlite"), ":memory:")
+		defer func() { _ = db.Close() }()
+		_, _ = db.ExecContext(t.Context(), "CREATE TABLE test (id INTEGER)")
+
+		handler.ForceFlush(t.Context())
+
+		assert.True(t, collector.hasTrace("sql.conn.exec"))
+	})
 }
 
 func Test_Empty_Instrument(t *testing.T) {
@@ -327,5 +338,7 @@ func Test_Empty_Instrument(t *testing.T) {
 	handler.InstrumentAws(conf)
 	assert.Empty(t, conf.APIOptions)
 
+	assert.Equal(t, "sqlite", handler.InstrumentSql("sqlite"))
+
 	handler.Shutdown()
 }
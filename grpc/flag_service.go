@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/grpc/proto"
@@ -11,7 +12,9 @@ import (
 	"github.com/configcat/configcat-proxy/sdk"
 	"github.com/configcat/configcat-proxy/stream"
 	configcat "github.com/configcat/go-sdk/v9"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -98,14 +101,32 @@ func (s *flagService) EvalAllFlagsStream(req *proto.EvalRequest, evalStream prot
 	}
 }
 
-func (s *flagService) EvalFlag(_ context.Context, req *proto.EvalRequest) (*proto.EvalResponse, error) {
+func (s *flagService) EvalFlag(ctx context.Context, req *proto.EvalRequest) (*proto.EvalResponse, error) {
 	var user model.UserAttrs
+	defaultValue := getDefaultValue(req)
 	sdkClient, err := s.parseEvalRequest(req, &user, true)
 	if err != nil {
-		return nil, err
+		if sdkClient != nil && defaultValue != nil {
+			if !errors.Is(err, sdk.ErrNotReady) {
+				payload := model.DefaultPayload(defaultValue, model.ReasonError, model.ErrorCodeGeneral, status.Convert(err).Message())
+				return s.toPayload(&payload), nil
+			}
+			if sdkClient.ServeDefaultsWhileInitializing() {
+				payload := model.DefaultPayload(defaultValue, model.ReasonDefault, model.ErrorCodeProviderNotReady, err.Error())
+				return s.toPayload(&payload), nil
+			}
+		}
+		return nil, toStatusError(ctx, err)
 	}
 	value := sdkClient.Eval(req.GetKey(), user)
+	if value.Error == nil && defaultValue != nil {
+		value.Error = model.CheckDefaultType(value.Value, defaultValue)
+	}
 	if value.Error != nil {
+		if defaultValue != nil {
+			payload := model.DefaultPayload(defaultValue, model.ReasonError, model.ErrorCodeOf(value.Error), value.Error.Error())
+			return s.toPayload(&payload), nil
+		}
 		var errKeyNotFound configcat.ErrKeyNotFound
 		if errors.As(value.Error, &errKeyNotFound) {
 			return nil, status.Error(codes.NotFound, "feature flag or setting with key '"+req.GetKey()+"' not found")
@@ -117,11 +138,11 @@ func (s *flagService) EvalFlag(_ context.Context, req *proto.EvalRequest) (*prot
 	return s.toPayload(&payload), nil
 }
 
-func (s *flagService) EvalAllFlags(_ context.Context, req *proto.EvalRequest) (*proto.EvalAllResponse, error) {
+func (s *flagService) EvalAllFlags(ctx context.Context, req *proto.EvalRequest) (*proto.EvalAllResponse, error) {
 	var user model.UserAttrs
 	sdkClient, err := s.parseEvalRequest(req, &user, false)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(ctx, err)
 	}
 	values := sdkClient.EvalAll(user)
 	final := make(map[string]*proto.EvalResponse)
@@ -132,7 +153,7 @@ func (s *flagService) EvalAllFlags(_ context.Context, req *proto.EvalRequest) (*
 	return &proto.EvalAllResponse{Values: final}, nil
 }
 
-func (s *flagService) GetKeys(_ context.Context, req *proto.KeysRequest) (*proto.KeysResponse, error) {
+func (s *flagService) GetKeys(ctx context.Context, req *proto.KeysRequest) (*proto.KeysResponse, error) {
 	sdkId, sdkKey := identifyTarget(req.GetTarget(), req.GetSdkId())
 	if sdkId == "" && sdkKey == "" {
 		return nil, status.Error(codes.InvalidArgument, "either the sdk id or the sdk key parameter must be set")
@@ -148,6 +169,9 @@ func (s *flagService) GetKeys(_ context.Context, req *proto.KeysRequest) (*proto
 	if sdkClient == nil {
 		return nil, status.Error(codes.InvalidArgument, "could not identify a configured SDK")
 	}
+	if err := sdkClient.WaitReady(); err != nil {
+		return nil, toStatusError(ctx, err)
+	}
 	if !sdkClient.IsInValidState() {
 		return nil, status.Error(codes.Internal, "requested SDK is in an invalid state; please check the logs for more details")
 	}
@@ -181,7 +205,7 @@ func (s *flagService) Refresh(ctx context.Context, req *proto.RefreshRequest) (*
 }
 
 func (s *flagService) toPayload(resp *model.ResponsePayload) *proto.EvalResponse {
-	payload := &proto.EvalResponse{VariationId: resp.VariationId}
+	payload := &proto.EvalResponse{VariationId: resp.VariationId, Reason: resp.Reason, ErrorCode: resp.ErrorCode, ErrorMessage: resp.ErrorMessage}
 	if boolVal, ok := resp.Value.(bool); ok {
 		payload.Value = &proto.EvalResponse_BoolValue{BoolValue: boolVal}
 	} else if intVal, ok := resp.Value.(int); ok {
@@ -232,6 +256,7 @@ func (s *flagService) parseEvalStreamRequest(req *proto.EvalRequest, user *model
 	return str, nil
 }
 
+// parseEvalRequest hands back the client even when it's not ready or invalid, so EvalFlag can fall back to the request's default value.
 func (s *flagService) parseEvalRequest(req *proto.EvalRequest, user *model.UserAttrs, checkKey bool) (sdk.Client, error) {
 	sdkId, sdkKey := identifyTarget(req.GetTarget(), req.GetSdkId())
 	if sdkId == "" && sdkKey == "" {
@@ -254,12 +279,39 @@ func (s *flagService) parseEvalRequest(req *proto.EvalRequest, user *model.UserA
 	if sdkClient == nil {
 		return nil, status.Error(codes.InvalidArgument, "could not identify a configured SDK")
 	}
+	if err := sdkClient.WaitReady(); err != nil {
+		return sdkClient, err
+	}
 	if !sdkClient.IsInValidState() {
-		return nil, status.Error(codes.Internal, "requested SDK is in an invalid state; please check the logs for more details")
+		return sdkClient, status.Error(codes.Internal, "requested SDK is in an invalid state; please check the logs for more details")
 	}
 	return sdkClient, nil
 }
 
+// toStatusError turns ErrNotReady into Unavailable, asking the caller to retry later via the 'retry-after' response header.
+func toStatusError(ctx context.Context, err error) error {
+	if !errors.Is(err, sdk.ErrNotReady) {
+		return err
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(sdk.NotReadyRetryAfter)))
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+func getDefaultValue(req *proto.EvalRequest) interface{} {
+	switch v := req.GetDefaultValue().(type) {
+	case *proto.EvalRequest_DefaultIntValue:
+		return int(v.DefaultIntValue)
+	case *proto.EvalRequest_DefaultDoubleValue:
+		return v.DefaultDoubleValue
+	case *proto.EvalRequest_DefaultStringValue:
+		return v.DefaultStringValue
+	case *proto.EvalRequest_DefaultBoolValue:
+		return v.DefaultBoolValue
+	default:
+		return nil
+	}
+}
+
 func identifyTarget(target *proto.Target, sdkId string) (string, string) {
 	if target == nil {
 		return sdkId, ""
@@ -237,6 +237,38 @@ func TestGrpc_EvalFlag(t *testing.T) {
 	assert.Equal(t, "test2", resp.GetStringValue())
 }
 
+func TestGrpc_EvalFlag_DefaultValue(t *testing.T) {
+	_, key, url := newFlagServer(t, map[string]*configcattest.Flag{
+		"flag": {
+			Default: "test1",
+		},
+	})
+	conn := createFlagServiceConnWithManualRegistrar(t, url, key)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := proto.NewFlagServiceClient(conn)
+	target := &proto.Target{Identifier: &proto.Target_SdkId{SdkId: "test"}}
+	resp, err := client.EvalFlag(t.Context(), &proto.EvalRequest{Key: "non-existing", Target: target, DefaultValue: &proto.EvalRequest_DefaultStringValue{DefaultStringValue: "fallback"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "fallback", resp.GetStringValue())
+	assert.Equal(t, "ERROR", resp.GetReason())
+	assert.Equal(t, "FLAG_NOT_FOUND", resp.GetErrorCode())
+	assert.NotEmpty(t, resp.GetErrorMessage())
+
+	resp, err = client.EvalFlag(t.Context(), &proto.EvalRequest{Key: "flag", Target: target, DefaultValue: &proto.EvalRequest_DefaultIntValue{DefaultIntValue: 5}})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), resp.GetIntValue())
+	assert.Equal(t, "TYPE_MISMATCH", resp.GetErrorCode())
+
+	resp, err = client.EvalFlag(t.Context(), &proto.EvalRequest{Key: "flag", Target: target, DefaultValue: &proto.EvalRequest_DefaultStringValue{DefaultStringValue: "fallback"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "test1", resp.GetStringValue())
+	assert.Empty(t, resp.GetReason())
+	assert.Empty(t, resp.GetErrorCode())
+}
+
 func TestGrpc_EvalFlag_Old(t *testing.T) {
 	h, key, url := newFlagServer(t, map[string]*configcattest.Flag{
 		"flag": {
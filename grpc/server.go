@@ -12,6 +12,7 @@ import (
 	"github.com/configcat/configcat-proxy/grpc/proto"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/stream"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
@@ -128,6 +129,10 @@ func (s *Server) runHealthCheck() {
 	}
 }
 
+func (s *Server) StreamServer() stream.Server {
+	return s.flagService.streamServer
+}
+
 func (s *Server) Shutdown() {
 	s.log.Reportf("initiating server shutdown")
 	close(s.stop)
@@ -4,6 +4,8 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/configcat/go-sdk/v9"
 )
@@ -25,6 +27,10 @@ type Logger interface {
 
 	// Reportf logs regardless of level
 	Reportf(format string, args ...interface{})
+	// SetComponentLevel overrides the level of every logger (and its sub-loggers) having the given prefix, "" means all loggers
+	SetComponentLevel(component string, level Level)
+	ResetComponentLevel(component string)
+	ComponentLevels() map[string]Level
 }
 
 const (
@@ -40,10 +46,21 @@ type logger struct {
 	errorLogger *log.Logger
 	outLogger   *log.Logger
 	prefix      string
+	overrides   *levelOverrides
+}
+
+type levelOverrides struct {
+	levels map[string]Level
+	mu     sync.RWMutex
 }
 
 func NewNullLogger() Logger {
-	return &logger{level: None}
+	return &logger{
+		level:       None,
+		errorLogger: log.New(io.Discard, "", 0),
+		outLogger:   log.New(io.Discard, "", 0),
+		overrides:   newLevelOverrides(),
+	}
 }
 
 func NewDebugLogger() Logger {
@@ -51,6 +68,7 @@ func NewDebugLogger() Logger {
 		level:       Debug,
 		errorLogger: log.New(os.Stderr, "", log.Ldate),
 		outLogger:   log.New(os.Stdout, "", log.Ldate),
+		overrides:   newLevelOverrides(),
 	}
 }
 
@@ -59,6 +77,7 @@ func NewLogger(err io.Writer, out io.Writer, level Level) Logger {
 		level:       level,
 		errorLogger: log.New(err, "", log.Ldate|log.Ltime|log.LUTC),
 		outLogger:   log.New(out, "", log.Ldate|log.Ltime|log.LUTC),
+		overrides:   newLevelOverrides(),
 	}
 }
 
@@ -68,6 +87,7 @@ func (l *logger) WithLevel(level Level) Logger {
 		errorLogger: l.errorLogger,
 		outLogger:   l.outLogger,
 		prefix:      l.prefix,
+		overrides:   l.overrides,
 	}
 }
 
@@ -80,11 +100,12 @@ func (l *logger) WithPrefix(prefix string) Logger {
 		errorLogger: l.errorLogger,
 		outLogger:   l.outLogger,
 		prefix:      prefix,
+		overrides:   l.overrides,
 	}
 }
 
 func (l *logger) GetConfigCatLevel() configcat.LogLevel {
-	switch l.level {
+	switch l.Level() {
 	case Debug:
 		return configcat.LogLevelDebug
 	case Info:
@@ -101,9 +122,34 @@ func (l *logger) GetConfigCatLevel() configcat.LogLevel {
 }
 
 func (l *logger) Level() Level {
+	if lvl, ok := l.overrides.lookup(l.prefix); ok {
+		return lvl
+	}
 	return l.level
 }
 
+func (l *logger) SetComponentLevel(component string, level Level) {
+	l.overrides.mu.Lock()
+	defer l.overrides.mu.Unlock()
+	l.overrides.levels[component] = level
+}
+
+func (l *logger) ResetComponentLevel(component string) {
+	l.overrides.mu.Lock()
+	defer l.overrides.mu.Unlock()
+	delete(l.overrides.levels, component)
+}
+
+func (l *logger) ComponentLevels() map[string]Level {
+	l.overrides.mu.RLock()
+	defer l.overrides.mu.RUnlock()
+	levels := make(map[string]Level, len(l.overrides.levels))
+	for component, level := range l.overrides.levels {
+		levels[component] = level
+	}
+	return levels
+}
+
 func (l *logger) Debugf(format string, values ...interface{}) {
 	l.logf(Debug, format, values...)
 }
@@ -121,7 +167,7 @@ func (l *logger) Errorf(format string, values ...interface{}) {
 }
 
 func (l *logger) Reportf(format string, values ...interface{}) {
-	if l.level == None {
+	if l.Level() == None {
 		return
 	}
 	pref := ""
@@ -136,7 +182,7 @@ func (l *logger) Reportf(format string, values ...interface{}) {
 }
 
 func (l *logger) logf(level Level, format string, values ...interface{}) {
-	if level >= l.level {
+	if level >= l.Level() {
 		var lo *log.Logger
 		if level == Error {
 			lo = l.errorLogger
@@ -151,6 +197,63 @@ func (l *logger) logf(level Level, format string, values ...interface{}) {
 	}
 }
 
+func newLevelOverrides() *levelOverrides {
+	return &levelOverrides{levels: make(map[string]Level)}
+}
+
+// lookup walks from the most specific prefix towards the root one, e.g. "http/sse" -> "http" -> ""
+func (o *levelOverrides) lookup(prefix string) (Level, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if len(o.levels) == 0 {
+		return None, false
+	}
+	for {
+		if lvl, ok := o.levels[prefix]; ok {
+			return lvl, true
+		}
+		if prefix == "" {
+			return None, false
+		}
+		idx := strings.LastIndex(prefix, "/")
+		if idx < 0 {
+			prefix = ""
+		} else {
+			prefix = prefix[:idx]
+		}
+	}
+}
+
+func ParseLevel(level string) (Level, bool) {
+	switch level {
+	case "debug":
+		return Debug, true
+	case "info":
+		return Info, true
+	case "warn":
+		return Warn, true
+	case "error":
+		return Error, true
+	case "none":
+		return None, true
+	}
+	return None, false
+}
+
+func (level Level) String() string {
+	switch level {
+	case Debug:
+		return "debug"
+	case Info:
+		return "info"
+	case Warn:
+		return "warn"
+	case Error:
+		return "error"
+	}
+	return "none"
+}
+
 func (level Level) prefix() string {
 	switch level {
 	case Debug:
@@ -93,6 +93,45 @@ func TestLogger(t *testing.T) {
 		l.Errorf("error")
 		assert.Contains(t, err.String(), "[error] <pref1/pref2> error")
 	})
+	t.Run("component level", func(t *testing.T) {
+		var out, err bytes.Buffer
+		root := NewLogger(&err, &out, Warn)
+		l := root.WithPrefix("pref1").WithPrefix("pref2")
+		other := root.WithPrefix("pref3")
+		l.Debugf("debug1")
+		assert.NotContains(t, out.String(), "debug1")
+
+		root.SetComponentLevel("pref1", Debug)
+		assert.Equal(t, Debug, l.Level())
+		assert.Equal(t, Warn, other.Level())
+		assert.Equal(t, map[string]Level{"pref1": Debug}, other.ComponentLevels())
+		l.Debugf("debug2")
+		other.Debugf("debug3")
+		assert.Contains(t, out.String(), "[debug] <pref1/pref2> debug2")
+		assert.NotContains(t, out.String(), "debug3")
+
+		other.SetComponentLevel("pref1/pref2", Error)
+		assert.Equal(t, Error, l.Level())
+
+		root.ResetComponentLevel("pref1/pref2")
+		root.ResetComponentLevel("pref1")
+		assert.Equal(t, Warn, l.Level())
+
+		root.SetComponentLevel("", Info)
+		assert.Equal(t, Info, l.Level())
+		assert.Equal(t, Info, root.Level())
+		root.ResetComponentLevel("")
+		assert.Empty(t, root.ComponentLevels())
+	})
+	t.Run("parse level", func(t *testing.T) {
+		for _, level := range []Level{Debug, Info, Warn, Error, None} {
+			parsed, ok := ParseLevel(level.String())
+			assert.True(t, ok)
+			assert.Equal(t, level, parsed)
+		}
+		_, ok := ParseLevel("invalid")
+		assert.False(t, ok)
+	})
 	t.Run("null logger", func(t *testing.T) {
 		l := NewNullLogger()
 		l.Debugf("debug")
@@ -100,6 +139,8 @@ func TestLogger(t *testing.T) {
 		l.Warnf("warn")
 		l.Errorf("error")
 		l.Reportf("rep")
+		l.SetComponentLevel("", Debug)
+		l.Debugf("debug")
 	})
 	t.Run("debug logger", func(t *testing.T) {
 		l := NewDebugLogger()
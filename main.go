@@ -10,6 +10,7 @@ import (
 	"github.com/configcat/configcat-proxy/grpc"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/stream"
 	"github.com/configcat/configcat-proxy/web"
 	"os"
 	"os/signal"
@@ -23,6 +24,10 @@ const (
 )
 
 func main() {
+	if isCommand(os.Args) {
+		os.Exit(runCommand(os.Args, os.Stdout, os.Stderr))
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
 
@@ -46,6 +51,12 @@ func run(closeSignal chan os.Signal) int {
 		logger.Errorf("%s", err)
 		return exitFailure
 	}
+	for _, env := range conf.UnknownEnvVars() {
+		logger.Warnf("unknown environment variable: %s", env)
+	}
+	for _, key := range conf.UnknownKeys() {
+		logger.Warnf("%s", key)
+	}
 
 	logger = logger.WithLevel(conf.Log.GetLevel())
 
@@ -68,7 +79,7 @@ func run(closeSignal chan os.Signal) int {
 
 	var externalCache cache.External
 	if conf.Cache.IsSet() {
-		externalCache, err = cache.SetupExternalCache(&conf.Cache, telemetryReporter, logger)
+		externalCache, err = cache.SetupExternalCache(&conf.Cache, telemetryReporter, statusReporter, logger)
 		if err != nil {
 			return exitFailure
 		}
@@ -102,6 +113,18 @@ func run(closeSignal chan os.Signal) int {
 		shutdownFuncs = append(shutdownFuncs, func() { grpcServer.Shutdown() })
 	}
 
+	if diagServer != nil {
+		streamServers := make(map[string]stream.Server)
+		if router != nil && router.StreamServer() != nil {
+			streamServers["sse"] = router.StreamServer()
+		}
+		if grpcServer != nil {
+			streamServers["grpc"] = grpcServer.StreamServer()
+		}
+		diagServer.SetupAdminRoutes(sdkRegistrar, statusReporter, streamServers)
+		diagServer.SetupReadiness(sdkRegistrar)
+	}
+
 	for {
 		select {
 		case <-closeSignal:
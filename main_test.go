@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/internal/testutils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,6 +60,44 @@ func TestAppMain_Disabled_Everything(t *testing.T) {
 	assert.Equal(t, 0, exitCode)
 }
 
+func TestAppMain_Unknown_Config_Keys(t *testing.T) {
+	resetFlags()
+	testutils.UseTempFile(`
+defaults: &defaults
+  poll_interval: 30
+sdks:
+  sdk1:
+    <<: *defaults
+    key: "XxPbCKmzIUGORk4vsufpzw/iC_KABprDEueeQs3yovVnQ"
+    base_url: "https://test-cdn-global.configcat.com"
+    poll_intervall: 30
+http:
+  enabled: false
+grpc:
+  enabled: false
+diag:
+  enabled: false
+`, func(file string) {
+		oldArgs := os.Args
+		defer func() { os.Args = oldArgs }()
+		os.Args = []string{"app", "-c=" + file}
+
+		var exitCode int
+		closeSignal := make(chan os.Signal, 1)
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			exitCode = run(closeSignal)
+			wg.Done()
+		}()
+		time.Sleep(1 * time.Second)
+		closeSignal <- syscall.SIGTERM
+		wg.Wait()
+
+		assert.Equal(t, 0, exitCode)
+	})
+}
+
 func TestAppMain_GRPCOnly(t *testing.T) {
 	resetFlags()
 	t.Setenv("CONFIGCAT_SDK1_BASE_URL", "https://test-cdn-global.configcat.com")
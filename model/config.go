@@ -0,0 +1,11 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+type ConfigPayload struct {
+	ETag      string          `json:"etag"`
+	FetchTime int64           `json:"fetchTime"`
+	Config    json.RawMessage `json:"config"`
+}
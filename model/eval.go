@@ -1,28 +1,99 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+
 	configcat "github.com/configcat/go-sdk/v9"
 )
 
+const (
+	ReasonDefault = "DEFAULT"
+	ReasonError   = "ERROR"
+
+	ErrorCodeGeneral          = "GENERAL"
+	ErrorCodeFlagNotFound     = "FLAG_NOT_FOUND"
+	ErrorCodeTypeMismatch     = "TYPE_MISMATCH"
+	ErrorCodeProviderNotReady = "PROVIDER_NOT_READY"
+)
+
+// ErrDefaultTypeMismatch is reported when the evaluated value's type differs from the caller-supplied default value's type.
+var ErrDefaultTypeMismatch = errors.New("the evaluated value's type doesn't match the default value's type")
+
 type EvalData struct {
-	Value       interface{}
-	VariationId string
-	Error       error
-	User        configcat.User
-	IsTargeting bool
+	Value        interface{}
+	VariationId  string
+	Error        error
+	User         configcat.User
+	IsTargeting  bool
+	IsOverridden bool
 }
 
 type ResponsePayload struct {
-	Value       interface{} `json:"value"`
-	VariationId string      `json:"variationId"`
+	Value        interface{} `json:"value"`
+	VariationId  string      `json:"variationId"`
+	Overridden   bool        `json:"overridden,omitempty"`
+	Reason       string      `json:"reason,omitempty"`
+	ErrorCode    string      `json:"errorCode,omitempty"`
+	ErrorMessage string      `json:"errorMessage,omitempty"`
 }
 
 type EvalRequest struct {
-	SdkKey string    `json:"sdkKey"`
-	Key    string    `json:"key"`
-	User   UserAttrs `json:"user"`
+	SdkKey       string      `json:"sdkKey"`
+	Key          string      `json:"key"`
+	User         UserAttrs   `json:"user"`
+	DefaultValue interface{} `json:"defaultValue"`
 }
 
 func PayloadFromEvalData(evalData *EvalData) ResponsePayload {
-	return ResponsePayload{Value: evalData.Value, VariationId: evalData.VariationId}
+	return ResponsePayload{Value: evalData.Value, VariationId: evalData.VariationId, Overridden: evalData.IsOverridden}
+}
+
+// DefaultPayload answers an evaluation request with the caller-supplied default value.
+func DefaultPayload(defaultValue interface{}, reason string, errorCode string, errorMessage string) ResponsePayload {
+	return ResponsePayload{Value: defaultValue, Reason: reason, ErrorCode: errorCode, ErrorMessage: errorMessage}
+}
+
+// IsValidDefaultValue reports whether the value can stand in for a feature flag or setting's value.
+func IsValidDefaultValue(value interface{}) bool {
+	switch value.(type) {
+	case bool, string, int, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckDefaultType returns ErrDefaultTypeMismatch when the evaluated value and the default value have different types.
+// Numbers are considered to be the same type regardless of being whole or floating point.
+func CheckDefaultType(value interface{}, defaultValue interface{}) error {
+	matches := false
+	switch defaultValue.(type) {
+	case bool:
+		_, matches = value.(bool)
+	case string:
+		_, matches = value.(string)
+	case int, float64:
+		switch value.(type) {
+		case int, float64:
+			matches = true
+		}
+	}
+	if !matches {
+		return fmt.Errorf("%w (%T != %T)", ErrDefaultTypeMismatch, value, defaultValue)
+	}
+	return nil
+}
+
+// ErrorCodeOf maps an evaluation error to the error code returned along with the default value.
+func ErrorCodeOf(err error) string {
+	var errKeyNotFound configcat.ErrKeyNotFound
+	switch {
+	case errors.As(err, &errKeyNotFound):
+		return ErrorCodeFlagNotFound
+	case errors.Is(err, ErrDefaultTypeMismatch):
+		return ErrorCodeTypeMismatch
+	default:
+		return ErrorCodeGeneral
+	}
 }
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"errors"
 	"testing"
 
+	configcat "github.com/configcat/go-sdk/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,3 +15,37 @@ func TestPayloadFromEvalData(t *testing.T) {
 	assert.Equal(t, "test", payload.Value)
 	assert.Equal(t, "varId", payload.VariationId)
 }
+
+func TestDefaultPayload(t *testing.T) {
+	payload := DefaultPayload(false, ReasonError, ErrorCodeFlagNotFound, "flag not found")
+
+	assert.Equal(t, false, payload.Value)
+	assert.Equal(t, ReasonError, payload.Reason)
+	assert.Equal(t, ErrorCodeFlagNotFound, payload.ErrorCode)
+	assert.Equal(t, "flag not found", payload.ErrorMessage)
+}
+
+func TestIsValidDefaultValue(t *testing.T) {
+	assert.True(t, IsValidDefaultValue(true))
+	assert.True(t, IsValidDefaultValue("test"))
+	assert.True(t, IsValidDefaultValue(1))
+	assert.True(t, IsValidDefaultValue(1.5))
+	assert.False(t, IsValidDefaultValue(nil))
+	assert.False(t, IsValidDefaultValue([]interface{}{"a"}))
+	assert.False(t, IsValidDefaultValue(map[string]interface{}{"a": "b"}))
+}
+
+func TestCheckDefaultType(t *testing.T) {
+	assert.NoError(t, CheckDefaultType(true, false))
+	assert.NoError(t, CheckDefaultType("a", "b"))
+	assert.NoError(t, CheckDefaultType(1, 2.5))
+	assert.NoError(t, CheckDefaultType(1.5, 2))
+	assert.ErrorIs(t, CheckDefaultType("a", false), ErrDefaultTypeMismatch)
+	assert.ErrorIs(t, CheckDefaultType(1, "b"), ErrDefaultTypeMismatch)
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	assert.Equal(t, ErrorCodeFlagNotFound, ErrorCodeOf(configcat.ErrKeyNotFound{Key: "flag"}))
+	assert.Equal(t, ErrorCodeTypeMismatch, ErrorCodeOf(CheckDefaultType("a", false)))
+	assert.Equal(t, ErrorCodeGeneral, ErrorCodeOf(errors.New("other")))
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DiffAdded    = "added"
+	DiffRemoved  = "removed"
+	DiffModified = "modified"
+
+	// DiffPrerequisiteChanged marks a setting that is unchanged itself but one of its prerequisite flags changed.
+	DiffPrerequisiteChanged = "prerequisite_changed"
+)
+
+type ConfigVersion struct {
+	Version    int             `json:"version"`
+	ETag       string          `json:"etag"`
+	FetchTime  time.Time       `json:"fetchTime"`
+	ConfigJson json.RawMessage `json:"config,omitempty"`
+}
+
+type ConfigHistory struct {
+	Latest   int             `json:"latest"`
+	Pinned   int             `json:"pinned,omitempty"`
+	Versions []ConfigVersion `json:"versions"`
+}
+
+type ConfigDiff struct {
+	From     int           `json:"from"`
+	To       int           `json:"to"`
+	Flags    []FlagDiff    `json:"flags"`
+	Segments []SegmentDiff `json:"segments"`
+}
+
+type ConfigChange struct {
+	Id           int           `json:"id"`
+	ETag         string        `json:"etag"`
+	PreviousETag string        `json:"previousEtag"`
+	DetectedAt   time.Time     `json:"detectedAt"`
+	Flags        []FlagDiff    `json:"flags"`
+	Segments     []SegmentDiff `json:"segments"`
+}
+
+type FlagDiff struct {
+	Key                      string     `json:"key"`
+	Change                   string     `json:"change"`
+	DefaultValue             *ValueDiff `json:"defaultValue,omitempty"`
+	TargetingRules           []RuleDiff `json:"targetingRules,omitempty"`
+	PercentageOptionsChanged bool       `json:"percentageOptionsChanged,omitempty"`
+	ChangedPrerequisites     []string   `json:"changedPrerequisites,omitempty"`
+}
+
+type SegmentDiff struct {
+	Name   string `json:"name"`
+	Change string `json:"change"`
+}
+
+type ValueDiff struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type RuleDiff struct {
+	Index  int             `json:"index"`
+	Change string          `json:"change"`
+	From   json.RawMessage `json:"from,omitempty"`
+	To     json.RawMessage `json:"to,omitempty"`
+}
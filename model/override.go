@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+type FlagOverride struct {
+	Value     interface{} `json:"value"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+func (o *FlagOverride) IsExpired(now time.Time) bool {
+	return !now.Before(o.ExpiresAt)
+}
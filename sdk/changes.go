@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"slices"
+
+	"github.com/configcat/configcat-proxy/model"
+)
+
+// Changes returns the recorded config changes having an id greater than since.
+func (c *client) Changes(since int) []model.ConfigChange {
+	return slices.DeleteFunc(c.cache.Changes(), func(change model.ConfigChange) bool {
+		return change.Id <= since
+	})
+}
+
+func (c *client) logChanges() {
+	c.changesMu.Lock()
+	defer c.changesMu.Unlock()
+
+	for _, change := range c.Changes(c.loggedChangeId) {
+		for _, flag := range change.Flags {
+			c.log.Infof("config change detected: etag=%s previous_etag=%s flag=%s change=%s", change.ETag, change.PreviousETag, flag.Key, flag.Change)
+		}
+		for _, segment := range change.Segments {
+			c.log.Infof("config change detected: etag=%s previous_etag=%s segment=%s change=%s", change.ETag, change.PreviousETag, segment.Name, segment.Change)
+		}
+		c.loggedChangeId = change.Id
+	}
+}
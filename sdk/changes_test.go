@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/go-sdk/v9/configcattest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChanges(t *testing.T) {
+	key, h, srv := newHistoryTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	sub := make(chan struct{}, 10)
+	client.Subscribe(sub)
+
+	<-client.Ready()
+	assert.Empty(t, client.Changes(0))
+
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{"flag": {Default: false}, "flag2": {Default: "a"}})
+	_ = client.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{"flag": {Default: false}})
+	_ = client.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+
+	changes := client.Changes(0)
+	require.Len(t, changes, 2)
+	assert.Equal(t, []model.FlagDiff{
+		{Key: "flag", Change: model.DiffModified, DefaultValue: &model.ValueDiff{From: true, To: false}},
+		{Key: "flag2", Change: model.DiffAdded},
+	}, changes[0].Flags)
+	assert.Equal(t, changes[0].ETag, changes[1].PreviousETag)
+	assert.Equal(t, []model.FlagDiff{{Key: "flag2", Change: model.DiffRemoved}}, changes[1].Flags)
+
+	changes = client.Changes(1)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 2, changes[0].Id)
+}
@@ -0,0 +1,260 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/configcat/configcat-proxy/cache"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/configcat-proxy/sdk/store"
+	"github.com/configcat/go-sdk/v9"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+)
+
+const (
+	historyCacheName    = "proxy-history"
+	historyCacheVersion = "v1"
+)
+
+var ErrVersionNotFound = errors.New("config version not found")
+
+type pinnedConfig struct {
+	version *model.ConfigVersion
+	entry   *store.EntryWithEtag
+	client  *configcat.Client
+}
+
+type storedHistory struct {
+	Versions []*model.ConfigVersion `json:"versions"`
+	Pinned   int                    `json:"pinned,omitempty"`
+}
+
+func historyCacheKey(sdkKey string) string {
+	return configcatcache.ProduceCacheKey(sdkKey, historyCacheName, historyCacheVersion)
+}
+
+func (c *client) History() model.ConfigHistory {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	result := model.ConfigHistory{Versions: make([]model.ConfigVersion, 0, len(c.history))}
+	for _, version := range c.history {
+		result.Versions = append(result.Versions, model.ConfigVersion{Version: version.Version, ETag: version.ETag, FetchTime: version.FetchTime})
+	}
+	if len(c.history) > 0 {
+		result.Latest = c.history[len(c.history)-1].Version
+	}
+	if pinned := c.pinned.Load(); pinned != nil {
+		result.Pinned = pinned.version.Version
+	}
+	return result
+}
+
+func (c *client) ConfigVersion(version int) (*model.ConfigVersion, error) {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	v := c.findVersion(version)
+	if v == nil {
+		return nil, ErrVersionNotFound
+	}
+	return v, nil
+}
+
+func (c *client) DiffVersions(from int, to int) (*model.ConfigDiff, error) {
+	c.historyMu.Lock()
+	fromVersion, toVersion := c.findVersion(from), c.findVersion(to)
+	c.historyMu.Unlock()
+
+	if fromVersion == nil || toVersion == nil {
+		return nil, ErrVersionNotFound
+	}
+	flags, segments, err := store.DiffConfigs(fromVersion.ConfigJson, toVersion.ConfigJson)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ConfigDiff{From: from, To: to, Flags: flags, Segments: segments}, nil
+}
+
+func (c *client) Pin(ctx context.Context, version int) error {
+	c.ensureReady()
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	v := c.findVersion(version)
+	if v == nil {
+		return ErrVersionNotFound
+	}
+	pinned, err := c.newPinnedConfig(ctx, v)
+	if err != nil {
+		return err
+	}
+	previous := c.pinned.Swap(pinned)
+	if err = c.persistHistory(ctx); err != nil {
+		c.pinned.Store(previous)
+		pinned.client.Close()
+		return err
+	}
+	previous.close()
+	c.log.Reportf("pinned to config version %d", version)
+	c.Publish(struct{}{})
+	return nil
+}
+
+func (c *client) Unpin(ctx context.Context) error {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	previous := c.pinned.Swap(nil)
+	if previous == nil {
+		return nil
+	}
+	if err := c.persistHistory(ctx); err != nil {
+		c.pinned.Store(previous)
+		return err
+	}
+	previous.close()
+	c.log.Reportf("config version %d unpinned", previous.version.Version)
+	c.Publish(struct{}{})
+	return nil
+}
+
+// recordVersion appends the currently cached config JSON to the history when its ETag differs from the latest recorded one.
+func (c *client) recordVersion() {
+	entry := c.cache.LoadEntry()
+	if entry.Empty {
+		return
+	}
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	if c.sdkCtx.SDKConf.History.UseCache && c.sdkCtx.ExternalCache != nil {
+		// another instance might have already recorded this version
+		c.loadHistory()
+	}
+	if len(c.history) > 0 && c.history[len(c.history)-1].ETag == entry.ETag {
+		return
+	}
+	next := 1
+	if len(c.history) > 0 {
+		next = c.history[len(c.history)-1].Version + 1
+	}
+	c.history = append(c.history, &model.ConfigVersion{Version: next, ETag: entry.ETag, FetchTime: entry.FetchTime, ConfigJson: entry.ConfigJson})
+	size := c.sdkCtx.SDKConf.History.Size
+	if size < 1 {
+		size = config.DefaultHistorySize
+	}
+	if len(c.history) > size {
+		c.history = slices.Clone(c.history[len(c.history)-size:])
+	}
+	c.log.Debugf("config version %d recorded", next)
+	_ = c.persistHistory(c.ctx)
+}
+
+// reloadHistory picks up the versions and the pinned state stored by other instances.
+func (c *client) reloadHistory() {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	pinnedVersion, ok := c.loadHistory()
+	if !ok {
+		return
+	}
+	current := c.pinned.Load()
+	if (current == nil && pinnedVersion == 0) || (current != nil && current.version.Version == pinnedVersion) {
+		return
+	}
+	var next *pinnedConfig
+	if pinnedVersion != 0 {
+		v := c.findVersion(pinnedVersion)
+		if v == nil {
+			c.log.Errorf("pinned config version %d not found in history", pinnedVersion)
+			return
+		}
+		var err error
+		if next, err = c.newPinnedConfig(c.ctx, v); err != nil {
+			c.log.Errorf("failed to pin config version %d: %s", pinnedVersion, err)
+			return
+		}
+	}
+	c.pinned.Swap(next).close()
+	c.log.Debugf("pinned config version changed in cache, reloading")
+	c.Publish(struct{}{})
+}
+
+func (c *client) loadHistory() (int, bool) {
+	data, err := c.sdkCtx.ExternalCache.Get(c.ctx, historyCacheKey(c.sdkCtx.SDKConf.Key))
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			c.log.Errorf("failed to load config history: %s", err)
+		}
+		return 0, false
+	}
+	var stored storedHistory
+	if err = json.Unmarshal(data, &stored); err != nil {
+		c.log.Errorf("failed to parse config history: %s", err)
+		return 0, false
+	}
+	c.history = slices.DeleteFunc(stored.Versions, func(version *model.ConfigVersion) bool {
+		return version == nil
+	})
+	return stored.Pinned, true
+}
+
+func (c *client) persistHistory(ctx context.Context) error {
+	if !c.sdkCtx.SDKConf.History.UseCache || c.sdkCtx.ExternalCache == nil {
+		return nil
+	}
+	stored := storedHistory{Versions: c.history}
+	if pinned := c.pinned.Load(); pinned != nil {
+		stored.Pinned = pinned.version.Version
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if err = c.sdkCtx.ExternalCache.Set(ctx, historyCacheKey(c.sdkCtx.SDKConf.Key), data); err != nil {
+		c.log.Errorf("failed to persist config history: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (c *client) findVersion(version int) *model.ConfigVersion {
+	for _, v := range c.history {
+		if v.Version == version {
+			return v
+		}
+	}
+	return nil
+}
+
+// newPinnedConfig starts an offline SDK instance that evaluates the given config version only.
+func (c *client) newPinnedConfig(ctx context.Context, version *model.ConfigVersion) (*pinnedConfig, error) {
+	storage := store.NewInMemoryStorage()
+	_ = storage.Set(ctx, "", configcatcache.CacheSegmentsToBytes(version.FetchTime, version.ETag, version.ConfigJson))
+	clientConfig := c.clientConfig
+	clientConfig.Offline = true
+	clientConfig.SDKKey = validEmptySdkKey
+	clientConfig.Cache = storage
+	clientConfig.Hooks = &configcat.Hooks{OnFlagEvaluated: c.clientConfig.Hooks.OnFlagEvaluated}
+	pinnedClient := configcat.NewCustomClient(clientConfig)
+	if err := pinnedClient.RefreshWithContext(ctx); err != nil {
+		pinnedClient.Close()
+		return nil, err
+	}
+	return &pinnedConfig{
+		version: version,
+		entry:   &store.EntryWithEtag{ConfigJson: version.ConfigJson, ETag: version.ETag, FetchTime: version.FetchTime},
+		client:  pinnedClient,
+	}, nil
+}
+
+func (p *pinnedConfig) close() {
+	if p != nil {
+		p.client.Close()
+	}
+}
@@ -0,0 +1,198 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	configcat "github.com/configcat/go-sdk/v9"
+	"github.com/configcat/go-sdk/v9/configcattest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_Record(t *testing.T) {
+	key, h, srv := newHistoryTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, History: config.HistoryConfig{Size: 2}}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	sub := make(chan struct{}, 10)
+	client.Subscribe(sub)
+
+	<-client.Ready()
+	history := client.History()
+	assert.Equal(t, 1, history.Latest)
+	assert.Len(t, history.Versions, 1)
+	assert.Empty(t, history.Versions[0].ConfigJson)
+
+	for _, value := range []bool{false, true} {
+		_ = h.SetFlags(key, map[string]*configcattest.Flag{"flag": {Default: value}})
+		_ = client.Refresh(t.Context())
+		testutils.WithTimeout(2*time.Second, func() {
+			<-sub
+		})
+	}
+
+	history = client.History()
+	assert.Equal(t, 3, history.Latest)
+	assert.Equal(t, []int{2, 3}, []int{history.Versions[0].Version, history.Versions[1].Version})
+
+	_, err := client.ConfigVersion(1)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+	version, err := client.ConfigVersion(2)
+	require.NoError(t, err)
+	assert.Contains(t, string(version.ConfigJson), `"flag"`)
+}
+
+func TestHistory_Pin(t *testing.T) {
+	key, h, srv := newHistoryTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	sub := make(chan struct{}, 10)
+	client.Subscribe(sub)
+
+	<-client.Ready()
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{"flag": {Default: false}, "flag2": {Default: "a"}})
+	_ = client.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+	assert.Equal(t, false, client.Eval("flag", nil).Value)
+	latestEtag := client.GetCachedJson().ETag
+
+	assert.ErrorIs(t, client.Pin(t.Context(), 5), ErrVersionNotFound)
+	require.NoError(t, client.Pin(t.Context(), 1))
+	<-sub
+	assert.Equal(t, true, client.Eval("flag", nil).Value)
+	assert.Equal(t, []string{"flag"}, client.Keys())
+	assert.False(t, client.HasKey("flag2"))
+	assert.NotEqual(t, latestEtag, client.GetCachedJson().ETag)
+	assert.Equal(t, 1, client.History().Pinned)
+
+	// new versions are still recorded while pinned
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{"flag": {Default: false}})
+	_ = client.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+	assert.Equal(t, 3, client.History().Latest)
+	assert.Equal(t, true, client.Eval("flag", nil).Value)
+
+	require.NoError(t, client.Unpin(t.Context()))
+	<-sub
+	assert.Equal(t, false, client.Eval("flag", nil).Value)
+	assert.Zero(t, client.History().Pinned)
+	assert.NoError(t, client.Unpin(t.Context()))
+}
+
+func TestHistory_Diff(t *testing.T) {
+	key, h, srv := newHistoryTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	sub := make(chan struct{}, 10)
+	client.Subscribe(sub)
+
+	<-client.Ready()
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {
+			Default: false,
+			Rules: []configcattest.Rule{{
+				ComparisonAttribute: "Email",
+				Comparator:          configcat.OpContains,
+				ComparisonValue:     "@example.com",
+				Value:               true,
+			}},
+		},
+		"flag2": {Default: "a"},
+	})
+	_ = client.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+
+	diff, err := client.DiffVersions(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+	require.Len(t, diff.Flags, 2)
+	assert.Equal(t, "flag", diff.Flags[0].Key)
+	assert.Equal(t, model.DiffModified, diff.Flags[0].Change)
+	assert.Equal(t, &model.ValueDiff{From: true, To: false}, diff.Flags[0].DefaultValue)
+	require.Len(t, diff.Flags[0].TargetingRules, 1)
+	assert.Equal(t, model.DiffAdded, diff.Flags[0].TargetingRules[0].Change)
+	assert.Contains(t, string(diff.Flags[0].TargetingRules[0].To), "@example.com")
+	assert.Equal(t, model.FlagDiff{Key: "flag2", Change: model.DiffAdded}, diff.Flags[1])
+
+	diff, err = client.DiffVersions(2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.DiffRemoved, diff.Flags[0].TargetingRules[0].Change)
+	assert.Equal(t, model.FlagDiff{Key: "flag2", Change: model.DiffRemoved}, diff.Flags[1])
+
+	diff, err = client.DiffVersions(2, 2)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Flags)
+
+	_, err = client.DiffVersions(1, 3)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestHistory_SharedCache(t *testing.T) {
+	key, h, srv := newHistoryTestServer(t)
+	s := miniredis.RunT(t)
+
+	ctx1 := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, History: config.HistoryConfig{UseCache: true}}, newRedisCache(s.Addr()))
+	client1 := NewClient(ctx1, log.NewNullLogger())
+	defer client1.Close()
+	sub := make(chan struct{}, 10)
+	client1.Subscribe(sub)
+
+	<-client1.Ready()
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{"flag": {Default: false}})
+	_ = client1.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+	require.NoError(t, client1.Pin(t.Context(), 1))
+
+	stored, err := s.Get(historyCacheKey(key))
+	require.NoError(t, err)
+	var persisted storedHistory
+	require.NoError(t, json.Unmarshal([]byte(stored), &persisted))
+	assert.Len(t, persisted.Versions, 2)
+	assert.Equal(t, 1, persisted.Pinned)
+
+	ctx2 := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, History: config.HistoryConfig{UseCache: true}}, newRedisCache(s.Addr()))
+	ctx2.SdkId = "test2"
+	client2 := NewClient(ctx2, log.NewNullLogger())
+	defer client2.Close()
+
+	testutils.WaitUntil(2*time.Second, func() bool {
+		return client2.History().Pinned == 1
+	})
+	assert.Equal(t, 2, client2.History().Latest)
+	assert.Equal(t, true, client2.Eval("flag", nil).Value)
+}
+
+func newHistoryTestServer(t *testing.T) (string, *configcattest.Handler, *httptest.Server) {
+	key := configcattest.RandomSDKKey()
+	var h configcattest.Handler
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {
+			Default: true,
+		},
+	})
+	srv := httptest.NewServer(&h)
+	t.Cleanup(srv.Close)
+	return key, &h, srv
+}
@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"github.com/configcat/go-sdk/v9/configcatcache"
+)
+
+// bootstrapFromLocalSnapshot loads the last-known-good config from the local disk snapshot, so the SDK can
+// serve evaluations before its first refresh completes. It returns true when a config was loaded.
+func (c *client) bootstrapFromLocalSnapshot() bool {
+	if c.sdkCtx.LocalSnapshot == nil || c.sdkCtx.SDKConf.Offline.Enabled {
+		return false
+	}
+	cacheKey := configcatcache.ProduceCacheKey(c.sdkCtx.SDKConf.Key, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion)
+	if _, err := c.sdkCtx.LocalSnapshot.Get(c.ctx, cacheKey); err != nil {
+		return false
+	}
+	// in offline mode the SDK reads the config from its cache, which falls back to the local snapshot
+	c.configCatClient.SetOffline()
+	defer c.configCatClient.SetOnline()
+	if err := c.configCatClient.RefreshWithContext(c.ctx); err != nil {
+		c.log.Errorf("failed to load the local snapshot: %s", err)
+		return false
+	}
+	if c.cache.LoadEntry().Empty {
+		return false
+	}
+	c.log.Reportf("bootstrapped from the local snapshot")
+	return true
+}
@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/configcat/configcat-proxy/cache"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSnapshot_Bootstrap(t *testing.T) {
+	key, _, srv := newHistoryTestServer(t)
+	snapshot, err := cache.NewLocalSnapshot(&config.LocalSnapshotConfig{Enabled: true, Path: t.TempDir()}, log.NewNullLogger())
+	require.NoError(t, err)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	ctx.LocalSnapshot = snapshot
+	client1 := NewClient(ctx, log.NewNullLogger())
+	<-client1.Ready()
+	etag := client1.GetCachedJson().ETag
+	client1.Close()
+
+	// the CDN is unreachable, the last-known-good config comes from the disk
+	srv.Close()
+	ctx = NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	ctx.LocalSnapshot = snapshot
+	client2 := NewClient(ctx, log.NewNullLogger())
+	defer client2.Close()
+
+	<-client2.Ready()
+	assert.True(t, client2.IsInValidState())
+	assert.Equal(t, etag, client2.GetCachedJson().ETag)
+	assert.Equal(t, true, client2.Eval("flag", nil).Value)
+}
@@ -0,0 +1,291 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"math"
+	"time"
+
+	"github.com/configcat/configcat-proxy/cache"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+)
+
+const (
+	overridesCacheName      = "proxy-overrides"
+	overridesCacheVersion   = "v1"
+	sharedStateSyncInterval = 5 * time.Second
+)
+
+var (
+	ErrOverrideKeyNotFound  = errors.New("feature flag or setting not found")
+	ErrOverrideTypeMismatch = errors.New("override value doesn't match the type of the feature flag or setting")
+)
+
+type flagOverrides map[string]*model.FlagOverride
+
+func overridesCacheKey(sdkKey string) string {
+	return configcatcache.ProduceCacheKey(sdkKey, overridesCacheName, overridesCacheVersion)
+}
+
+func (c *client) SetOverride(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if !c.ensureReady() {
+		return ErrNotReady
+	}
+	current := c.snapshot(nil).GetValueDetails(key)
+	if current.Data.Error != nil {
+		return ErrOverrideKeyNotFound
+	}
+	coerced, ok := coerceOverrideValue(current.Value, value)
+	if !ok {
+		return ErrOverrideTypeMismatch
+	}
+
+	c.overridesMu.Lock()
+	defer c.overridesMu.Unlock()
+
+	next, err := c.latestOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	next[key] = &model.FlagOverride{Value: coerced, ExpiresAt: time.Now().Add(ttl).UTC()}
+	if err := c.persistOverrides(ctx, next); err != nil {
+		return err
+	}
+	c.log.Reportf("override set for '%s', expires at %s", key, next[key].ExpiresAt.Format(time.RFC3339))
+	c.applyOverrides(next)
+	return nil
+}
+
+func (c *client) RemoveOverride(ctx context.Context, key string) error {
+	c.overridesMu.Lock()
+	defer c.overridesMu.Unlock()
+
+	next, err := c.latestOverrides(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := next[key]; !ok {
+		if !sameOverrides(next, c.activeOverrides(time.Now())) {
+			c.applyOverrides(next)
+		}
+		return nil
+	}
+	delete(next, key)
+	if err := c.persistOverrides(ctx, next); err != nil {
+		return err
+	}
+	c.log.Reportf("override removed for '%s'", key)
+	c.applyOverrides(next)
+	return nil
+}
+
+func (c *client) Overrides() map[string]model.FlagOverride {
+	active := c.activeOverrides(time.Now())
+	result := make(map[string]model.FlagOverride, len(active))
+	for key, override := range active {
+		result[key] = *override
+	}
+	return result
+}
+
+func (c *client) override(key string) *model.FlagOverride {
+	current := c.overrides.Load()
+	if current == nil {
+		return nil
+	}
+	override, ok := (*current)[key]
+	if !ok || override.IsExpired(time.Now()) {
+		return nil
+	}
+	return override
+}
+
+func (c *client) applyOverride(key string, data *model.EvalData) {
+	override := c.override(key)
+	if override == nil || data.Error != nil {
+		return
+	}
+	if value, ok := coerceOverrideValue(data.Value, override.Value); ok {
+		data.Value = value
+		data.VariationId = ""
+		data.IsTargeting = false
+		data.IsOverridden = true
+	}
+}
+
+func (c *client) activeOverrides(now time.Time) flagOverrides {
+	result := flagOverrides{}
+	current := c.overrides.Load()
+	if current == nil {
+		return result
+	}
+	for key, override := range *current {
+		if !override.IsExpired(now) {
+			result[key] = override
+		}
+	}
+	return result
+}
+
+// latestOverrides returns the active overrides, read from the external cache when there's one,
+// so changes made by other instances sharing the cache are not lost by a read-modify-write.
+func (c *client) latestOverrides(ctx context.Context) (flagOverrides, error) {
+	if c.sdkCtx.ExternalCache == nil {
+		return c.activeOverrides(time.Now()), nil
+	}
+	return c.loadOverrides(ctx)
+}
+
+func (c *client) loadOverrides(ctx context.Context) (flagOverrides, error) {
+	data, err := c.sdkCtx.ExternalCache.Get(ctx, overridesCacheKey(c.sdkCtx.SDKConf.Key))
+	loaded := flagOverrides{}
+	if err != nil && !errors.Is(err, cache.ErrNotFound) {
+		c.log.Errorf("failed to load overrides: %s", err)
+		return nil, err
+	}
+	if err == nil {
+		if err = json.Unmarshal(data, &loaded); err != nil {
+			c.log.Errorf("failed to parse overrides: %s", err)
+			return nil, err
+		}
+	}
+	now := time.Now()
+	maps.DeleteFunc(loaded, func(_ string, override *model.FlagOverride) bool {
+		return override == nil || override.IsExpired(now)
+	})
+	return loaded, nil
+}
+
+func (c *client) persistOverrides(ctx context.Context, overrides flagOverrides) error {
+	if c.sdkCtx.ExternalCache == nil {
+		return nil
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	if err = c.sdkCtx.ExternalCache.Set(ctx, overridesCacheKey(c.sdkCtx.SDKConf.Key), data); err != nil {
+		c.log.Errorf("failed to persist overrides: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (c *client) applyOverrides(overrides flagOverrides) {
+	c.overrides.Store(&overrides)
+	expirations := make(map[string]time.Time, len(overrides))
+	for key, override := range overrides {
+		expirations[key] = override.ExpiresAt
+	}
+	c.sdkCtx.StatusReporter.ReportOverrides(c.sdkCtx.SdkId, expirations)
+	c.Publish(struct{}{})
+}
+
+// syncSharedState expires overrides, and keeps the overrides and the pinned config version
+// in sync with other instances sharing the same external cache. The cache is only polled
+// when there's something to sync: API overrides or a shared config history.
+func (c *client) syncSharedState() {
+	defer close(c.syncDone)
+	expiry := time.NewTicker(time.Second)
+	defer expiry.Stop()
+	var reload <-chan time.Time
+	if c.sdkCtx.ExternalCache != nil && (c.sdkCtx.SyncOverrides || c.sdkCtx.SDKConf.History.UseCache) {
+		c.reloadSharedState()
+		reloader := time.NewTicker(sharedStateSyncInterval)
+		defer reloader.Stop()
+		reload = reloader.C
+	}
+	for {
+		select {
+		case <-expiry.C:
+			c.expireOverrides()
+		case <-reload:
+			c.reloadSharedState()
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *client) reloadSharedState() {
+	if c.sdkCtx.SyncOverrides {
+		c.reloadOverrides()
+	}
+	if c.sdkCtx.SDKConf.History.UseCache {
+		c.reloadHistory()
+	}
+}
+
+func (c *client) expireOverrides() {
+	c.overridesMu.Lock()
+	defer c.overridesMu.Unlock()
+
+	current := c.overrides.Load()
+	if current == nil {
+		return
+	}
+	active := c.activeOverrides(time.Now())
+	if len(active) != len(*current) {
+		c.log.Debugf("override(s) expired")
+		c.applyOverrides(active)
+	}
+}
+
+func (c *client) reloadOverrides() {
+	c.overridesMu.Lock()
+	defer c.overridesMu.Unlock()
+
+	loaded, err := c.loadOverrides(c.ctx)
+	if err != nil {
+		return
+	}
+	if !sameOverrides(loaded, c.activeOverrides(time.Now())) {
+		c.log.Debugf("overrides changed in cache, reloading")
+		c.applyOverrides(loaded)
+	}
+}
+
+func sameOverrides(a flagOverrides, b flagOverrides) bool {
+	return maps.EqualFunc(a, b, func(x *model.FlagOverride, y *model.FlagOverride) bool {
+		return x.ExpiresAt.Equal(y.ExpiresAt) && comparableValue(x.Value) == comparableValue(y.Value)
+	})
+}
+
+func comparableValue(value interface{}) interface{} {
+	if v, ok := value.(int); ok {
+		return float64(v)
+	}
+	return value
+}
+
+// coerceOverrideValue converts value to the type of current, numbers decoded from JSON arrive as float64.
+func coerceOverrideValue(current interface{}, value interface{}) (interface{}, bool) {
+	switch current.(type) {
+	case bool:
+		v, ok := value.(bool)
+		return v, ok
+	case string:
+		v, ok := value.(string)
+		return v, ok
+	case int:
+		switch v := value.(type) {
+		case int:
+			return v, true
+		case float64:
+			if v == math.Trunc(v) {
+				return int(v), true
+			}
+		}
+	case float64:
+		switch v := value.(type) {
+		case float64:
+			return v, true
+		case int:
+			return float64(v), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,179 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/go-sdk/v9/configcattest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrides_Eval(t *testing.T) {
+	key, srv := newOverrideTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	ctx.StatusReporter.RegisterSdk(ctx.SdkId, ctx.SDKConf)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	sub := make(chan struct{}, 10)
+	client.Subscribe(sub)
+
+	assert.NoError(t, client.SetOverride(t.Context(), "flag", false, 2*time.Second))
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+
+	data := client.Eval("flag", nil)
+	assert.Equal(t, false, data.Value)
+	assert.True(t, data.IsOverridden)
+	assert.Empty(t, data.VariationId)
+	all := client.EvalAll(nil)
+	assert.Equal(t, false, all["flag"].Value)
+	assert.True(t, all["flag"].IsOverridden)
+	assert.Equal(t, 5, all["num"].Value)
+	assert.False(t, all["num"].IsOverridden)
+
+	overrides := client.Overrides()
+	assert.Equal(t, false, overrides["flag"].Value)
+	assert.Contains(t, ctx.StatusReporter.GetStatus().SDKs[ctx.SdkId].Overrides, "flag")
+
+	// expiry
+	testutils.WithTimeout(5*time.Second, func() {
+		<-sub
+	})
+	data = client.Eval("flag", nil)
+	assert.Equal(t, true, data.Value)
+	assert.False(t, data.IsOverridden)
+	assert.Empty(t, client.Overrides())
+	assert.Empty(t, ctx.StatusReporter.GetStatus().SDKs[ctx.SdkId].Overrides)
+}
+
+func TestOverrides_Remove(t *testing.T) {
+	key, srv := newOverrideTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+
+	assert.NoError(t, client.SetOverride(t.Context(), "flag", false, time.Minute))
+	assert.Equal(t, false, client.Eval("flag", nil).Value)
+	assert.NoError(t, client.RemoveOverride(t.Context(), "flag"))
+	assert.Equal(t, true, client.Eval("flag", nil).Value)
+	assert.NoError(t, client.RemoveOverride(t.Context(), "non-existing"))
+}
+
+func TestOverrides_Values(t *testing.T) {
+	key, srv := newOverrideTestServer(t)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+
+	assert.ErrorIs(t, client.SetOverride(t.Context(), "non-existing", false, time.Minute), ErrOverrideKeyNotFound)
+	assert.ErrorIs(t, client.SetOverride(t.Context(), "flag", "false", time.Minute), ErrOverrideTypeMismatch)
+	assert.ErrorIs(t, client.SetOverride(t.Context(), "num", 5.5, time.Minute), ErrOverrideTypeMismatch)
+
+	assert.NoError(t, client.SetOverride(t.Context(), "num", float64(10), time.Minute))
+	assert.Equal(t, 10, client.Eval("num", nil).Value)
+}
+
+func TestOverrides_SharedCache(t *testing.T) {
+	key, srv := newOverrideTestServer(t)
+	s := miniredis.RunT(t)
+
+	ctx1 := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, newRedisCache(s.Addr()))
+	ctx1.SyncOverrides = true
+	client1 := NewClient(ctx1, log.NewNullLogger())
+	defer client1.Close()
+
+	assert.NoError(t, client1.SetOverride(t.Context(), "num", 10, time.Minute))
+
+	stored, err := s.Get(overridesCacheKey(key))
+	assert.NoError(t, err)
+	var persisted map[string]model.FlagOverride
+	assert.NoError(t, json.Unmarshal([]byte(stored), &persisted))
+	assert.Equal(t, float64(10), persisted["num"].Value)
+
+	ctx2 := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, newRedisCache(s.Addr()))
+	ctx2.SdkId = "test2"
+	ctx2.SyncOverrides = true
+	client2 := NewClient(ctx2, log.NewNullLogger())
+	defer client2.Close()
+
+	testutils.WaitUntil(2*time.Second, func() bool {
+		return client2.Eval("num", nil).IsOverridden
+	})
+	assert.Equal(t, 10, client2.Eval("num", nil).Value)
+}
+
+func TestOverrides_SharedCache_ConcurrentWriters(t *testing.T) {
+	key, srv := newOverrideTestServer(t)
+	s := miniredis.RunT(t)
+
+	ctx1 := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, newRedisCache(s.Addr()))
+	ctx1.SyncOverrides = true
+	client1 := NewClient(ctx1, log.NewNullLogger())
+	defer client1.Close()
+	ctx2 := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, newRedisCache(s.Addr()))
+	ctx2.SdkId = "test2"
+	ctx2.SyncOverrides = true
+	client2 := NewClient(ctx2, log.NewNullLogger())
+	defer client2.Close()
+
+	// both writes happen before the periodic sync could pick up the other instance's change
+	assert.NoError(t, client1.SetOverride(t.Context(), "num", 10, time.Minute))
+	assert.NoError(t, client2.SetOverride(t.Context(), "flag", false, time.Minute))
+
+	stored, err := s.Get(overridesCacheKey(key))
+	assert.NoError(t, err)
+	var persisted map[string]model.FlagOverride
+	assert.NoError(t, json.Unmarshal([]byte(stored), &persisted))
+	assert.Len(t, persisted, 2)
+	assert.Equal(t, float64(10), persisted["num"].Value)
+	assert.Equal(t, false, persisted["flag"].Value)
+
+	assert.NoError(t, client1.RemoveOverride(t.Context(), "flag"))
+	stored, err = s.Get(overridesCacheKey(key))
+	assert.NoError(t, err)
+	persisted = nil
+	assert.NoError(t, json.Unmarshal([]byte(stored), &persisted))
+	assert.Len(t, persisted, 1)
+	assert.Contains(t, persisted, "num")
+}
+
+func TestOverrides_NoSyncWithoutOverrideApi(t *testing.T) {
+	key, srv := newOverrideTestServer(t)
+	s := miniredis.RunT(t)
+	_ = s.Set(overridesCacheKey(key), `{"num":{"value":10,"expiresAt":"`+time.Now().Add(time.Minute).UTC().Format(time.RFC3339)+`"}}`)
+
+	client := NewClient(NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, newRedisCache(s.Addr())), log.NewNullLogger())
+	defer client.Close()
+	<-client.Ready()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, client.Eval("num", nil).IsOverridden)
+	assert.Empty(t, client.Overrides())
+}
+
+func newOverrideTestServer(t *testing.T) (string, *httptest.Server) {
+	key := configcattest.RandomSDKKey()
+	var h configcattest.Handler
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {
+			Default: true,
+		},
+		"num": {
+			Default: 5,
+		},
+	})
+	srv := httptest.NewServer(&h)
+	t.Cleanup(srv.Close)
+	return key, srv
+}
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -23,8 +24,14 @@ import (
 
 const (
 	validEmptySdkKey = "0000000000000000000000/0000000000000000000000"
+
+	// NotReadyRetryAfter is the number of seconds the callers are asked to wait before retrying when an SDK is not ready yet.
+	NotReadyRetryAfter = 5
 )
 
+// ErrNotReady is returned when an SDK couldn't finish its initialization within the configured init timeout.
+var ErrNotReady = errors.New("SDK is not initialized yet")
+
 type Client interface {
 	pubsub.SubscriptionHandler[struct{}]
 	Eval(key string, user model.UserAttrs) model.EvalData
@@ -40,6 +47,17 @@ type Client interface {
 	WebhookSignatureValidFor() int
 	IsInValidState() bool
 	Ready() <-chan struct{}
+	WaitReady() error
+	ServeDefaultsWhileInitializing() bool
+	SetOverride(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	RemoveOverride(ctx context.Context, key string) error
+	Overrides() map[string]model.FlagOverride
+	History() model.ConfigHistory
+	ConfigVersion(version int) (*model.ConfigVersion, error)
+	DiffVersions(from int, to int) (*model.ConfigDiff, error)
+	Pin(ctx context.Context, version int) error
+	Unpin(ctx context.Context) error
+	Changes(since int) []model.ConfigChange
 }
 
 type Context struct {
@@ -51,21 +69,32 @@ type Context struct {
 	StatusReporter     status.Reporter
 	EvalReporter       statistics.Reporter
 	ExternalCache      cache.ReaderWriter
+	LocalSnapshot      cache.ReaderWriter
 	Transport          http.RoundTripper
+	SyncOverrides      bool // whether overrides set through the API are synced with other instances via ExternalCache
 }
 
 type client struct {
 	configCatClient *configcat.Client
+	clientConfig    configcat.Config
 	defaultAttrs    model.UserAttrs
 	log             log.Logger
 	cache           store.EntryStore
 	sdkCtx          *Context
 	initialized     atomic.Bool
 	ready           chan struct{}
-	readyOnce       sync.Once
+	initDeadline    time.Time
 	ctx             context.Context
 	ctxCancel       func()
 	mu              sync.Mutex
+	overrides       atomic.Pointer[flagOverrides]
+	overridesMu     sync.Mutex
+	syncDone        chan struct{}
+	history         []*model.ConfigVersion
+	historyMu       sync.Mutex
+	pinned          atomic.Pointer[pinnedConfig]
+	loggedChangeId  int
+	changesMu       sync.Mutex
 	pubsub.Publisher[struct{}]
 }
 
@@ -78,21 +107,36 @@ func NewClient(sdkCtx *Context, log log.Logger) Client {
 	if offline && sdkCtx.SDKConf.Offline.Local.FilePath != "" {
 		key = validEmptySdkKey
 		storage = file.NewFileStore(sdkCtx.SdkId, &sdkCtx.SDKConf.Offline.Local, sdkCtx.StatusReporter, log.WithLevel(sdkCtx.SDKConf.Offline.Log.GetLevel()))
+	} else if offline && sdkCtx.SDKConf.Offline.Upstream.Url != "" {
+		key = validEmptySdkKey
+		transport := sdkCtx.TelemetryReporter.InstrumentHttpClient(sdkCtx.Transport, telemetry.SdkId.V(sdkCtx.SdkId), telemetry.Source.V("upstream"))
+		storage = store.NewUpstreamStore(sdkCtx.SdkId, &sdkCtx.SDKConf.Offline.Upstream, transport, sdkCtx.StatusReporter, log.WithLevel(sdkCtx.SDKConf.Offline.Log.GetLevel()))
 	} else if offline && sdkCtx.SDKConf.Offline.UseCache && sdkCtx.ExternalCache != nil {
 		cacheKey := configcatcache.ProduceCacheKey(sdkCtx.SDKConf.Key, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion)
 		cacheStore := store.NewCacheStore(sdkCtx.ExternalCache, sdkCtx.StatusReporter)
-		storage = store.NewNotifyingCacheStore(sdkCtx.SdkId, cacheKey, cacheStore, &sdkCtx.SDKConf.Offline, sdkCtx.TelemetryReporter, sdkCtx.StatusReporter, log.WithLevel(sdkCtx.SDKConf.Offline.Log.GetLevel()))
+		offlineLog := log.WithLevel(sdkCtx.SDKConf.Offline.Log.GetLevel())
+		if fileCache, ok := cache.AsFileBased(sdkCtx.ExternalCache); ok {
+			watcher := file.NewWatcher(&config.LocalConfig{FilePath: fileCache.EntryPath(cacheKey), PollInterval: sdkCtx.SDKConf.Offline.CachePollInterval}, offlineLog)
+			storage = store.NewWatchingCacheStore(sdkCtx.SdkId, cacheKey, cacheStore, watcher, &sdkCtx.SDKConf.Offline, sdkCtx.TelemetryReporter, sdkCtx.StatusReporter, offlineLog)
+		} else {
+			storage = store.NewNotifyingCacheStore(sdkCtx.SdkId, cacheKey, cacheStore, &sdkCtx.SDKConf.Offline, sdkCtx.TelemetryReporter, sdkCtx.StatusReporter, offlineLog)
+		}
 	} else if !offline && sdkCtx.ExternalCache != nil {
 		storage = store.NewCacheStore(sdkCtx.ExternalCache, sdkCtx.StatusReporter)
 	} else {
 		storage = store.NewInMemoryStorage()
 	}
+	if !offline && sdkCtx.LocalSnapshot != nil {
+		storage = store.NewLocalSnapshotStore(storage.(store.CacheEntryStore), sdkCtx.LocalSnapshot, sdkLog)
+	}
 	client := &client{
 		Publisher:    pubsub.NewPublisher[struct{}](),
 		log:          sdkLog,
 		cache:        storage.(store.EntryStore),
 		sdkCtx:       sdkCtx,
 		ready:        make(chan struct{}),
+		syncDone:     make(chan struct{}),
+		initDeadline: time.Now().Add(time.Duration(sdkCtx.SDKConf.Init.Timeout) * time.Second),
 		defaultAttrs: model.MergeUserAttrs(sdkCtx.GlobalDefaultAttrs, sdkCtx.SDKConf.DefaultAttrs),
 	}
 	client.ctx, client.ctxCancel = context.WithCancel(context.Background())
@@ -113,7 +157,8 @@ func NewClient(sdkCtx *Context, log log.Logger) Client {
 			client.signal()
 		}
 		clientConfig.Transport = sdkCtx.TelemetryReporter.InstrumentHttpClient(
-			status.InterceptSdk(sdkCtx.SdkId, sdkCtx.StatusReporter, clientConfig.Transport),
+			telemetry.InterceptSdk(sdkCtx.SdkId, sdkCtx.TelemetryReporter,
+				status.InterceptSdk(sdkCtx.SdkId, sdkCtx.StatusReporter, clientConfig.Transport)),
 			telemetry.SdkId.V(sdkCtx.SdkId),
 			telemetry.Source.V("sdk"))
 
@@ -133,15 +178,38 @@ func NewClient(sdkCtx *Context, log log.Logger) Client {
 				UserAttrs: user})
 		}
 	}
+	if sdkCtx.SDKConf.Overrides.IsSet() {
+		clientConfig.FlagOverrides = &configcat.FlagOverrides{
+			Behavior: configcat.LocalOverRemote,
+			FilePath: sdkCtx.SDKConf.Overrides.FilePath,
+			Values:   sdkCtx.SDKConf.Overrides.Values,
+		}
+		if sdkCtx.SDKConf.Overrides.Behavior == config.OverrideRemoteOverLocal {
+			clientConfig.FlagOverrides.Behavior = configcat.RemoteOverLocal
+		}
+	}
 	if sdkCtx.SDKConf.DataGovernance == "eu" {
 		clientConfig.DataGovernance = configcat.EUOnly
 	}
+	client.clientConfig = clientConfig
 	client.configCatClient = configcat.NewCustomClient(clientConfig)
 	go func() {
 		client.mu.Lock()
 		defer client.mu.Unlock()
+		markReady := sync.OnceFunc(func() {
+			close(client.ready)
+		})
+		if client.bootstrapFromLocalSnapshot() {
+			client.recordVersion()
+			client.logChanges()
+			client.reportConfig()
+			markReady()
+		}
 		_ = client.Refresh(client.ctx)
-		close(client.ready)
+		client.recordVersion()
+		client.logChanges()
+		client.reportConfig()
+		markReady()
 	}()
 
 	if notifier, ok := storage.(store.NotifyingStore); ok {
@@ -149,6 +217,7 @@ func NewClient(sdkCtx *Context, log log.Logger) Client {
 	} else {
 		go client.poll()
 	}
+	go client.syncSharedState()
 	sdkLog.Reportf("started")
 	return client
 }
@@ -193,46 +262,99 @@ func (c *client) signal() {
 	if c.sdkCtx.SDKConf.Offline.Enabled {
 		_ = c.Refresh(c.ctx)
 	}
+	c.recordVersion()
+	c.logChanges()
+	c.reportConfig()
 	c.Publish(struct{}{})
 }
 
-func (c *client) ensureReady() {
-	c.readyOnce.Do(func() {
-		select {
-		case <-c.ready:
-		case <-c.ctx.Done():
+func (c *client) snapshot(user model.UserAttrs) *configcat.Snapshot {
+	if pinned := c.pinned.Load(); pinned != nil {
+		return pinned.client.Snapshot(user)
+	}
+	return c.configCatClient.Snapshot(user)
+}
+
+// ensureReady waits for the SDK's first refresh, but not longer than the configured init timeout counted from the SDK's start.
+func (c *client) ensureReady() bool {
+	select {
+	case <-c.ready:
+		return true
+	default:
+	}
+	var timeout <-chan time.Time
+	if c.sdkCtx.SDKConf.Init.Timeout > 0 {
+		remaining := time.Until(c.initDeadline)
+		if remaining <= 0 {
+			return false
 		}
-	})
+		timer := time.NewTimer(remaining)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-c.ready:
+		return true
+	case <-timeout:
+		return false
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+func (c *client) WaitReady() error {
+	if !c.ensureReady() {
+		return ErrNotReady
+	}
+	return nil
+}
+
+func (c *client) ServeDefaultsWhileInitializing() bool {
+	return c.sdkCtx.SDKConf.Init.ServeDefaults
 }
 
 func (c *client) Eval(key string, user model.UserAttrs) model.EvalData {
-	c.ensureReady()
+	if !c.ensureReady() {
+		return model.EvalData{Error: ErrNotReady}
+	}
+	c.sdkCtx.TelemetryReporter.RecordEvaluation(c.sdkCtx.SdkId, key)
 	mergedUser := model.MergeUserAttrs(c.defaultAttrs, user)
-	details := c.configCatClient.Snapshot(mergedUser).GetValueDetails(key)
-	return model.EvalData{Value: details.Value, VariationId: details.Data.VariationID, User: details.Data.User, Error: details.Data.Error,
+	details := c.snapshot(mergedUser).GetValueDetails(key)
+	data := model.EvalData{Value: details.Value, VariationId: details.Data.VariationID, User: details.Data.User, Error: details.Data.Error,
 		IsTargeting: details.Data.MatchedPercentageOption != nil || details.Data.MatchedTargetingRule != nil}
+	c.applyOverride(key, &data)
+	return data
 }
 
 func (c *client) EvalAll(user model.UserAttrs) map[string]model.EvalData {
-	c.ensureReady()
+	if !c.ensureReady() {
+		return map[string]model.EvalData{}
+	}
 	mergedUser := model.MergeUserAttrs(c.defaultAttrs, user)
-	allDetails := c.configCatClient.Snapshot(mergedUser).GetAllValueDetails()
+	allDetails := c.snapshot(mergedUser).GetAllValueDetails()
 	result := make(map[string]model.EvalData, len(allDetails))
 	for _, details := range allDetails {
-		result[details.Data.Key] = model.EvalData{Value: details.Value, VariationId: details.Data.VariationID, User: details.Data.User, Error: details.Data.Error,
+		c.sdkCtx.TelemetryReporter.RecordEvaluation(c.sdkCtx.SdkId, details.Data.Key)
+		data := model.EvalData{Value: details.Value, VariationId: details.Data.VariationID, User: details.Data.User, Error: details.Data.Error,
 			IsTargeting: details.Data.MatchedPercentageOption != nil || details.Data.MatchedTargetingRule != nil}
+		c.applyOverride(details.Data.Key, &data)
+		result[details.Data.Key] = data
 	}
 	return result
 }
 
 func (c *client) Keys() []string {
-	c.ensureReady()
-	return c.configCatClient.GetAllKeys()
+	if !c.ensureReady() {
+		return nil
+	}
+	return c.snapshot(nil).GetAllKeys()
 }
 
 func (c *client) HasKey(key string) bool {
-	c.ensureReady()
-	keys := c.configCatClient.GetAllKeys()
+	if !c.ensureReady() {
+		return false
+	}
+	keys := c.snapshot(nil).GetAllKeys()
 	for _, k := range keys {
 		if k == key {
 			return true
@@ -241,8 +363,22 @@ func (c *client) HasKey(key string) bool {
 	return false
 }
 
+// reportConfig publishes the metadata of the currently cached config JSON to the status and telemetry reporters.
+func (c *client) reportConfig() {
+	entry := c.cache.LoadEntry()
+	if entry.Empty {
+		return
+	}
+	c.sdkCtx.TelemetryReporter.RecordConfigFetchTime(c.sdkCtx.SdkId, entry.FetchTime)
+	flagCount := len(c.configCatClient.Snapshot(nil).GetAllKeys())
+	c.sdkCtx.StatusReporter.ReportConfig(c.sdkCtx.SdkId, entry.ETag, entry.FetchTime, flagCount)
+}
+
 func (c *client) GetCachedJson() *store.EntryWithEtag {
 	c.ensureReady()
+	if pinned := c.pinned.Load(); pinned != nil {
+		return pinned.entry
+	}
 	return c.cache.LoadEntry()
 }
 
@@ -272,7 +408,7 @@ func (c *client) WebhookSignatureValidFor() int {
 }
 
 func (c *client) IsInValidState() bool {
-	return !c.GetCachedJson().Empty
+	return c.ensureReady() && !c.GetCachedJson().Empty
 }
 
 func (c *client) Ready() <-chan struct{} {
@@ -285,9 +421,11 @@ func (c *client) Close() {
 	}
 	c.Publisher.Close()
 	c.ctxCancel()
+	<-c.syncDone
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.configCatClient.Close()
+	c.pinned.Swap(nil).close()
 	c.log.Reportf("shutdown complete")
 }
 
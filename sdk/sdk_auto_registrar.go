@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/configcat/configcat-proxy/cache"
@@ -23,6 +24,7 @@ import (
 
 type AutoRegistrar interface {
 	Refresh()
+	ProfileSnapshot() *ProfileSnapshot
 	pubsub.SubscriptionHandler[string]
 	Registrar
 }
@@ -32,6 +34,7 @@ type autoRegistrar struct {
 	options            *model.OptionsModel
 	cacheKey           string
 	etag               string
+	profile            atomic.Pointer[ProfileSnapshot]
 	sdkClients         *xsync.MapOf[string, Client]
 	sdkClientsBySdkKey *xsync.MapOf[string, Client]
 	httpClient         *http.Client
@@ -41,6 +44,7 @@ type autoRegistrar struct {
 	telemetryReporter  telemetry.Reporter
 	statusReporter     status.Reporter
 	cache              cache.ReaderWriter
+	localSnapshot      cache.ReaderWriter
 	log                log.Logger
 	sdkTransport       http.RoundTripper
 	pubsub.Publisher[string]
@@ -58,6 +62,7 @@ func newAutoRegistrar(conf *config.Config, telemetryReporter telemetry.Reporter,
 		telemetryReporter:  telemetryReporter,
 		statusReporter:     statusReporter,
 		cache:              cache,
+		localSnapshot:      buildLocalSnapshot(&conf.LocalSnapshot, log),
 		log:                regLog,
 		Publisher:          pubsub.NewPublisher[string](),
 		httpClient: &http.Client{
@@ -70,12 +75,16 @@ func newAutoRegistrar(conf *config.Config, telemetryReporter telemetry.Reporter,
 	}
 	registrar.ctx, registrar.ctxCancel = context.WithCancel(context.Background())
 
-	timeoutCtx, timeoutCancel := context.WithTimeout(registrar.ctx, time.Second*15)
-	defer timeoutCancel()
-	autoConfig, err := registrar.getConfig(timeoutCtx)
-	if err != nil {
-		regLog.Errorf("%v", err)
-		return nil, err
+	autoConfig, bootstrapped := registrar.readLocalSnapshot(registrar.ctx)
+	if !bootstrapped {
+		timeoutCtx, timeoutCancel := context.WithTimeout(registrar.ctx, time.Second*15)
+		defer timeoutCancel()
+		var err error
+		autoConfig, err = registrar.getConfig(timeoutCtx)
+		if err != nil {
+			regLog.Errorf("%v", err)
+			return nil, err
+		}
 	}
 	registrar.options = &autoConfig.Options
 	for sdkId, sdkModel := range autoConfig.SDKs {
@@ -96,7 +105,8 @@ func newAutoRegistrar(conf *config.Config, telemetryReporter telemetry.Reporter,
 	} else {
 		interval = conf.Profile.PollInterval
 	}
-	go registrar.run(interval)
+	// when bootstrapped from the local snapshot, the remote profile is fetched right away in the background
+	go registrar.run(interval, bootstrapped)
 	return registrar, nil
 }
 
@@ -130,6 +140,10 @@ func (r *autoRegistrar) GetAll() map[string]Client {
 	return all
 }
 
+func (r *autoRegistrar) ProfileSnapshot() *ProfileSnapshot {
+	return r.profile.Load()
+}
+
 func (r *autoRegistrar) Refresh() {
 	select {
 	case <-r.ctx.Done():
@@ -151,13 +165,16 @@ func (r *autoRegistrar) Close() {
 	r.log.Reportf("shutdown complete")
 }
 
-func (r *autoRegistrar) run(interval int) {
+func (r *autoRegistrar) run(interval int, refreshNow bool) {
 	inter := interval
 	if inter < 1 {
 		inter = config.DefaultAutoSdkPollInterval
 	}
 	poller := time.NewTicker(time.Duration(inter) * time.Second)
 	defer poller.Stop()
+	if refreshNow {
+		r.refreshConfig()
+	}
 	for {
 		select {
 		case <-poller.C:
@@ -224,12 +241,18 @@ func (r *autoRegistrar) getConfig(ctx context.Context) (*model.ProxyConfigModel,
 	cachedConfig, cachedEtag, cacheErr := r.readCache(ctx)
 	if r.conf.GlobalOfflineConfig.Enabled {
 		if cacheErr != nil {
+			r.statusReporter.ReportError(status.Profile, "failed to read profile from cache")
+			r.telemetryReporter.RecordProfilePoll(telemetry.FetchFailure)
 			return nil, fmt.Errorf("could not load proxy profile from cache: %s", cacheErr)
 		}
+		r.statusReporter.ReportOk(status.Profile, "profile loaded from cache")
+		r.telemetryReporter.RecordProfilePoll(telemetry.FetchSuccess)
 		return r.parseConfig(cachedConfig, cachedEtag)
 	}
 	fetched, fetchedEtag, fetchErr := r.fetchConfig(ctx, cachedEtag)
 	if fetchErr != nil {
+		r.statusReporter.ReportError(status.Profile, "profile fetch failed")
+		r.telemetryReporter.RecordProfilePoll(telemetry.FetchFailure)
 		r.log.Errorf("could not fetch proxy profile, falling back to cache: %v", fetchErr)
 		if cacheErr != nil {
 			return nil, fmt.Errorf("could not load proxy profile from cache: %s", cacheErr)
@@ -237,14 +260,19 @@ func (r *autoRegistrar) getConfig(ctx context.Context) (*model.ProxyConfigModel,
 		return r.parseConfig(cachedConfig, cachedEtag)
 	}
 	if fetched == nil { // 304
+		r.statusReporter.ReportOk(status.Profile, "profile not modified")
+		r.telemetryReporter.RecordProfilePoll(telemetry.FetchNotModified)
 		r.log.Debugf("proxy profile not modified")
 		return r.parseConfig(cachedConfig, cachedEtag)
 	} else { // 200
+		r.statusReporter.ReportOk(status.Profile, "profile fetched")
+		r.telemetryReporter.RecordProfilePoll(telemetry.FetchSuccess)
 		r.log.Debugf("proxy profile fetched with etag %s", fetchedEtag)
 		err := r.writeCache(ctx, fetched, fetchedEtag)
 		if err != nil {
 			r.log.Errorf("could not write proxy profile to cache: %v", err)
 		}
+		r.writeLocalSnapshot(ctx, fetched, fetchedEtag)
 		return r.parseConfig(fetched, fetchedEtag)
 	}
 }
@@ -258,6 +286,7 @@ func (r *autoRegistrar) parseConfig(body []byte, etag string) (*model.ProxyConfi
 		return nil, fmt.Errorf("error during parsing proxy profile: %v", err)
 	}
 	r.etag = etag
+	r.profile.Store(&ProfileSnapshot{CacheKey: r.cacheKey, ETag: etag, Config: body})
 	r.log.Debugf("proxy profile loaded, got %d SDK keys", len(parsed.SDKs))
 	return &parsed, nil
 }
@@ -304,6 +333,7 @@ func (r *autoRegistrar) buildSdkConfig(sdkId string, sdkModel *model.SdkConfigMo
 		PollInterval:             r.options.PollInterval,
 		DataGovernance:           r.options.DataGovernance,
 		Log:                      r.conf.Profile.SDKs.Log,
+		Init:                     r.conf.Profile.SDKs.Init,
 		WebhookSigningKey:        r.conf.Profile.WebhookSigningKey,
 		WebhookSignatureValidFor: r.conf.Profile.WebhookSignatureValidFor,
 	}
@@ -329,7 +359,9 @@ func (r *autoRegistrar) buildSdkClient(sdkId string, sdkConfig *config.SDKConfig
 		GlobalDefaultAttrs: r.conf.DefaultAttrs,
 		SdkId:              sdkId,
 		ExternalCache:      r.cache,
+		LocalSnapshot:      r.localSnapshot,
 		Transport:          r.sdkTransport,
+		SyncOverrides:      r.conf.Http.IsOverrideApiEnabled(),
 	}
 	if len(sdkModel.Key2) > 0 {
 		ctx.SecondarySdkKey.Store(&sdkModel.Key2)
@@ -355,6 +387,7 @@ func (r *autoRegistrar) deleteSdkClients(sdkIds []string) {
 			}
 			sdkClient.Close()
 			r.statusReporter.RemoveSdk(sdkId)
+			r.telemetryReporter.RemoveSdk(sdkId)
 			r.Publish(sdkId)
 		}
 	}
@@ -449,6 +482,39 @@ func (r *autoRegistrar) writeCache(ctx context.Context, config []byte, etag stri
 	return nil
 }
 
+// readLocalSnapshot loads the last-known-good proxy profile from the local disk snapshot, so the SDKs can be
+// set up without waiting for the first profile fetch.
+func (r *autoRegistrar) readLocalSnapshot(ctx context.Context) (*model.ProxyConfigModel, bool) {
+	if r.localSnapshot == nil || r.conf.GlobalOfflineConfig.Enabled {
+		return nil, false
+	}
+	cached, err := r.localSnapshot.Get(ctx, r.cacheKey)
+	if err != nil {
+		return nil, false
+	}
+	body, etag, err := cacheSegmentsFromBytes(cached)
+	if err != nil {
+		r.log.Errorf("could not read proxy profile from the local snapshot: %v", err)
+		return nil, false
+	}
+	parsed, err := r.parseConfig(body, etag)
+	if err != nil || parsed == nil {
+		r.log.Errorf("could not load proxy profile from the local snapshot: %v", err)
+		return nil, false
+	}
+	r.log.Reportf("proxy profile bootstrapped from the local snapshot")
+	return parsed, true
+}
+
+func (r *autoRegistrar) writeLocalSnapshot(ctx context.Context, config []byte, etag string) {
+	if r.localSnapshot == nil {
+		return
+	}
+	if err := r.localSnapshot.Set(ctx, r.cacheKey, cacheSegmentsToBytes(etag, config)); err != nil {
+		r.log.Errorf("could not write proxy profile to the local snapshot: %v", err)
+	}
+}
+
 const newLineByte byte = '\n'
 
 func cacheSegmentsFromBytes(cacheBytes []byte) (config []byte, eTag string, err error) {
@@ -380,6 +380,24 @@ func TestAutoRegistrar_Cache_Rebuild(t *testing.T) {
 	})
 }
 
+func TestAutoRegistrar_ProfileStatus(t *testing.T) {
+	reg, h, _ := NewTestAutoRegistrar(t, config.Config{}, nil, log.NewNullLogger())
+	reporter := reg.(*autoRegistrar).statusReporter
+
+	profile := reporter.GetStatus().Profile
+	assert.Equal(t, status.Healthy, profile.Status)
+	assert.NotNil(t, profile.LastSuccessfulFetch)
+	assert.Equal(t, "profile fetched", profile.History[0].Message)
+
+	h.SetFailing(true)
+	reg.Refresh()
+
+	assert.Eventually(t, func() bool {
+		return reporter.GetStatus().Profile.ConsecutiveFailures == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "profile fetch failed", reporter.GetStatus().Profile.History[1].Message)
+}
+
 func TestAutoRegistrar_GetBySdkKey(t *testing.T) {
 	cache := miniredis.RunT(t)
 	extCache := newRedisCache(cache.Addr())
@@ -395,3 +413,22 @@ func TestAutoRegistrar_GetBySdkKey(t *testing.T) {
 	assert.Equal(t, `{"SDKs":{"test":{"Key1":"`+sdkClient.sdkCtx.SDKConf.Key+`","Key2":""}},"Options":{"PollInterval":60,"DataGovernance":"global"}}`, string(cachedBody))
 	assert.Equal(t, utils.GenerateEtag(cachedBody), "W/"+cachedEtag)
 }
+
+func TestAutoRegistrar_LocalSnapshot(t *testing.T) {
+	conf := config.Config{Profile: config.ProfileConfig{Key: "test-reg", PollInterval: 60}, LocalSnapshot: config.LocalSnapshotConfig{Enabled: true, Path: t.TempDir()}}
+	reg, _, _ := NewTestAutoRegistrar(t, conf, nil, log.NewNullLogger())
+	assert.True(t, reg.GetSdkOrNil("test").Eval("flag", nil).Value.(bool))
+
+	// neither the profile API nor the CDN is reachable
+	conf.Profile.BaseUrl = "http://localhost:1"
+	conf.Profile.SDKs.BaseUrl = "http://localhost:1"
+	reg2, err := newAutoRegistrar(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), nil, log.NewNullLogger())
+	assert.NoError(t, err)
+	defer reg2.Close()
+
+	sdkClient := reg2.GetSdkOrNil("test")
+	assert.NotNil(t, sdkClient)
+	<-sdkClient.Ready()
+	assert.True(t, sdkClient.IsInValidState())
+	assert.True(t, sdkClient.Eval("flag", nil).Value.(bool))
+}
@@ -2,14 +2,25 @@ package sdk
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/configcat/configcat-proxy/cache"
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/pubsub"
+	"github.com/puzpuzpuz/xsync/v3"
+)
+
+var (
+	ErrSdkAlreadyExists = errors.New("SDK already exists")
+	ErrSdkNotFound      = errors.New("SDK not found")
+	ErrSdkKeyInUse      = errors.New("SDK key is already used by another SDK")
 )
 
 type Registrar interface {
@@ -20,9 +31,26 @@ type Registrar interface {
 	Close()
 }
 
+type ManualRegistrar interface {
+	AddSdk(sdkId string, conf *config.SDKConfig) error
+	ResetSdk(sdkId string, conf *config.SDKConfig) error
+	RemoveSdk(sdkId string) error
+	pubsub.SubscriptionHandler[string]
+	Registrar
+}
+
 type manualRegistrar struct {
-	sdkClients         map[string]Client
-	sdkClientsBySdkKey map[string]Client
+	sdkClients         *xsync.MapOf[string, Client]
+	sdkClientsBySdkKey *xsync.MapOf[string, Client]
+	conf               *config.Config
+	telemetryReporter  telemetry.Reporter
+	statusReporter     status.Reporter
+	cache              cache.ReaderWriter
+	localSnapshot      cache.ReaderWriter
+	transport          http.RoundTripper
+	log                log.Logger
+	mu                 sync.Mutex
+	pubsub.Publisher[string]
 }
 
 func NewRegistrar(conf *config.Config, telemetryReporter telemetry.Reporter, statusReporter status.Reporter, externalCache cache.ReaderWriter, log log.Logger) (Registrar, error) {
@@ -34,48 +62,158 @@ func NewRegistrar(conf *config.Config, telemetryReporter telemetry.Reporter, sta
 
 func newManualRegistrar(conf *config.Config, telemetryReporter telemetry.Reporter, statusReporter status.Reporter, externalCache cache.ReaderWriter, log log.Logger) (*manualRegistrar, error) {
 	regLog := log.WithPrefix("sdk-registrar").WithLevel(conf.Profile.Log.GetLevel())
-	transport := buildTransport(&conf.HttpProxy, regLog)
-	sdkClients := make(map[string]Client, len(conf.SDKs))
-	sdkClientsBySdkKey := make(map[string]Client, len(conf.SDKs))
+	registrar := &manualRegistrar{
+		sdkClients:         xsync.NewMapOf[string, Client](),
+		sdkClientsBySdkKey: xsync.NewMapOf[string, Client](),
+		conf:               conf,
+		telemetryReporter:  telemetryReporter,
+		statusReporter:     statusReporter,
+		cache:              externalCache,
+		localSnapshot:      buildLocalSnapshot(&conf.LocalSnapshot, log),
+		transport:          buildTransport(&conf.HttpProxy, regLog),
+		log:                regLog,
+		Publisher:          pubsub.NewPublisher[string](),
+	}
 	for key, sdkConf := range conf.SDKs {
 		statusReporter.RegisterSdk(key, sdkConf)
-		sdkClient := NewClient(&Context{
-			SDKConf:            sdkConf,
-			TelemetryReporter:  telemetryReporter,
-			StatusReporter:     statusReporter,
-			GlobalDefaultAttrs: conf.DefaultAttrs,
-			SdkId:              key,
-			ExternalCache:      externalCache,
-			Transport:          transport,
-		}, regLog)
-		sdkClients[key] = sdkClient
-		sdkClientsBySdkKey[sdkConf.Key] = sdkClient
+		sdkClient := registrar.buildSdkClient(key, sdkConf)
+		registrar.sdkClients.Store(key, sdkClient)
+		registrar.sdkClientsBySdkKey.Store(sdkConf.Key, sdkClient)
 	}
-	return &manualRegistrar{sdkClients: sdkClients, sdkClientsBySdkKey: sdkClientsBySdkKey}, nil
+	return registrar, nil
 }
 
 func (r *manualRegistrar) GetSdkOrNil(id string) Client {
-	return r.sdkClients[id]
+	if sdk, ok := r.sdkClients.Load(id); ok {
+		return sdk
+	}
+	return nil
 }
 
 func (r *manualRegistrar) GetSdkByKeyOrNil(sdkKey string) Client {
-	return r.sdkClientsBySdkKey[sdkKey]
+	if sdk, ok := r.sdkClientsBySdkKey.Load(sdkKey); ok {
+		return sdk
+	}
+	return nil
 }
 
 func (r *manualRegistrar) RefreshAll(ctx context.Context) {
-	for _, sdkClient := range r.sdkClients {
-		_ = sdkClient.Refresh(ctx)
-	}
+	r.sdkClients.Range(func(key string, value Client) bool {
+		_ = value.Refresh(ctx)
+		return true
+	})
 }
 
 func (r *manualRegistrar) GetAll() map[string]Client {
-	return r.sdkClients
+	all := make(map[string]Client, r.sdkClients.Size())
+	r.sdkClients.Range(func(key string, value Client) bool {
+		all[key] = value
+		return true
+	})
+	return all
+}
+
+func (r *manualRegistrar) AddSdk(sdkId string, sdkConf *config.SDKConfig) error {
+	if err := r.conf.InitSdkConfig(sdkId, sdkConf); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sdkClients.Load(sdkId); ok {
+		return fmt.Errorf("%w: '%s'", ErrSdkAlreadyExists, sdkId)
+	}
+	if _, ok := r.sdkClientsBySdkKey.Load(sdkConf.Key); ok {
+		return ErrSdkKeyInUse
+	}
+	r.statusReporter.RegisterSdk(sdkId, sdkConf)
+	sdkClient := r.buildSdkClient(sdkId, sdkConf)
+	r.sdkClients.Store(sdkId, sdkClient)
+	r.sdkClientsBySdkKey.Store(sdkConf.Key, sdkClient)
+	r.log.Reportf("SDK '%s' added", sdkId)
+	r.Publish(sdkId)
+	return nil
+}
+
+func (r *manualRegistrar) ResetSdk(sdkId string, sdkConf *config.SDKConfig) error {
+	if err := r.conf.InitSdkConfig(sdkId, sdkConf); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.sdkClients.Load(sdkId)
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrSdkNotFound, sdkId)
+	}
+	if other, ok := r.sdkClientsBySdkKey.Load(sdkConf.Key); ok && other != existing {
+		return ErrSdkKeyInUse
+	}
+	key, _ := existing.SdkKeys()
+	r.sdkClientsBySdkKey.Delete(key)
+	existing.Close()
+	r.statusReporter.RemoveSdk(sdkId)
+	r.telemetryReporter.RemoveSdk(sdkId)
+	r.statusReporter.RegisterSdk(sdkId, sdkConf)
+	sdkClient := r.buildSdkClient(sdkId, sdkConf)
+	r.sdkClients.Store(sdkId, sdkClient)
+	r.sdkClientsBySdkKey.Store(sdkConf.Key, sdkClient)
+	r.log.Reportf("SDK '%s' reset", sdkId)
+	r.Publish(sdkId)
+	return nil
+}
+
+func (r *manualRegistrar) RemoveSdk(sdkId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sdkClient, ok := r.sdkClients.LoadAndDelete(sdkId)
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrSdkNotFound, sdkId)
+	}
+	key, _ := sdkClient.SdkKeys()
+	r.sdkClientsBySdkKey.Delete(key)
+	sdkClient.Close()
+	r.statusReporter.RemoveSdk(sdkId)
+	r.telemetryReporter.RemoveSdk(sdkId)
+	r.log.Reportf("SDK '%s' removed", sdkId)
+	r.Publish(sdkId)
+	return nil
 }
 
 func (r *manualRegistrar) Close() {
-	for _, sdkClient := range r.sdkClients {
-		sdkClient.Close()
+	r.Publisher.Close()
+	r.sdkClients.Range(func(key string, value Client) bool {
+		value.Close()
+		return true
+	})
+}
+
+func (r *manualRegistrar) buildSdkClient(sdkId string, sdkConf *config.SDKConfig) Client {
+	return NewClient(&Context{
+		SDKConf:            sdkConf,
+		TelemetryReporter:  r.telemetryReporter,
+		StatusReporter:     r.statusReporter,
+		GlobalDefaultAttrs: r.conf.DefaultAttrs,
+		SdkId:              sdkId,
+		ExternalCache:      r.cache,
+		LocalSnapshot:      r.localSnapshot,
+		Transport:          r.transport,
+		SyncOverrides:      r.conf.Http.IsOverrideApiEnabled(),
+	}, r.log)
+}
+
+func buildLocalSnapshot(conf *config.LocalSnapshotConfig, log log.Logger) cache.ReaderWriter {
+	if !conf.Enabled {
+		return nil
+	}
+	snapshot, err := cache.NewLocalSnapshot(conf, log.WithPrefix("local-snapshot"))
+	if err != nil {
+		return nil
 	}
+	return snapshot
 }
 
 func buildTransport(proxyConf *config.HttpProxyConfig, log log.Logger) http.RoundTripper {
@@ -101,3 +101,92 @@ func TestBuildProxy(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "ok from proxy", string(body))
 }
+
+func TestRegistrar_AddSdk(t *testing.T) {
+	reporter := status.NewEmptyReporter()
+	reg, _ := NewRegistrar(&config.Config{
+		SDKs: map[string]*config.SDKConfig{"test1": {Key: "key1"}},
+	}, telemetry.NewEmptyReporter(), reporter, nil, log.NewNullLogger())
+	defer reg.Close()
+
+	manualReg := reg.(ManualRegistrar)
+	sub := make(chan string, 1)
+	manualReg.Subscribe(sub)
+
+	err := manualReg.AddSdk("test2", &config.SDKConfig{Key: "key2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test2", <-sub)
+	assert.NotNil(t, reg.GetSdkOrNil("test2"))
+	assert.NotNil(t, reg.GetSdkByKeyOrNil("key2"))
+	assert.Equal(t, 2, len(reg.GetAll()))
+	assert.Contains(t, reporter.GetStatus().SDKs, "test2")
+
+	err = manualReg.AddSdk("test2", &config.SDKConfig{Key: "key3"})
+	assert.ErrorIs(t, err, ErrSdkAlreadyExists)
+
+	err = manualReg.AddSdk("test3", &config.SDKConfig{Key: "key1"})
+	assert.ErrorIs(t, err, ErrSdkKeyInUse)
+
+	err = manualReg.AddSdk("test3", &config.SDKConfig{})
+	assert.ErrorContains(t, err, "sdk-test3: SDK key is required")
+	assert.Nil(t, reg.GetSdkOrNil("test3"))
+}
+
+func TestRegistrar_ResetSdk(t *testing.T) {
+	reg, _ := NewRegistrar(&config.Config{
+		SDKs: map[string]*config.SDKConfig{"test1": {Key: "key1"}, "test2": {Key: "key2"}},
+	}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), nil, log.NewNullLogger())
+	defer reg.Close()
+
+	manualReg := reg.(ManualRegistrar)
+	sub := make(chan string, 1)
+	manualReg.Subscribe(sub)
+
+	old := reg.GetSdkOrNil("test1").(*client)
+	err := manualReg.ResetSdk("test1", &config.SDKConfig{Key: "key3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test1", <-sub)
+	testutils.WithTimeout(1*time.Second, func() {
+		<-old.ctx.Done()
+	})
+	assert.Nil(t, reg.GetSdkByKeyOrNil("key1"))
+	assert.NotNil(t, reg.GetSdkByKeyOrNil("key3"))
+	assert.NotSame(t, old, reg.GetSdkOrNil("test1"))
+
+	err = manualReg.ResetSdk("test1", &config.SDKConfig{Key: "key3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "test1", <-sub)
+
+	err = manualReg.ResetSdk("test1", &config.SDKConfig{Key: "key2"})
+	assert.ErrorIs(t, err, ErrSdkKeyInUse)
+
+	err = manualReg.ResetSdk("non-existing", &config.SDKConfig{Key: "key4"})
+	assert.ErrorIs(t, err, ErrSdkNotFound)
+}
+
+func TestRegistrar_RemoveSdk(t *testing.T) {
+	reporter := status.NewEmptyReporter()
+	reg, _ := NewRegistrar(&config.Config{
+		SDKs: map[string]*config.SDKConfig{"test1": {Key: "key1"}, "test2": {Key: "key2"}},
+	}, telemetry.NewEmptyReporter(), reporter, nil, log.NewNullLogger())
+	defer reg.Close()
+
+	manualReg := reg.(ManualRegistrar)
+	sub := make(chan string, 1)
+	manualReg.Subscribe(sub)
+
+	old := reg.GetSdkOrNil("test1").(*client)
+	err := manualReg.RemoveSdk("test1")
+	assert.NoError(t, err)
+	assert.Equal(t, "test1", <-sub)
+	testutils.WithTimeout(1*time.Second, func() {
+		<-old.ctx.Done()
+	})
+	assert.Nil(t, reg.GetSdkOrNil("test1"))
+	assert.Nil(t, reg.GetSdkByKeyOrNil("key1"))
+	assert.Equal(t, 1, len(reg.GetAll()))
+	assert.NotContains(t, reporter.GetStatus().SDKs, "test1")
+
+	err = manualReg.RemoveSdk("test1")
+	assert.ErrorIs(t, err, ErrSdkNotFound)
+}
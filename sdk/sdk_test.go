@@ -3,13 +3,16 @@ package sdk
 import (
 	"crypto/sha1"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/configcat/configcat-proxy/cache"
 	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
 	"github.com/configcat/configcat-proxy/internal/testutils"
 	"github.com/configcat/configcat-proxy/internal/utils"
@@ -262,6 +265,38 @@ func TestSdk_Signal_Offline_Redis_Watch(t *testing.T) {
 	assert.Equal(t, "etag2", j.ETag)
 }
 
+func TestSdk_Signal_Offline_Filesystem_Watch(t *testing.T) {
+	sdkKey := configcattest.RandomSDKKey()
+	fsCache, _ := cache.SetupExternalCache(&config.CacheConfig{Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	cacheKey := configcatcache.ProduceCacheKey(sdkKey, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion)
+	cacheEntry := configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`{"f":{"flag":{"a":"","i":"v_flag","v":{"b":true},"t":0}}}`))
+	_ = fsCache.Set(t.Context(), cacheKey, cacheEntry)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{
+		Key:     sdkKey,
+		Offline: config.OfflineConfig{Enabled: true, UseCache: true, CachePollInterval: 60},
+	}, fsCache)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	sub := make(chan struct{})
+	client.Subscribe(sub)
+	data := client.Eval("flag", nil)
+	assert.NoError(t, data.Error)
+	assert.True(t, data.Value.(bool))
+
+	// the poll interval is long, the change must be picked up by the file watcher
+	cacheEntry = configcatcache.CacheSegmentsToBytes(time.Now(), "etag2", []byte(`{"f":{"flag":{"a":"","i":"v_flag","v":{"b":false},"t":0}}}`))
+	_ = fsCache.Set(t.Context(), cacheKey, cacheEntry)
+	testutils.WithTimeout(5*time.Second, func() {
+		<-sub
+	})
+	data = client.Eval("flag", nil)
+	j := client.GetCachedJson()
+	assert.NoError(t, data.Error)
+	assert.False(t, data.Value.(bool))
+	assert.Equal(t, "etag2", j.ETag)
+}
+
 func TestSdk_EvalAll(t *testing.T) {
 	key := configcattest.RandomSDKKey()
 	var h configcattest.Handler
@@ -324,6 +359,64 @@ func TestSdk_EvalAll_Targeting(t *testing.T) {
 	assert.Nil(t, details["flag2"].Error)
 }
 
+func TestSdk_Overrides(t *testing.T) {
+	key := configcattest.RandomSDKKey()
+	var h configcattest.Handler
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag1": {
+			Default: "v1",
+		},
+		"flag2": {
+			Default: "v2",
+		},
+	})
+	srv := httptest.NewServer(&h)
+	defer srv.Close()
+
+	t.Run("local over remote", func(t *testing.T) {
+		ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, Overrides: config.OverrideConfig{Behavior: config.OverrideLocalOverRemote, Values: map[string]interface{}{"flag1": "local", "flag3": true}}}, nil)
+		client := NewClient(ctx, log.NewNullLogger())
+		defer client.Close()
+
+		details := client.EvalAll(nil)
+		assert.Equal(t, 3, len(details))
+		assert.Equal(t, "local", details["flag1"].Value)
+		assert.Equal(t, "v2", details["flag2"].Value)
+		assert.Equal(t, true, details["flag3"].Value)
+	})
+	t.Run("remote over local", func(t *testing.T) {
+		ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, Overrides: config.OverrideConfig{Behavior: config.OverrideRemoteOverLocal, Values: map[string]interface{}{"flag1": "local", "flag3": true}}}, nil)
+		client := NewClient(ctx, log.NewNullLogger())
+		defer client.Close()
+
+		details := client.EvalAll(nil)
+		assert.Equal(t, 3, len(details))
+		assert.Equal(t, "v1", details["flag1"].Value)
+		assert.Equal(t, "v2", details["flag2"].Value)
+		assert.Equal(t, true, details["flag3"].Value)
+	})
+	t.Run("simplified file", func(t *testing.T) {
+		testutils.UseTempFile(`{"flags":{"flag2":"local"}}`, func(path string) {
+			ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, Overrides: config.OverrideConfig{Behavior: config.OverrideLocalOverRemote, FilePath: path}}, nil)
+			client := NewClient(ctx, log.NewNullLogger())
+			defer client.Close()
+
+			assert.Equal(t, "v1", client.Eval("flag1", nil).Value)
+			assert.Equal(t, "local", client.Eval("flag2", nil).Value)
+		})
+	})
+	t.Run("config json file", func(t *testing.T) {
+		testutils.UseTempFile(`{"f":{"flag2":{"t":1,"v":{"s":"local"}}}}`, func(path string) {
+			ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key, Overrides: config.OverrideConfig{Behavior: config.OverrideLocalOverRemote, FilePath: path}}, nil)
+			client := NewClient(ctx, log.NewNullLogger())
+			defer client.Close()
+
+			assert.Equal(t, "v1", client.Eval("flag1", nil).Value)
+			assert.Equal(t, "local", client.Eval("flag2", nil).Value)
+		})
+	})
+}
+
 func TestSdk_Keys(t *testing.T) {
 	key := configcattest.RandomSDKKey()
 	var h configcattest.Handler
@@ -448,6 +541,96 @@ func TestSdk_IsInValidState_EmptyCache_False(t *testing.T) {
 	assert.False(t, client.IsInValidState())
 }
 
+func TestSdk_ReportConfig(t *testing.T) {
+	reporter := status.NewEmptyReporter()
+	reg, _, _ := NewTestRegistrarTWithStatusReporter(t, reporter)
+	client := reg.GetSdkOrNil("test")
+	<-client.Ready()
+
+	stat := reporter.GetStatus().SDKs["test"]
+	assert.Equal(t, client.GetCachedJson().ETag, stat.ConfigETag)
+	assert.Equal(t, len(client.Keys()), stat.FlagCount)
+	assert.NotNil(t, stat.LastSuccessfulFetch)
+	assert.NotEmpty(t, stat.ConfigAge)
+}
+
+type telemetryRecorder struct {
+	telemetry.Reporter
+
+	mu          sync.Mutex
+	fetches     []string
+	fetchTimes  []time.Time
+	evaluations []string
+}
+
+func (r *telemetryRecorder) RecordConfigFetch(_ string, result string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetches = append(r.fetches, result)
+}
+
+func (r *telemetryRecorder) RecordConfigFetchTime(_ string, fetchTime time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetchTimes = append(r.fetchTimes, fetchTime)
+}
+
+func (r *telemetryRecorder) RecordEvaluation(_ string, flag string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evaluations = append(r.evaluations, flag)
+}
+
+func TestSdk_Telemetry(t *testing.T) {
+	key := configcattest.RandomSDKKey()
+	var h configcattest.Handler
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {
+			Default: true,
+		},
+	})
+	srv := httptest.NewServer(&h)
+	defer srv.Close()
+
+	recorder := &telemetryRecorder{Reporter: telemetry.NewEmptyReporter()}
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: key}, nil)
+	ctx.TelemetryReporter = recorder
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+	<-client.Ready()
+
+	client.Eval("flag", nil)
+	client.EvalAll(nil)
+	client.Keys()
+	assert.NoError(t, client.SetOverride(t.Context(), "flag", false, time.Minute))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, []string{telemetry.FetchSuccess}, recorder.fetches)
+	assert.NotEmpty(t, recorder.fetchTimes)
+	assert.Equal(t, []string{"flag", "flag"}, recorder.evaluations)
+}
+
+func TestSdk_InitTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx := NewTestSdkContext(&config.SDKConfig{BaseUrl: srv.URL, Key: configcattest.RandomSDKKey(), Init: config.InitConfig{Timeout: 1, ServeDefaults: true}}, nil)
+	client := NewClient(ctx, log.NewNullLogger())
+	defer client.Close()
+
+	assert.ErrorIs(t, client.WaitReady(), ErrNotReady)
+	assert.ErrorIs(t, client.Eval("flag", nil).Error, ErrNotReady)
+	assert.Empty(t, client.EvalAll(nil))
+	assert.Nil(t, client.Keys())
+	assert.False(t, client.IsInValidState())
+	assert.True(t, client.ServeDefaultsWhileInitializing())
+}
+
 func TestVersion(t *testing.T) {
 	assert.Equal(t, "0.0.0", Version())
 }
@@ -493,7 +676,7 @@ func TestSdk_Cache_Rebuild(t *testing.T) {
 }
 
 func newRedisCache(addr string) cache.ReaderWriter {
-	c, _ := cache.SetupExternalCache(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{addr}}}, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	c, _ := cache.SetupExternalCache(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{addr}}}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	return c
 }
 
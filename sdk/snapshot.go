@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/configcat/configcat-proxy/cache"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+)
+
+// Snapshot holds the config JSONs of each SDK and the proxy profile (when it's used) in a form
+// that can be written into an external cache.
+type Snapshot struct {
+	Profile *ProfileSnapshot
+	SDKs    map[string]*SdkSnapshot
+}
+
+type ProfileSnapshot struct {
+	CacheKey string          `json:"cacheKey"`
+	ETag     string          `json:"etag"`
+	Config   json.RawMessage `json:"config"`
+}
+
+type SdkSnapshot struct {
+	CacheKey  string          `json:"cacheKey"`
+	ETag      string          `json:"etag"`
+	FetchTime time.Time       `json:"fetchTime"`
+	Config    json.RawMessage `json:"config"`
+}
+
+// TakeSnapshot waits for each SDK client to initialize, then collects their config JSONs.
+func TakeSnapshot(ctx context.Context, registrar Registrar) (*Snapshot, error) {
+	snapshot := &Snapshot{SDKs: make(map[string]*SdkSnapshot)}
+	if autoReg, ok := registrar.(AutoRegistrar); ok {
+		snapshot.Profile = autoReg.ProfileSnapshot()
+		if snapshot.Profile == nil {
+			return nil, fmt.Errorf("no proxy profile available to export")
+		}
+	}
+	for sdkId, sdkClient := range registrar.GetAll() {
+		select {
+		case <-sdkClient.Ready():
+		case <-ctx.Done():
+			return nil, fmt.Errorf("SDK '%s' did not initialize in time: %s", sdkId, ctx.Err())
+		}
+		entry := sdkClient.GetCachedJson()
+		if entry.Empty || !json.Valid(entry.ConfigJson) {
+			return nil, fmt.Errorf("SDK '%s' has no valid config JSON to export", sdkId)
+		}
+		key, _ := sdkClient.SdkKeys()
+		snapshot.SDKs[sdkId] = &SdkSnapshot{
+			CacheKey:  configcatcache.ProduceCacheKey(key, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion),
+			ETag:      entry.ETag,
+			FetchTime: entry.FetchTime,
+			Config:    entry.ConfigJson,
+		}
+	}
+	return snapshot, nil
+}
+
+// WriteToCache stores the snapshot's entries in the same format the SDK clients and the proxy profile poller read them.
+func (s *Snapshot) WriteToCache(ctx context.Context, externalCache cache.ReaderWriter) error {
+	if s.Profile != nil {
+		if err := externalCache.Set(ctx, s.Profile.CacheKey, cacheSegmentsToBytes(s.Profile.ETag, s.Profile.Config)); err != nil {
+			return fmt.Errorf("failed to write proxy profile to cache: %s", err)
+		}
+	}
+	for sdkId, sdkSnapshot := range s.SDKs {
+		if err := externalCache.Set(ctx, sdkSnapshot.CacheKey, configcatcache.CacheSegmentsToBytes(sdkSnapshot.FetchTime, sdkSnapshot.ETag, sdkSnapshot.Config)); err != nil {
+			return fmt.Errorf("failed to write config JSON of SDK '%s' to cache: %s", sdkId, err)
+		}
+	}
+	return nil
+}
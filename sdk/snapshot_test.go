@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeSnapshot(t *testing.T) {
+	reg, _, key := NewTestRegistrarT(t)
+
+	snapshot, err := TakeSnapshot(context.Background(), reg)
+	require.NoError(t, err)
+	assert.Nil(t, snapshot.Profile)
+	assert.Len(t, snapshot.SDKs, 1)
+
+	sdkSnapshot := snapshot.SDKs["test"]
+	assert.Equal(t, configcatcache.ProduceCacheKey(key, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion), sdkSnapshot.CacheKey)
+	assert.NotEmpty(t, sdkSnapshot.ETag)
+	assert.False(t, sdkSnapshot.FetchTime.IsZero())
+	assert.Contains(t, string(sdkSnapshot.Config), `"flag"`)
+}
+
+func TestTakeSnapshot_Invalid(t *testing.T) {
+	reg := NewTestRegistrarTWithErrorServer(t)
+
+	_, err := TakeSnapshot(context.Background(), reg)
+	assert.ErrorContains(t, err, "SDK 'test' has no valid config JSON to export")
+}
+
+func TestSnapshot_WriteToCache(t *testing.T) {
+	reg, _, _ := NewTestAutoRegistrar(t, config.Config{Profile: config.ProfileConfig{Key: "test-reg", PollInterval: 60}}, nil, log.NewNullLogger())
+
+	snapshot, err := TakeSnapshot(context.Background(), reg)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot.Profile)
+	assert.Equal(t, "configcat-proxy-profile-test-reg", snapshot.Profile.CacheKey)
+	assert.Contains(t, string(snapshot.Profile.Config), `"test"`)
+
+	s := miniredis.RunT(t)
+	extCache := newRedisCache(s.Addr())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, snapshot.WriteToCache(ctx, extCache))
+
+	offlineReg := NewTestAutoRegistrarWithCache(t, 60, extCache, log.NewNullLogger())
+	sdkClient := offlineReg.GetSdkOrNil("test")
+	require.NotNil(t, sdkClient)
+	res := sdkClient.Eval("flag", nil)
+	assert.True(t, res.Value.(bool))
+	assert.Equal(t, snapshot.SDKs["test"].ETag, sdkClient.GetCachedJson().ETag)
+}
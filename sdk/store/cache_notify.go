@@ -22,12 +22,21 @@ type notifyingCacheStore struct {
 	telemetryReporter telemetry.Reporter
 	sdkId             string
 	cacheKey          string
+	watcher           Watcher
 }
 
 func NewNotifyingCacheStore(sdkId string, cacheKey string, cache CacheEntryStore, conf *config.OfflineConfig,
+	telemetryReporter telemetry.Reporter, statusReporter status.Reporter, log log.Logger) NotifyingStore {
+	return NewWatchingCacheStore(sdkId, cacheKey, cache, nil, conf, telemetryReporter, statusReporter, log)
+}
+
+// NewWatchingCacheStore works like NewNotifyingCacheStore, but it also reloads the cache entry
+// when the given watcher signals a change, without waiting for the next poll.
+func NewWatchingCacheStore(sdkId string, cacheKey string, cache CacheEntryStore, watcher Watcher, conf *config.OfflineConfig,
 	telemetryReporter telemetry.Reporter, statusReporter status.Reporter, log log.Logger) NotifyingStore {
 	nrLogger := log.WithPrefix("cache-poll")
 	n := &notifyingCacheStore{
+		watcher:           watcher,
 		CacheEntryStore:   cache,
 		Notifier:          NewNotifier(),
 		cacheKey:          cacheKey,
@@ -48,12 +57,24 @@ func (n *notifyingCacheStore) run(interval int) {
 	}
 	poller := time.NewTicker(time.Duration(inter) * time.Second)
 	defer poller.Stop()
+	var watched <-chan struct{}
+	if n.watcher != nil {
+		watched = n.watcher.Modified()
+	}
 	for {
 		select {
 		case <-poller.C:
 			if n.reload() {
 				n.Notify()
 			}
+		case _, ok := <-watched:
+			if !ok {
+				watched = nil
+				continue
+			}
+			if n.reload() {
+				n.Notify()
+			}
 		case <-n.Notifier.Context().Done():
 			return
 		}
@@ -103,5 +124,8 @@ func (n *notifyingCacheStore) Set(_ context.Context, _ string, _ []byte) error {
 
 func (n *notifyingCacheStore) Close() {
 	n.Notifier.Close()
+	if n.watcher != nil {
+		n.watcher.Close()
+	}
 	n.log.Reportf("shutdown complete")
 }
@@ -22,7 +22,7 @@ func TestRedisNotify(t *testing.T) {
 	cacheKey := configcatcache.ProduceCacheKey(sdkKey, configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion)
 	s := miniredis.RunT(t)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger()).(*notifyingCacheStore)
@@ -50,7 +50,7 @@ func TestRedisNotify_Initial(t *testing.T) {
 	err := s.Set(cacheKey, string(cacheEntry))
 	assert.NoError(t, err)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
@@ -70,7 +70,7 @@ func TestRedisNotify_Notify(t *testing.T) {
 	err := s.Set(cacheKey, string(cacheEntry))
 	assert.NoError(t, err)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger()).(*notifyingCacheStore)
@@ -100,7 +100,7 @@ func TestRedisNotify_BadJson(t *testing.T) {
 	err := s.Set(cacheKey, `{"f":{"flag":{"v":{"b":false}}},"p":null}`)
 	assert.NoError(t, err)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
@@ -118,7 +118,7 @@ func TestRedisNotify_MalformedCacheEntry(t *testing.T) {
 	err := s.Set(cacheKey, `{"k":{"flag`)
 	assert.NoError(t, err)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
@@ -137,7 +137,7 @@ func TestRedisNotify_MalformedJson(t *testing.T) {
 	err := s.Set(cacheKey, string(cacheEntry))
 	assert.NoError(t, err)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
@@ -157,7 +157,7 @@ func TestRedisNotify_Reporter(t *testing.T) {
 	assert.NoError(t, err)
 	reporter := &testReporter{}
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, reporter)
 	srv := NewNotifyingCacheStore(sdkKey, cacheKey, r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), reporter, log.NewNullLogger()).(*notifyingCacheStore)
@@ -184,7 +184,7 @@ func TestRedisNotify_Reporter(t *testing.T) {
 
 func TestRedisNotify_Unavailable(t *testing.T) {
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{"nonexisting"}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", "", r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
@@ -198,7 +198,7 @@ func TestRedisNotify_Unavailable(t *testing.T) {
 func TestRedisNotify_Close(t *testing.T) {
 	s := miniredis.RunT(t)
 	conf := config.CacheConfig{Redis: config.RedisConfig{Enabled: true, Addresses: []string{s.Addr()}}}
-	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), log.NewNullLogger())
+	red, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
 	assert.NoError(t, err)
 	r := NewCacheStore(red, status.NewEmptyReporter())
 	srv := NewNotifyingCacheStore("test", "", r, &config.OfflineConfig{CachePollInterval: 1}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger()).(*notifyingCacheStore)
@@ -213,6 +213,32 @@ func TestRedisNotify_Close(t *testing.T) {
 	})
 }
 
+func TestWatchingNotify(t *testing.T) {
+	cacheKey := configcatcache.ProduceCacheKey("key", configcatcache.ConfigJSONName, configcatcache.ConfigJSONCacheVersion)
+	conf := config.CacheConfig{Filesystem: config.FilesystemConfig{Enabled: true, Path: t.TempDir()}}
+	fsCache, err := cache.SetupExternalCache(&conf, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger())
+	assert.NoError(t, err)
+	watcher := NewNotifier()
+	r := NewCacheStore(fsCache, status.NewEmptyReporter())
+	srv := NewWatchingCacheStore("test", cacheKey, r, watcher, &config.OfflineConfig{CachePollInterval: 60}, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), log.NewNullLogger()).(*notifyingCacheStore)
+
+	cacheEntry := configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`{"f":{"flag":{"v":{"b":true}}},"p":null}`))
+	err = fsCache.Set(t.Context(), cacheKey, cacheEntry)
+	assert.NoError(t, err)
+	watcher.Notify()
+	testutils.WithTimeout(2*time.Second, func() {
+		<-srv.Modified()
+	})
+	assert.Equal(t, "etag", srv.LoadEntry().ETag)
+
+	srv.Close()
+	select {
+	case <-watcher.Context().Done():
+	default:
+		t.Error("watcher was not closed")
+	}
+}
+
 type testReporter struct {
 	records []string
 
@@ -245,6 +271,14 @@ func (r *testReporter) ReportError(component string, message string) {
 	r.records = append(r.records, component+"[error] "+message)
 }
 
+func (r *testReporter) ReportOverrides(_ string, _ map[string]time.Time) {
+	// do nothing
+}
+
+func (r *testReporter) ReportConfig(_ string, _ string, _ time.Time, _ int) {
+	// do nothing
+}
+
 func (r *testReporter) Records() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -252,7 +286,7 @@ func (r *testReporter) Records() []string {
 	return r.records
 }
 
-func (r *testReporter) HttpHandler() http.HandlerFunc {
+func (r *testReporter) HttpHandler(_ *config.StatusConfig) http.HandlerFunc {
 	return nil
 }
 
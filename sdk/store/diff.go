@@ -0,0 +1,180 @@
+package store
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/configcat/configcat-proxy/model"
+	configcat "github.com/configcat/go-sdk/v9"
+)
+
+// DiffConfigs compares two config JSONs at setting, targeting rule and segment level.
+// Settings that are unchanged but depend on a changed setting through a prerequisite flag condition are also reported.
+func DiffConfigs(from []byte, to []byte) ([]model.FlagDiff, []model.SegmentDiff, error) {
+	var fromConf, toConf configcat.ConfigJson
+	if err := json.Unmarshal(from, &fromConf); err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(to, &toConf); err != nil {
+		return nil, nil, err
+	}
+	return diffSettings(fromConf.Settings, toConf.Settings), diffSegments(fromConf.Segments, toConf.Segments), nil
+}
+
+func diffSettings(from map[string]*configcat.Setting, to map[string]*configcat.Setting) []model.FlagDiff {
+	keys := slices.Collect(maps.Keys(from))
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	diffs := make(map[string]*model.FlagDiff)
+	for _, key := range keys {
+		fromSetting, fromOk := from[key]
+		toSetting, toOk := to[key]
+		switch {
+		case !fromOk:
+			diffs[key] = &model.FlagDiff{Key: key, Change: model.DiffAdded}
+		case !toOk:
+			diffs[key] = &model.FlagDiff{Key: key, Change: model.DiffRemoved}
+		default:
+			if diff := diffSetting(key, fromSetting, toSetting); diff != nil {
+				diffs[key] = diff
+			}
+		}
+	}
+	markPrerequisiteChanges(to, diffs)
+
+	result := make([]model.FlagDiff, 0, len(diffs))
+	for _, key := range keys {
+		if diff, ok := diffs[key]; ok {
+			result = append(result, *diff)
+		}
+	}
+	return result
+}
+
+func diffSetting(key string, from *configcat.Setting, to *configcat.Setting) *model.FlagDiff {
+	if from == nil || to == nil {
+		return nil
+	}
+	diff := model.FlagDiff{Key: key, Change: model.DiffModified}
+	if fromValue, toValue := settingValue(from.Value), settingValue(to.Value); fromValue != toValue {
+		diff.DefaultValue = &model.ValueDiff{From: fromValue, To: toValue}
+	}
+	for i := 0; i < max(len(from.TargetingRules), len(to.TargetingRules)); i++ {
+		switch {
+		case i >= len(from.TargetingRules):
+			diff.TargetingRules = append(diff.TargetingRules, model.RuleDiff{Index: i, Change: model.DiffAdded, To: marshalRule(to.TargetingRules[i])})
+		case i >= len(to.TargetingRules):
+			diff.TargetingRules = append(diff.TargetingRules, model.RuleDiff{Index: i, Change: model.DiffRemoved, From: marshalRule(from.TargetingRules[i])})
+		case !reflect.DeepEqual(from.TargetingRules[i], to.TargetingRules[i]):
+			diff.TargetingRules = append(diff.TargetingRules, model.RuleDiff{Index: i, Change: model.DiffModified,
+				From: marshalRule(from.TargetingRules[i]), To: marshalRule(to.TargetingRules[i])})
+		}
+	}
+	diff.PercentageOptionsChanged = from.PercentageOptionsAttribute != to.PercentageOptionsAttribute ||
+		!reflect.DeepEqual(from.PercentageOptions, to.PercentageOptions)
+	if diff.DefaultValue == nil && len(diff.TargetingRules) == 0 && !diff.PercentageOptionsChanged {
+		return nil
+	}
+	return &diff
+}
+
+// markPrerequisiteChanges walks the prerequisite flag conditions until every setting affected by a change is marked.
+func markPrerequisiteChanges(settings map[string]*configcat.Setting, diffs map[string]*model.FlagDiff) {
+	for changed := true; changed; {
+		changed = false
+		for key, setting := range settings {
+			for _, prerequisite := range prerequisiteKeys(setting) {
+				if _, ok := diffs[prerequisite]; !ok {
+					continue
+				}
+				diff, ok := diffs[key]
+				if !ok {
+					diff = &model.FlagDiff{Key: key, Change: model.DiffPrerequisiteChanged}
+					diffs[key] = diff
+				}
+				if !slices.Contains(diff.ChangedPrerequisites, prerequisite) {
+					diff.ChangedPrerequisites = append(diff.ChangedPrerequisites, prerequisite)
+					slices.Sort(diff.ChangedPrerequisites)
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+func prerequisiteKeys(setting *configcat.Setting) []string {
+	var keys []string
+	if setting == nil {
+		return keys
+	}
+	for _, rule := range setting.TargetingRules {
+		if rule == nil {
+			continue
+		}
+		for _, condition := range rule.Conditions {
+			if condition != nil && condition.PrerequisiteFlagCondition != nil {
+				keys = append(keys, condition.PrerequisiteFlagCondition.FlagKey)
+			}
+		}
+	}
+	return keys
+}
+
+func diffSegments(from []*configcat.Segment, to []*configcat.Segment) []model.SegmentDiff {
+	fromByName, toByName := segmentsByName(from), segmentsByName(to)
+	result := make([]model.SegmentDiff, 0)
+	for _, segment := range from {
+		if segment == nil {
+			continue
+		}
+		toSegment, ok := toByName[segment.Name]
+		switch {
+		case !ok:
+			result = append(result, model.SegmentDiff{Name: segment.Name, Change: model.DiffRemoved})
+		case !reflect.DeepEqual(segment.Conditions, toSegment.Conditions):
+			result = append(result, model.SegmentDiff{Name: segment.Name, Change: model.DiffModified})
+		}
+	}
+	for _, segment := range to {
+		if segment == nil {
+			continue
+		}
+		if _, ok := fromByName[segment.Name]; !ok {
+			result = append(result, model.SegmentDiff{Name: segment.Name, Change: model.DiffAdded})
+		}
+	}
+	slices.SortFunc(result, func(a, b model.SegmentDiff) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+func segmentsByName(segments []*configcat.Segment) map[string]*configcat.Segment {
+	result := make(map[string]*configcat.Segment, len(segments))
+	for _, segment := range segments {
+		if segment != nil {
+			result[segment.Name] = segment
+		}
+	}
+	return result
+}
+
+func settingValue(value *configcat.SettingValue) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.Value
+}
+
+func marshalRule(rule *configcat.TargetingRule) json.RawMessage {
+	data, _ := json.Marshal(rule)
+	return data
+}
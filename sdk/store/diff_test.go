@@ -0,0 +1,80 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	t.Run("settings", func(t *testing.T) {
+		from := `{"f":{
+			"unchanged":{"t":0,"v":{"b":true}},
+			"removed":{"t":0,"v":{"b":true}},
+			"value":{"t":1,"v":{"s":"a"}},
+			"rules":{"t":0,"v":{"b":false},"r":[{"c":[{"u":{"a":"Email","c":2,"l":["@example.com"]}}],"s":{"v":{"b":true}}}]},
+			"percentage":{"t":0,"v":{"b":false},"p":[{"p":50,"v":{"b":true}},{"p":50,"v":{"b":false}}]}
+		}}`
+		to := `{"f":{
+			"unchanged":{"t":0,"v":{"b":true}},
+			"added":{"t":2,"v":{"i":5}},
+			"value":{"t":1,"v":{"s":"b"}},
+			"rules":{"t":0,"v":{"b":false},"r":[{"c":[{"u":{"a":"Email","c":2,"l":["@configcat.com"]}}],"s":{"v":{"b":true}}},{"c":[{"u":{"a":"Country","c":0,"l":["HU"]}}],"s":{"v":{"b":true}}}]},
+			"percentage":{"t":0,"v":{"b":false},"p":[{"p":20,"v":{"b":true}},{"p":80,"v":{"b":false}}]}
+		}}`
+		flags, segments, err := DiffConfigs([]byte(from), []byte(to))
+		require.NoError(t, err)
+		assert.Empty(t, segments)
+		require.Len(t, flags, 5)
+
+		assert.Equal(t, model.FlagDiff{Key: "added", Change: model.DiffAdded}, flags[0])
+		assert.Equal(t, "percentage", flags[1].Key)
+		assert.True(t, flags[1].PercentageOptionsChanged)
+		assert.Equal(t, model.FlagDiff{Key: "removed", Change: model.DiffRemoved}, flags[2])
+		assert.Equal(t, "rules", flags[3].Key)
+		require.Len(t, flags[3].TargetingRules, 2)
+		assert.Equal(t, model.DiffModified, flags[3].TargetingRules[0].Change)
+		assert.Contains(t, string(flags[3].TargetingRules[0].From), "@example.com")
+		assert.Contains(t, string(flags[3].TargetingRules[0].To), "@configcat.com")
+		assert.Equal(t, model.DiffAdded, flags[3].TargetingRules[1].Change)
+		assert.Nil(t, flags[3].DefaultValue)
+		assert.Equal(t, "value", flags[4].Key)
+		assert.Equal(t, &model.ValueDiff{From: "a", To: "b"}, flags[4].DefaultValue)
+	})
+	t.Run("segments", func(t *testing.T) {
+		from := `{"s":[{"n":"beta","r":[{"a":"Email","c":2,"l":["@example.com"]}]},{"n":"old","r":[]}],"f":{}}`
+		to := `{"s":[{"n":"beta","r":[{"a":"Email","c":2,"l":["@configcat.com"]}]},{"n":"new","r":[]}],"f":{}}`
+		flags, segments, err := DiffConfigs([]byte(from), []byte(to))
+		require.NoError(t, err)
+		assert.Empty(t, flags)
+		assert.Equal(t, []model.SegmentDiff{
+			{Name: "beta", Change: model.DiffModified},
+			{Name: "new", Change: model.DiffAdded},
+			{Name: "old", Change: model.DiffRemoved},
+		}, segments)
+	})
+	t.Run("prerequisites", func(t *testing.T) {
+		from := `{"f":{
+			"base":{"t":0,"v":{"b":false}},
+			"dependent":{"t":0,"v":{"b":false},"r":[{"c":[{"p":{"f":"base","c":0,"v":{"b":true}}}],"s":{"v":{"b":true}}}]},
+			"transitive":{"t":0,"v":{"b":false},"r":[{"c":[{"p":{"f":"dependent","c":0,"v":{"b":true}}}],"s":{"v":{"b":true}}}]}
+		}}`
+		to := `{"f":{
+			"base":{"t":0,"v":{"b":true}},
+			"dependent":{"t":0,"v":{"b":false},"r":[{"c":[{"p":{"f":"base","c":0,"v":{"b":true}}}],"s":{"v":{"b":true}}}]},
+			"transitive":{"t":0,"v":{"b":false},"r":[{"c":[{"p":{"f":"dependent","c":0,"v":{"b":true}}}],"s":{"v":{"b":true}}}]}
+		}}`
+		flags, _, err := DiffConfigs([]byte(from), []byte(to))
+		require.NoError(t, err)
+		require.Len(t, flags, 3)
+		assert.Equal(t, model.DiffModified, flags[0].Change)
+		assert.Equal(t, model.FlagDiff{Key: "dependent", Change: model.DiffPrerequisiteChanged, ChangedPrerequisites: []string{"base"}}, flags[1])
+		assert.Equal(t, model.FlagDiff{Key: "transitive", Change: model.DiffPrerequisiteChanged, ChangedPrerequisites: []string{"dependent"}}, flags[2])
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := DiffConfigs([]byte(`{}`), []byte(`invalid`))
+		assert.Error(t, err)
+	})
+}
@@ -2,18 +2,25 @@ package store
 
 import (
 	"encoding/json"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/configcat/configcat-proxy/internal/utils"
+	"github.com/configcat/configcat-proxy/model"
 	configcat "github.com/configcat/go-sdk/v9"
 	"github.com/configcat/go-sdk/v9/configcatcache"
 )
 
+const changeLogSize = 100
+
 type EntryStore interface {
 	LoadEntry() *EntryWithEtag
 	ComposeBytes() []byte
 	StoreEntry(data []byte, fetchTime time.Time, eTag string)
+	// Changes returns the rolling log of structural changes between the stored entries, oldest first.
+	Changes() []model.ConfigChange
 }
 
 type EntryWithEtag struct {
@@ -24,8 +31,11 @@ type EntryWithEtag struct {
 }
 
 type entryStore struct {
-	entry    atomic.Pointer[EntryWithEtag]
-	modified chan struct{}
+	entry     atomic.Pointer[EntryWithEtag]
+	modified  chan struct{}
+	changes   []model.ConfigChange
+	changeId  int
+	changesMu sync.Mutex
 }
 
 func NewEntryStore() EntryStore {
@@ -48,7 +58,40 @@ func (e *entryStore) ComposeBytes() []byte {
 }
 
 func (e *entryStore) StoreEntry(configJson []byte, fetchTime time.Time, eTag string) {
-	e.entry.Store(entryWithEtag(configJson, fetchTime, eTag, false))
+	previous := e.entry.Swap(entryWithEtag(configJson, fetchTime, eTag, false))
+	if previous.Empty || previous.ETag == eTag {
+		return
+	}
+	e.recordChange(previous, configJson, eTag)
+}
+
+func (e *entryStore) Changes() []model.ConfigChange {
+	e.changesMu.Lock()
+	defer e.changesMu.Unlock()
+
+	return slices.Clone(e.changes)
+}
+
+func (e *entryStore) recordChange(previous *EntryWithEtag, configJson []byte, eTag string) {
+	flags, segments, err := DiffConfigs(previous.ConfigJson, configJson)
+	if err != nil || (len(flags) == 0 && len(segments) == 0) {
+		return
+	}
+	e.changesMu.Lock()
+	defer e.changesMu.Unlock()
+
+	e.changeId++
+	e.changes = append(e.changes, model.ConfigChange{
+		Id:           e.changeId,
+		ETag:         eTag,
+		PreviousETag: previous.ETag,
+		DetectedAt:   time.Now().UTC(),
+		Flags:        flags,
+		Segments:     segments,
+	})
+	if len(e.changes) > changeLogSize {
+		e.changes = slices.Clone(e.changes[len(e.changes)-changeLogSize:])
+	}
 }
 
 func entryWithEtag(configJson []byte, fetchTime time.Time, eTag string, empty bool) *EntryWithEtag {
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -22,4 +24,31 @@ func TestEntryStore(t *testing.T) {
 		assert.Equal(t, "-62135596800000\netag\ntest", string(e.ComposeBytes()))
 		assert.False(t, e.LoadEntry().Empty)
 	})
+	t.Run("changes", func(t *testing.T) {
+		e := NewEntryStore()
+		e.StoreEntry([]byte(`{"f":{"flag":{"t":0,"v":{"b":true}}}}`), time.Time{}, "etag1")
+		assert.Empty(t, e.Changes())
+		e.StoreEntry([]byte(`{"f":{"flag":{"t":0,"v":{"b":true}}}}`), time.Time{}, "etag1")
+		assert.Empty(t, e.Changes())
+		e.StoreEntry([]byte(`{"f":{"flag":{"t":0,"v":{"b":false}}}}`), time.Time{}, "etag2")
+		e.StoreEntry([]byte(`{"f":{"flag":{"t":0,"v":{"b":false}}},"p":{"s":"salt"}}`), time.Time{}, "etag3")
+
+		changes := e.Changes()
+		assert.Len(t, changes, 1)
+		assert.Equal(t, 1, changes[0].Id)
+		assert.Equal(t, "etag2", changes[0].ETag)
+		assert.Equal(t, "etag1", changes[0].PreviousETag)
+		assert.Equal(t, "flag", changes[0].Flags[0].Key)
+		assert.False(t, changes[0].DetectedAt.IsZero())
+	})
+	t.Run("changes bounded", func(t *testing.T) {
+		e := NewEntryStore()
+		for i := 0; i <= changeLogSize+1; i++ {
+			e.StoreEntry([]byte(fmt.Sprintf(`{"f":{"flag":{"t":1,"v":{"s":"%d"}}}}`, i)), time.Time{}, strconv.Itoa(i))
+		}
+		changes := e.Changes()
+		assert.Len(t, changes, changeLogSize)
+		assert.Equal(t, 2, changes[0].Id)
+		assert.Equal(t, changeLogSize+1, changes[len(changes)-1].Id)
+	})
 }
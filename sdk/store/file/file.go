@@ -15,11 +15,6 @@ import (
 	configcat "github.com/configcat/go-sdk/v9"
 )
 
-type watcher interface {
-	Modified() <-chan struct{}
-	Close()
-}
-
 type nullWatcher struct {
 	modified chan struct{}
 }
@@ -28,7 +23,7 @@ type fileStore struct {
 	store.EntryStore
 	store.Notifier
 
-	watcher  watcher
+	watcher  store.Watcher
 	log      log.Logger
 	conf     *config.LocalConfig
 	stored   []byte
@@ -39,23 +34,10 @@ type fileStore struct {
 
 func NewFileStore(sdkId string, conf *config.LocalConfig, reporter status.Reporter, log log.Logger) store.NotifyingStore {
 	fileLogger := log.WithPrefix("file-store")
-	var watch watcher
-	var err error
-	if conf.Polling {
-		watch, err = newPollWatcher(conf, fileLogger)
-	} else {
-		watch, err = newFileWatcher(conf, fileLogger)
-		if err != nil {
-			watch, err = newPollWatcher(conf, fileLogger)
-		}
-	}
-	if err != nil {
-		watch = &nullWatcher{modified: make(chan struct{})}
-	}
 	f := &fileStore{
 		EntryStore: store.NewEntryStore(),
 		Notifier:   store.NewNotifier(),
-		watcher:    watch,
+		watcher:    NewWatcher(conf, fileLogger),
 		log:        fileLogger,
 		conf:       conf,
 		reporter:   reporter,
@@ -67,6 +49,25 @@ func NewFileStore(sdkId string, conf *config.LocalConfig, reporter status.Report
 	return f
 }
 
+// NewWatcher watches the file at conf.FilePath with file system notifications, or with polling when
+// conf.Polling is set or notifications are not available. When neither works, the returned watcher never signals.
+func NewWatcher(conf *config.LocalConfig, log log.Logger) store.Watcher {
+	var watch store.Watcher
+	var err error
+	if conf.Polling {
+		watch, err = newPollWatcher(conf, log)
+	} else {
+		watch, err = newFileWatcher(conf, log)
+		if err != nil {
+			watch, err = newPollWatcher(conf, log)
+		}
+	}
+	if err != nil {
+		watch = &nullWatcher{modified: make(chan struct{})}
+	}
+	return watch
+}
+
 func (f *fileStore) run() {
 	for {
 		select {
@@ -56,7 +56,8 @@ func (f *fileWatcher) run() {
 	for {
 		select {
 		case event := <-f.watch.Events:
-			if event.Name == f.realFilePath && event.Has(fsnotify.Write) {
+			// files replaced atomically (written elsewhere, then renamed) show up as Create
+			if event.Name == f.realFilePath && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
 				f.Notify()
 			}
 		case err := <-f.watch.Errors:
@@ -1,6 +1,8 @@
 package file
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -21,6 +23,22 @@ func TestFileWatcher_Existing(t *testing.T) {
 	})
 }
 
+func TestFileWatcher_Replaced(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.json")
+	_ = os.WriteFile(path, []byte(""), 0644)
+	watcher, _ := newFileWatcher(&config.LocalConfig{FilePath: path}, log.NewNullLogger())
+	defer watcher.Close()
+
+	tmpPath := filepath.Join(dir, "test.tmp")
+	_ = os.WriteFile(tmpPath, []byte("test"), 0644)
+	_ = os.Rename(tmpPath, path)
+	testutils.WithTimeout(2*time.Second, func() {
+		<-watcher.Modified()
+	})
+	assert.Equal(t, "test", testutils.ReadFile(path))
+}
+
 func TestFileWatcher_Stop(t *testing.T) {
 	testutils.UseTempFile("", func(path string) {
 		watcher, _ := newFileWatcher(&config.LocalConfig{FilePath: path}, log.NewNullLogger())
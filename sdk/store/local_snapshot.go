@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+
+	"github.com/configcat/configcat-proxy/log"
+	configcat "github.com/configcat/go-sdk/v9"
+)
+
+// localSnapshotStore mirrors every successfully fetched config to a local disk snapshot and
+// falls back to that snapshot when the wrapped store has no usable entry.
+type localSnapshotStore struct {
+	CacheEntryStore
+
+	snapshot configcat.ConfigCache
+	log      log.Logger
+}
+
+func NewLocalSnapshotStore(actual CacheEntryStore, snapshot configcat.ConfigCache, log log.Logger) CacheEntryStore {
+	return &localSnapshotStore{
+		CacheEntryStore: actual,
+		snapshot:        snapshot,
+		log:             log,
+	}
+}
+
+func (l *localSnapshotStore) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := l.CacheEntryStore.Get(ctx, key)
+	if err == nil && len(b) > 0 {
+		return b, nil
+	}
+	snapshot, snapshotErr := l.snapshot.Get(ctx, key)
+	if snapshotErr != nil || len(snapshot) == 0 {
+		return b, err
+	}
+	l.log.Debugf("serving config from the local snapshot")
+	return snapshot, nil
+}
+
+func (l *localSnapshotStore) Set(ctx context.Context, key string, value []byte) error {
+	err := l.CacheEntryStore.Set(ctx, key, value)
+	if snapshotErr := l.snapshot.Set(ctx, key, value); snapshotErr != nil {
+		l.log.Errorf("failed to write the local snapshot: %s", snapshotErr)
+	}
+	return err
+}
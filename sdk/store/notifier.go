@@ -9,6 +9,11 @@ type Notifier interface {
 	Context() context.Context
 }
 
+type Watcher interface {
+	Modified() <-chan struct{}
+	Close()
+}
+
 type notifier struct {
 	ctx       context.Context
 	ctxCancel func()
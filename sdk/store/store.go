@@ -2,8 +2,10 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/configcat/configcat-proxy/cache"
 	"github.com/configcat/configcat-proxy/diag/status"
 	configcat "github.com/configcat/go-sdk/v9"
 	"github.com/configcat/go-sdk/v9/configcatcache"
@@ -36,6 +38,14 @@ func NewCacheStore(actualCache configcat.ConfigCache, reporter status.Reporter)
 
 func (c *cacheStore) Get(ctx context.Context, key string) ([]byte, error) {
 	b, err := c.actualCache.Get(ctx, key)
+	if errors.Is(err, cache.ErrCircuitOpen) {
+		c.reporter.ReportError(status.Cache, "cache read skipped, circuit breaker is open")
+		// serve the last known entry while the cache is unhealthy
+		if !c.LoadEntry().Empty {
+			return c.ComposeBytes(), nil
+		}
+		return nil, err
+	}
 	if err != nil {
 		c.reporter.ReportError(status.Cache, "cache read failed")
 	} else {
@@ -5,7 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/configcat/configcat-proxy/cache"
+	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/log"
 	"github.com/configcat/go-sdk/v9/configcatcache"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,6 +26,28 @@ func TestCacheStore(t *testing.T) {
 	assert.Equal(t, `test`, string(store.LoadEntry().ConfigJson))
 }
 
+func TestCacheStore_CircuitOpen(t *testing.T) {
+	reporter := status.NewReporter(&config.CacheConfig{Redis: config.RedisConfig{Enabled: true}})
+	store := NewCacheStore(&openCircuitCache{}, reporter).(*cacheStore)
+
+	_, err := store.Get(t.Context(), "key")
+	assert.ErrorIs(t, err, cache.ErrCircuitOpen)
+
+	err = store.Set(t.Context(), "key", configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test`)))
+	assert.ErrorIs(t, err, cache.ErrCircuitOpen)
+
+	res, err := store.Get(t.Context(), "key")
+	assert.NoError(t, err)
+	_, etag, j, err := configcatcache.CacheSegmentsFromBytes(res)
+	assert.NoError(t, err)
+	assert.Equal(t, `test`, string(j))
+	assert.Equal(t, "etag", etag)
+
+	stat := reporter.GetStatus()
+	assert.Equal(t, status.Degraded, stat.Cache.Status)
+	assert.Contains(t, stat.Cache.Records[len(stat.Cache.Records)-1], "cache read skipped, circuit breaker is open")
+}
+
 func TestInMemoryStore(t *testing.T) {
 	t.Run("load default", func(t *testing.T) {
 		e := NewInMemoryStorage().(*inMemoryStore)
@@ -43,6 +68,30 @@ func TestInMemoryStore(t *testing.T) {
 	})
 }
 
+func TestLocalSnapshotStore(t *testing.T) {
+	snapshot := &testCache{}
+	s := NewLocalSnapshotStore(NewInMemoryStorage().(CacheEntryStore), snapshot, log.NewNullLogger())
+
+	_, err := s.Get(t.Context(), "")
+	assert.Error(t, err)
+
+	err = s.Set(t.Context(), "", configcatcache.CacheSegmentsToBytes(time.Now(), "etag", []byte(`test`)))
+	assert.NoError(t, err)
+	_, etag, j, err := configcatcache.CacheSegmentsFromBytes(snapshot.v)
+	assert.NoError(t, err)
+	assert.Equal(t, "etag", etag)
+	assert.Equal(t, `test`, string(j))
+
+	// a fresh store falls back to the snapshot left on disk
+	s = NewLocalSnapshotStore(NewInMemoryStorage().(CacheEntryStore), snapshot, log.NewNullLogger())
+	res, err := s.Get(t.Context(), "")
+	assert.NoError(t, err)
+	_, etag, _, err = configcatcache.CacheSegmentsFromBytes(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "etag", etag)
+	assert.True(t, s.LoadEntry().Empty)
+}
+
 type testCache struct {
 	v []byte
 }
@@ -57,3 +106,13 @@ func (r *testCache) Set(_ context.Context, _ string, val []byte) error {
 }
 
 func (r *testCache) Close() {}
+
+type openCircuitCache struct{}
+
+func (r *openCircuitCache) Get(_ context.Context, _ string) ([]byte, error) {
+	return nil, cache.ErrCircuitOpen
+}
+
+func (r *openCircuitCache) Set(_ context.Context, _ string, _ []byte) error {
+	return cache.ErrCircuitOpen
+}
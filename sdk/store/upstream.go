@@ -0,0 +1,200 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	configcat "github.com/configcat/go-sdk/v9"
+)
+
+const upstreamMinReconnectDelay = time.Second
+
+var (
+	errUpstreamClosed = errors.New("stream closed by upstream")
+	errUpstreamIdle   = errors.New("no data or keep-alive received from upstream")
+)
+
+type upstreamStore struct {
+	EntryStore
+	Notifier
+
+	log               log.Logger
+	statusReporter    status.Reporter
+	client            *http.Client
+	sdkId             string
+	url               string
+	headers           map[string]string
+	maxReconnectDelay time.Duration
+	readTimeout       time.Duration
+}
+
+// NewUpstreamStore follows the raw config JSON stream of an upstream proxy and reconnects
+// with an exponential backoff (capped at conf.MaxReconnectDelay) when the stream breaks, or when
+// nothing (not even a keep-alive) arrives within conf.ReadTimeout.
+func NewUpstreamStore(sdkId string, conf *config.UpstreamConfig, transport http.RoundTripper, statusReporter status.Reporter, log log.Logger) NotifyingStore {
+	upstreamLogger := log.WithPrefix("upstream")
+	upstreamSdkId := conf.SdkId
+	if upstreamSdkId == "" {
+		upstreamSdkId = sdkId
+	}
+	maxDelay := conf.MaxReconnectDelay
+	if maxDelay < 1 {
+		maxDelay = config.DefaultUpstreamMaxReconnectDelay
+	}
+	readTimeout := conf.ReadTimeout
+	if readTimeout < 1 {
+		readTimeout = config.DefaultUpstreamReadTimeout
+	}
+	u := &upstreamStore{
+		EntryStore:        NewEntryStore(),
+		Notifier:          NewNotifier(),
+		log:               upstreamLogger,
+		statusReporter:    statusReporter,
+		client:            &http.Client{Transport: transport},
+		sdkId:             sdkId,
+		url:               strings.TrimSuffix(conf.Url, "/") + "/sse/" + url.PathEscape(upstreamSdkId) + "/config",
+		headers:           conf.Headers,
+		maxReconnectDelay: time.Duration(maxDelay) * time.Second,
+		readTimeout:       time.Duration(readTimeout) * time.Second,
+	}
+	go u.run()
+	return u
+}
+
+func (u *upstreamStore) run() {
+	delay := upstreamMinReconnectDelay
+	for {
+		received, err := u.follow(u.Notifier.Context())
+		if u.Notifier.Context().Err() != nil {
+			return
+		}
+		if received {
+			delay = upstreamMinReconnectDelay
+		}
+		u.log.Errorf("upstream stream failed: %s; reconnecting in %s", err, delay)
+		u.statusReporter.ReportError(u.sdkId, "upstream stream failed")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-u.Notifier.Context().Done():
+			timer.Stop()
+			return
+		}
+		delay = min(delay*2, u.maxReconnectDelay)
+	}
+}
+
+// follow reads the stream until it breaks, and reports whether at least one event was received.
+func (u *upstreamStore) follow(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	idle := time.AfterFunc(u.readTimeout, func() {
+		cancel(errUpstreamIdle)
+	})
+	defer idle.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.url, http.NoBody)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for name, value := range u.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		if errors.Is(context.Cause(ctx), errUpstreamIdle) {
+			err = errUpstreamIdle
+		}
+		return false, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	u.log.Debugf("connected to upstream proxy (%s)", u.url)
+
+	received := false
+	reader := bufio.NewReader(resp.Body)
+	var data []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(context.Cause(ctx), errUpstreamIdle) {
+				err = errUpstreamIdle
+			} else if errors.Is(err, io.EOF) {
+				err = errUpstreamClosed
+			}
+			return received, err
+		}
+		idle.Reset(u.readTimeout)
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if len(data) > 0 {
+				u.reload(data)
+				received = true
+				data = nil
+			}
+			continue
+		}
+		if value, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			if len(data) > 0 {
+				data = append(data, '\n')
+			}
+			data = append(data, bytes.TrimPrefix(value, []byte(" "))...)
+		}
+	}
+}
+
+func (u *upstreamStore) reload(data []byte) {
+	var payload model.ConfigPayload
+	if err := json.Unmarshal(data, &payload); err != nil || len(payload.Config) == 0 || string(payload.Config) == "null" {
+		u.log.Errorf("failed to recognise the upstream payload: %v", err)
+		u.statusReporter.ReportError(u.sdkId, "failed to recognise the upstream payload")
+		return
+	}
+	if u.LoadEntry().ETag == payload.ETag {
+		u.statusReporter.ReportOk(u.sdkId, "config from upstream not modified")
+		return
+	}
+	u.log.Debugf("new JSON received from upstream, reloading")
+
+	var root configcat.ConfigJson
+	if err := json.Unmarshal(payload.Config, &root); err != nil {
+		u.log.Errorf("failed to parse JSON from upstream: %s", err)
+		u.statusReporter.ReportError(u.sdkId, "failed to parse JSON from upstream")
+		return
+	}
+	u.StoreEntry(payload.Config, time.UnixMilli(payload.FetchTime).UTC(), payload.ETag)
+	u.statusReporter.ReportOk(u.sdkId, "reload from upstream succeeded")
+	u.Notify()
+}
+
+func (u *upstreamStore) Get(_ context.Context, _ string) ([]byte, error) {
+	return u.ComposeBytes(), nil
+}
+
+func (u *upstreamStore) Set(_ context.Context, _ string, _ []byte) error {
+	return nil // do nothing
+}
+
+func (u *upstreamStore) Close() {
+	u.Notifier.Close()
+	u.log.Reportf("shutdown complete")
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/go-sdk/v9/configcatcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpstream(t *testing.T) {
+	var path, authHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authHeader = r.Header.Get("X-SSE-KEY")
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, `data: {"etag":"etag1","fetchTime":1700000000000,"config":{"f":{"flag":{"v":{"b":true}}},"p":null}}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	reporter := status.NewEmptyReporter()
+	reporter.RegisterSdk("test", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, Upstream: config.UpstreamConfig{Url: srv.URL}}})
+	str := NewUpstreamStore("test", &config.UpstreamConfig{Url: srv.URL + "/", SdkId: "central", Headers: map[string]string{"X-SSE-KEY": "secret"}}, http.DefaultTransport, reporter, log.NewNullLogger())
+	defer str.Close()
+
+	testutils.WithTimeout(2*time.Second, func() {
+		<-str.Modified()
+	})
+	assert.Equal(t, "/sse/central/config", path)
+	assert.Equal(t, "secret", authHeader)
+	entry := str.LoadEntry()
+	assert.Equal(t, `{"f":{"flag":{"v":{"b":true}}},"p":null}`, string(entry.ConfigJson))
+	assert.Equal(t, "etag1", entry.ETag)
+	assert.Equal(t, int64(1700000000000), entry.FetchTime.UnixMilli())
+
+	res, err := str.Get(t.Context(), "")
+	assert.NoError(t, err)
+	_, eTag, j, _ := configcatcache.CacheSegmentsFromBytes(res)
+	assert.Equal(t, "etag1", eTag)
+	assert.Equal(t, `{"f":{"flag":{"v":{"b":true}}},"p":null}`, string(j))
+	assert.NoError(t, str.Set(t.Context(), "", []byte{})) // set does nothing
+
+	stat := reporter.GetStatus()
+	assert.Equal(t, status.UpstreamSrc, stat.SDKs["test"].Source.Type)
+	assert.Equal(t, status.Healthy, stat.SDKs["test"].Source.Status)
+}
+
+func TestUpstream_Reconnect(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := attempts.Add(1)
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, `data: {"etag":"etag%d","fetchTime":1700000000000,"config":{"f":{"flag":{"v":{"b":true}}},"p":null}}`+"\n\n", attempt)
+		w.(http.Flusher).Flush()
+		if attempt == 2 {
+			return // drop the stream
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	reporter := status.NewEmptyReporter()
+	str := NewUpstreamStore("test", &config.UpstreamConfig{Url: srv.URL, MaxReconnectDelay: 1}, http.DefaultTransport, reporter, log.NewNullLogger())
+	defer str.Close()
+
+	testutils.WithTimeout(5*time.Second, func() {
+		<-str.Modified()
+		<-str.Modified()
+	})
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, "etag3", str.LoadEntry().ETag)
+}
+
+func TestUpstream_NotModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, `data: {"etag":"etag1","fetchTime":1700000000000,"config":{"f":{"flag":{"v":{"b":true}}},"p":null}}`+"\n\n")
+		_, _ = fmt.Fprint(w, `data: {"etag":"etag1","fetchTime":1700000000000,"config":{"f":{"flag":{"v":{"b":true}}},"p":null}}`+"\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	str := NewUpstreamStore("test", &config.UpstreamConfig{Url: srv.URL}, http.DefaultTransport, status.NewEmptyReporter(), log.NewNullLogger())
+	defer str.Close()
+
+	testutils.WithTimeout(2*time.Second, func() {
+		<-str.Modified()
+	})
+	select {
+	case <-str.Modified():
+		t.Fatal("unexpected notification")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestUpstream_InvalidPayload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"etag\":\"etag1\",\"config\":null}\n\n")
+		_, _ = fmt.Fprint(w, "data: invalid\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	reporter := status.NewEmptyReporter()
+	reporter.RegisterSdk("test", &config.SDKConfig{Offline: config.OfflineConfig{Enabled: true, Upstream: config.UpstreamConfig{Url: srv.URL}}})
+	str := NewUpstreamStore("test", &config.UpstreamConfig{Url: srv.URL}, http.DefaultTransport, reporter, log.NewNullLogger())
+	defer str.Close()
+
+	testutils.WaitUntil(2*time.Second, func() bool {
+		return len(reporter.GetStatus().SDKs["test"].Source.Records) == 2
+	})
+	assert.True(t, str.LoadEntry().Empty)
+	assert.Equal(t, status.Down, reporter.GetStatus().SDKs["test"].Source.Status)
+}
+
+func TestUpstream_ReadTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := attempts.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, `data: {"etag":"etag%d","fetchTime":1700000000000,"config":{"f":{"flag":{"v":{"b":true}}},"p":null}}`+"\n\n", attempt)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done() // stall without keep-alive
+	}))
+	defer srv.Close()
+
+	str := NewUpstreamStore("test", &config.UpstreamConfig{Url: srv.URL, MaxReconnectDelay: 1, ReadTimeout: 1}, http.DefaultTransport, status.NewEmptyReporter(), log.NewNullLogger())
+	defer str.Close()
+
+	testutils.WithTimeout(5*time.Second, func() {
+		<-str.Modified()
+		<-str.Modified()
+	})
+	assert.Equal(t, "etag2", str.LoadEntry().ETag)
+}
+
+func TestUpstream_KeepAlive(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, `data: {"etag":"etag1","fetchTime":1700000000000,"config":{"f":{"flag":{"v":{"b":true}}},"p":null}}`+"\n\n")
+		w.(http.Flusher).Flush()
+		ticker := time.NewTicker(300 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	str := NewUpstreamStore("test", &config.UpstreamConfig{Url: srv.URL, MaxReconnectDelay: 1, ReadTimeout: 1}, http.DefaultTransport, status.NewEmptyReporter(), log.NewNullLogger())
+	defer str.Close()
+
+	testutils.WithTimeout(2*time.Second, func() {
+		<-str.Modified()
+	})
+	time.Sleep(2 * time.Second)
+	assert.Equal(t, int32(1), attempts.Load())
+}
@@ -136,6 +136,7 @@ type TestSdkRegistrarHandler struct {
 	mu         sync.RWMutex
 	result     model.ProxyConfigModel
 	sdkHandler *configcattest.Handler
+	failing    bool
 }
 
 func (h *TestSdkRegistrarHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -145,6 +146,10 @@ func (h *TestSdkRegistrarHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 	}
 	h.mu.RLock()
 	defer h.mu.RUnlock()
+	if h.failing {
+		http.Error(w, "failing", http.StatusInternalServerError)
+		return
+	}
 	body, _ := json.Marshal(h.result)
 	etag := utils.GenerateEtag(body)
 	w.Header().Set("ETag", etag)
@@ -199,6 +204,13 @@ func (h *TestSdkRegistrarHandler) RemoveSdk(sdkId string) {
 	delete(h.result.SDKs, sdkId)
 }
 
+func (h *TestSdkRegistrarHandler) SetFailing(failing bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.failing = failing
+}
+
 func (h *TestSdkRegistrarHandler) ModifyGlobalOpts(optionsModel model.OptionsModel) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/configcat/configcat-proxy/sdk"
+)
+
+// A snapshot is stored as a directory (or a .tar.gz / .tgz archive of the same layout):
+//
+//	profile.json        - the proxy profile, only when the proxy runs with a profile
+//	sdks/<sdkId>.json   - the config JSON of each SDK
+const (
+	profileSnapshotFile = "profile.json"
+	sdkSnapshotDir      = "sdks"
+	snapshotFileExt     = ".json"
+)
+
+func isArchivePath(p string) bool {
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz")
+}
+
+func snapshotFiles(snapshot *sdk.Snapshot) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if snapshot.Profile != nil {
+		data, err := json.MarshalIndent(snapshot.Profile, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[profileSnapshotFile] = data
+	}
+	for sdkId, sdkSnapshot := range snapshot.SDKs {
+		if sdkId == "" || sdkId != filepath.Base(sdkId) || sdkId == "." || sdkId == ".." {
+			return nil, fmt.Errorf("SDK id '%s' can't be used as a file name", sdkId)
+		}
+		data, err := json.MarshalIndent(sdkSnapshot, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join(sdkSnapshotDir, sdkId+snapshotFileExt)] = data
+	}
+	return files, nil
+}
+
+func writeSnapshot(target string, snapshot *sdk.Snapshot) error {
+	files, err := snapshotFiles(snapshot)
+	if err != nil {
+		return err
+	}
+	if isArchivePath(target) {
+		return writeSnapshotArchive(target, files)
+	}
+	for name, data := range files {
+		filePath := filepath.Join(target, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(filePath, data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSnapshotArchive(target string, files map[string][]byte) (err error) {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		if _, err = tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func readSnapshot(source string) (*sdk.Snapshot, error) {
+	var files map[string][]byte
+	var err error
+	if isArchivePath(source) {
+		files, err = readSnapshotArchive(source)
+	} else {
+		files, err = readSnapshotDir(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &sdk.Snapshot{SDKs: make(map[string]*sdk.SdkSnapshot)}
+	for name, data := range files {
+		switch {
+		case name == profileSnapshotFile:
+			var profile sdk.ProfileSnapshot
+			if err = json.Unmarshal(data, &profile); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %s", name, err)
+			}
+			snapshot.Profile = &profile
+		case path.Dir(name) == sdkSnapshotDir && path.Ext(name) == snapshotFileExt:
+			var sdkSnapshot sdk.SdkSnapshot
+			if err = json.Unmarshal(data, &sdkSnapshot); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %s", name, err)
+			}
+			snapshot.SDKs[strings.TrimSuffix(path.Base(name), snapshotFileExt)] = &sdkSnapshot
+		}
+	}
+	if snapshot.Profile == nil && len(snapshot.SDKs) == 0 {
+		return nil, fmt.Errorf("no snapshot found at %s", source)
+	}
+	return snapshot, nil
+}
+
+func readSnapshotDir(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if data, err := os.ReadFile(filepath.Join(dir, profileSnapshotFile)); err == nil {
+		files[profileSnapshotFile] = data
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, sdkSnapshotDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, sdkSnapshotDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join(sdkSnapshotDir, entry.Name())] = data
+	}
+	return files, nil
+}
+
+func readSnapshotArchive(archive string) (map[string][]byte, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(header.Name)] = data
+	}
+	return files, nil
+}
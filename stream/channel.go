@@ -3,9 +3,11 @@ package stream
 import (
 	"github.com/configcat/configcat-proxy/model"
 	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/sdk/store"
 )
 
 const AllFlagsDiscriminator = "[ALL]"
+const ConfigJsonDiscriminator = "[CONFIG]"
 
 type channel interface {
 	Notify(sdkClient sdk.Client, key string) int
@@ -32,8 +34,16 @@ type allFlagsChannel struct {
 	connectionHolder
 }
 
+type configJsonChannel struct {
+	lastPayload *model.ConfigPayload
+
+	connectionHolder
+}
+
 func createChannel(established *connEstablished, sdkClient sdk.Client) channel {
-	if established.key == AllFlagsDiscriminator {
+	if established.key == ConfigJsonDiscriminator {
+		return &configJsonChannel{lastPayload: configPayload(sdkClient.GetCachedJson())}
+	} else if established.key == AllFlagsDiscriminator {
 		values := sdkClient.EvalAll(established.user)
 		payloads := make(map[string]*model.ResponsePayload)
 		for key, val := range values {
@@ -56,13 +66,17 @@ func (af *allFlagsChannel) LastPayload() interface{} {
 	return af.lastPayload
 }
 
+func (cj *configJsonChannel) LastPayload() interface{} {
+	return cj.lastPayload
+}
+
 func (sf *singleFlagChannel) Notify(sdkClient sdk.Client, key string) int {
 	sent := 0
 	val := sdkClient.Eval(key, sf.user)
 	if val.Error != nil {
 		return 0
 	}
-	if sf.lastPayload == nil || val.Value != sf.lastPayload.Value {
+	if sf.lastPayload == nil || val.Value != sf.lastPayload.Value || val.IsOverridden != sf.lastPayload.Overridden {
 		payload := model.PayloadFromEvalData(&val)
 		sf.lastPayload = &payload
 		for _, conn := range sf.connections {
@@ -94,6 +108,30 @@ func (af *allFlagsChannel) Notify(sdkClient sdk.Client, _ string) int {
 	return sent
 }
 
+func (cj *configJsonChannel) Notify(sdkClient sdk.Client, _ string) int {
+	sent := 0
+	entry := sdkClient.GetCachedJson()
+	if entry.Empty {
+		return 0
+	}
+	if cj.lastPayload == nil || entry.ETag != cj.lastPayload.ETag {
+		payload := configPayload(entry)
+		cj.lastPayload = payload
+		for _, conn := range cj.connections {
+			sent++
+			conn.receive <- payload
+		}
+	}
+	return sent
+}
+
+func configPayload(entry *store.EntryWithEtag) *model.ConfigPayload {
+	if entry.Empty {
+		return nil
+	}
+	return &model.ConfigPayload{ETag: entry.ETag, FetchTime: entry.FetchTime.UnixMilli(), Config: entry.ConfigJson}
+}
+
 func (c *connectionHolder) AddConnection(conn *Connection) {
 	c.connections = append(c.connections, conn)
 }
@@ -45,6 +45,7 @@ func TestStream_Connections(t *testing.T) {
 	assert.Equal(t, 2, len(str.channels["test"][user1Discriminator].(*singleFlagChannel).connections))
 	assert.Equal(t, 1, len(str.channels["test"][user2Discriminator].(*singleFlagChannel).connections))
 	assert.Equal(t, 2, len(str.channels["test"][0].(*singleFlagChannel).connections))
+	assert.Equal(t, int64(5), str.ConnectionCount())
 
 	assert.Equal(t, user1, str.channels["test"][user1Discriminator].(*singleFlagChannel).user)
 	assert.Equal(t, user2, str.channels["test"][user2Discriminator].(*singleFlagChannel).user)
@@ -60,12 +61,14 @@ func TestStream_Connections(t *testing.T) {
 	assert.Equal(t, 1, len(str.channels["test"][user1Discriminator].(*singleFlagChannel).connections))
 	assert.Nil(t, str.channels["test"][user2Discriminator])
 	assert.Equal(t, 1, len(str.channels["test"][0].(*singleFlagChannel).connections))
+	assert.Equal(t, int64(2), str.ConnectionCount())
 
 	str.CloseConnection(conn1, "test")
 	str.CloseConnection(conn5, "test")
 
 	time.Sleep(100 * time.Millisecond) // wait for goroutine finish removing connections
 	assert.Empty(t, str.channels)
+	assert.Equal(t, int64(0), str.ConnectionCount())
 }
 
 func TestStream_Close(t *testing.T) {
@@ -11,6 +11,7 @@ import (
 type Server interface {
 	GetStreamOrNil(sdkId string) Stream
 	GetStreamBySdkKeyOrNil(sdkKey string) Stream
+	ConnectionCounts() map[string]int64
 	Close()
 }
 
@@ -115,6 +116,15 @@ func (s *server) GetStreamBySdkKeyOrNil(sdkKey string) Stream {
 	return str
 }
 
+func (s *server) ConnectionCounts() map[string]int64 {
+	counts := make(map[string]int64, s.streams.Size())
+	s.streams.Range(func(key string, value Stream) bool {
+		counts[key] = value.ConnectionCount()
+		return true
+	})
+	return counts
+}
+
 func (s *server) Close() {
 	close(s.stop)
 	if autoRegistrar, ok := s.sdkRegistrar.(pubsub.SubscriptionHandler[string]); ok {
@@ -28,6 +28,15 @@ func TestServer_GetStreamOrNil(t *testing.T) {
 
 	assert.Equal(t, 1, srv.streams.Size())
 
+	conn := str.CreateConnection("flag", nil)
+	testutils.WaitUntil(time.Second, func() bool {
+		return srv.ConnectionCounts()["test"] == 1
+	})
+	str.CloseConnection(conn, "flag")
+	testutils.WaitUntil(time.Second, func() bool {
+		return srv.ConnectionCounts()["test"] == 0
+	})
+
 	srv.Close()
 	assert.Equal(t, 0, srv.streams.Size())
 }
@@ -17,6 +17,7 @@ type Stream interface {
 	CloseConnection(conn *Connection, key string)
 	ResetSdk(client sdk.Client)
 	SdkKeys() (string, *string)
+	ConnectionCount() int64
 	Close()
 	Closed() <-chan struct{}
 }
@@ -43,7 +44,7 @@ type stream struct {
 	channels          map[string]map[uint64]channel
 	connEstablished   chan *connEstablished
 	connClosed        chan *connClosed
-	connCount         int64
+	connCount         atomic.Int64
 	seed              maphash.Seed
 }
 
@@ -72,15 +73,15 @@ func (s *stream) run() {
 		select {
 		case established := <-s.connEstablished:
 			s.addConnection(established)
-			s.connCount++
-			s.log.Debugf("#%s: connection established, all connections: %d", established.key, s.connCount)
-			s.telemetryReporter.RecordConnections(s.connCount, s.sdkId, s.serverType, established.key)
+			connCount := s.connCount.Add(1)
+			s.log.Debugf("#%s: connection established, all connections: %d", established.key, connCount)
+			s.telemetryReporter.RecordConnections(connCount, s.sdkId, s.serverType, established.key)
 
 		case closed := <-s.connClosed:
 			s.removeConnection(closed)
-			s.connCount--
-			s.log.Debugf("#%s: connection closed, all connections: %d", closed.key, s.connCount)
-			s.telemetryReporter.RecordConnections(s.connCount, s.sdkId, s.serverType, closed.key)
+			connCount := s.connCount.Add(-1)
+			s.log.Debugf("#%s: connection closed, all connections: %d", closed.key, connCount)
+			s.telemetryReporter.RecordConnections(connCount, s.sdkId, s.serverType, closed.key)
 
 		case <-s.sdkConfigChanged:
 			s.notifyConnections()
@@ -99,6 +100,10 @@ func (s *stream) SdkKeys() (string, *string) {
 	return s.sdkClient.Load().(sdk.Client).SdkKeys()
 }
 
+func (s *stream) ConnectionCount() int64 {
+	return s.connCount.Load()
+}
+
 func (s *stream) IsInValidState() bool {
 	return s.sdkClient.Load().(sdk.Client).IsInValidState()
 }
@@ -51,6 +51,33 @@ func TestStream_Receive(t *testing.T) {
 	})
 }
 
+func TestStream_ConfigJson_Receive(t *testing.T) {
+	clients, h, key := sdk.NewTestSdkClient(t)
+
+	str := NewStream("test", clients["test"], telemetry.NewEmptyReporter(), log.NewNullLogger(), "test")
+	defer str.Close()
+
+	conn := str.CreateConnection(ConfigJsonDiscriminator, nil)
+	var first *model.ConfigPayload
+	testutils.WithTimeout(2*time.Second, func() {
+		pyl := <-conn.Receive()
+		first = pyl.(*model.ConfigPayload)
+		assert.Equal(t, clients["test"].GetCachedJson().ETag, first.ETag)
+		assert.Contains(t, string(first.Config), `"flag"`)
+	})
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {
+			Default: false,
+		},
+	})
+	_ = clients["test"].Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		pyl := <-conn.Receive()
+		assert.NotEqual(t, first.ETag, pyl.(*model.ConfigPayload).ETag)
+		assert.Equal(t, clients["test"].GetCachedJson().ConfigJson, []byte(pyl.(*model.ConfigPayload).Config))
+	})
+}
+
 func TestStream_Offline_Receive(t *testing.T) {
 	testutils.UseTempFile(`{"f":{"flag":{"a":"","i":"v_flag","v":{"b":true},"t":0}}}`, func(path string) {
 		ctx := sdk.NewTestSdkContext(&config.SDKConfig{Key: "key", Offline: config.OfflineConfig{Enabled: true, Local: config.LocalConfig{FilePath: path}}}, nil)
@@ -0,0 +1,415 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/stream"
+)
+
+const (
+	SdkIdPathVariable     = "sdkId"
+	ComponentPathVariable = "component"
+	VersionPathVariable   = "version"
+)
+
+type sdkRequest struct {
+	Key                      string          `json:"key"`
+	BaseUrl                  string          `json:"baseUrl"`
+	PollInterval             int             `json:"pollInterval"`
+	DataGovernance           string          `json:"dataGovernance"`
+	WebhookSigningKey        string          `json:"webhookSigningKey"`
+	WebhookSignatureValidFor int             `json:"webhookSignatureValidFor"`
+	DefaultAttrs             model.UserAttrs `json:"defaultUserAttributes"`
+	LogLevel                 string          `json:"logLevel"`
+}
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+type pinRequest struct {
+	Version int `json:"version"`
+}
+
+type sdkInfo struct {
+	Mode      status.SDKMode      `json:"mode"`
+	Source    status.SDKSource    `json:"source"`
+	Status    status.HealthStatus `json:"status"`
+	ETag      string              `json:"etag,omitempty"`
+	FetchTime *time.Time          `json:"fetchTime,omitempty"`
+	FlagCount int                 `json:"flagCount"`
+}
+
+type Server struct {
+	sdkRegistrar   sdk.Registrar
+	statusReporter status.Reporter
+	streamServers  map[string]stream.Server
+	logger         log.Logger
+}
+
+func NewServer(sdkRegistrar sdk.Registrar, statusReporter status.Reporter, streamServers map[string]stream.Server, log log.Logger) *Server {
+	adminLogger := log.WithPrefix("admin")
+	return &Server{
+		sdkRegistrar:   sdkRegistrar,
+		statusReporter: statusReporter,
+		streamServers:  streamServers,
+		logger:         adminLogger,
+	}
+}
+
+func (s *Server) ListSdks(w http.ResponseWriter, _ *http.Request) {
+	stat := s.statusReporter.GetStatus()
+	res := make(map[string]*sdkInfo)
+	for sdkId, sdkClient := range s.sdkRegistrar.GetAll() {
+		res[sdkId] = s.collectSdkInfo(sdkId, sdkClient, &stat)
+	}
+	writeJson(w, res)
+}
+
+func (s *Server) GetSdk(w http.ResponseWriter, r *http.Request) {
+	sdkId, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	stat := s.statusReporter.GetStatus()
+	writeJson(w, s.collectSdkInfo(sdkId, sdkClient, &stat))
+}
+
+func (s *Server) RefreshSdk(w http.ResponseWriter, r *http.Request) {
+	sdkId, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if err = sdkClient.Refresh(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.logger.Infof("SDK '%s' refreshed via the admin API", sdkId)
+}
+
+func (s *Server) RefreshAll(w http.ResponseWriter, r *http.Request) {
+	s.sdkRegistrar.RefreshAll(r.Context())
+	s.logger.Infof("all SDKs refreshed via the admin API")
+}
+
+func (s *Server) StreamConnections(w http.ResponseWriter, _ *http.Request) {
+	res := make(map[string]map[string]int64)
+	for sdkId := range s.sdkRegistrar.GetAll() {
+		res[sdkId] = make(map[string]int64, len(s.streamServers))
+	}
+	for serverType, streamServer := range s.streamServers {
+		for sdkId, count := range streamServer.ConnectionCounts() {
+			if counts, ok := res[sdkId]; ok {
+				counts[serverType] = count
+			}
+		}
+	}
+	writeJson(w, res)
+}
+
+func (s *Server) GetLogLevels(w http.ResponseWriter, _ *http.Request) {
+	levels := s.logger.ComponentLevels()
+	res := make(map[string]string, len(levels))
+	for component, level := range levels {
+		res[component] = level.String()
+	}
+	writeJson(w, res)
+}
+
+func (s *Server) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var levelReq logLevelRequest
+	if err = json.Unmarshal(reqBody, &levelReq); err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse JSON body: %s", err), http.StatusBadRequest)
+		return
+	}
+	level, ok := log.ParseLevel(levelReq.Level)
+	if !ok {
+		http.Error(w, fmt.Sprintf("invalid log level '%s', it must be 'debug', 'info', 'warn', 'error', or 'none'", levelReq.Level), http.StatusBadRequest)
+		return
+	}
+	component := r.PathValue(ComponentPathVariable)
+	s.logger.SetComponentLevel(component, level)
+	s.logger.Reportf("log level of component '%s' set to '%s' via the admin API", component, level)
+}
+
+func (s *Server) ResetLogLevel(w http.ResponseWriter, r *http.Request) {
+	component := r.PathValue(ComponentPathVariable)
+	if _, ok := s.logger.ComponentLevels()[component]; !ok {
+		http.Error(w, fmt.Sprintf("no log level override found for component '%s'", component), http.StatusNotFound)
+		return
+	}
+	s.logger.ResetComponentLevel(component)
+	s.logger.Reportf("log level override of component '%s' removed via the admin API", component)
+}
+
+func (s *Server) History(w http.ResponseWriter, r *http.Request) {
+	_, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeJson(w, sdkClient.History())
+}
+
+func (s *Server) ConfigVersion(w http.ResponseWriter, r *http.Request) {
+	_, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	version, err := strconv.Atoi(r.PathValue(VersionPathVariable))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("'%s' path parameter must be a number", VersionPathVariable), http.StatusBadRequest)
+		return
+	}
+	configVersion, err := sdkClient.ConfigVersion(version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("config version %d not found", version), http.StatusNotFound)
+		return
+	}
+	writeJson(w, configVersion)
+}
+
+func (s *Server) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	_, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "'from' query parameter must be a number", http.StatusBadRequest)
+		return
+	}
+	to := sdkClient.History().Latest
+	if toParam := r.URL.Query().Get("to"); toParam != "" {
+		if to, err = strconv.Atoi(toParam); err != nil {
+			http.Error(w, "'to' query parameter must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	diff, err := sdkClient.DiffVersions(from, to)
+	if errors.Is(err, sdk.ErrVersionNotFound) {
+		http.Error(w, fmt.Sprintf("config version %d or %d not found", from, to), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJson(w, diff)
+}
+
+func (s *Server) Changes(w http.ResponseWriter, r *http.Request) {
+	_, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	since := 0
+	if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+		if since, err = strconv.Atoi(sinceParam); err != nil {
+			http.Error(w, "'since' query parameter must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	writeJson(w, sdkClient.Changes(since))
+}
+
+func (s *Server) Pin(w http.ResponseWriter, r *http.Request) {
+	sdkId, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var pinReq pinRequest
+	if err = json.Unmarshal(reqBody, &pinReq); err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse JSON body: %s", err), http.StatusBadRequest)
+		return
+	}
+	err = sdkClient.Pin(r.Context(), pinReq.Version)
+	if errors.Is(err, sdk.ErrVersionNotFound) {
+		http.Error(w, fmt.Sprintf("config version %d not found", pinReq.Version), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.logger.Infof("SDK '%s' pinned to config version %d via the admin API", sdkId, pinReq.Version)
+}
+
+func (s *Server) Unpin(w http.ResponseWriter, r *http.Request) {
+	sdkId, sdkClient, err, code := s.getSdkClient(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if err = sdkClient.Unpin(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.logger.Infof("SDK '%s' unpinned via the admin API", sdkId)
+}
+
+func (s *Server) AddSdk(w http.ResponseWriter, r *http.Request) {
+	registrar, sdkId, sdkConf, err, code := s.parseSdkRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if err = registrar.AddSdk(sdkId, sdkConf); err != nil {
+		http.Error(w, err.Error(), errorToStatusCode(err))
+		return
+	}
+	s.logger.Infof("SDK '%s' added via the admin API", sdkId)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) ResetSdk(w http.ResponseWriter, r *http.Request) {
+	registrar, sdkId, sdkConf, err, code := s.parseSdkRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	if err = registrar.ResetSdk(sdkId, sdkConf); err != nil {
+		http.Error(w, err.Error(), errorToStatusCode(err))
+		return
+	}
+	s.logger.Infof("SDK '%s' reset via the admin API", sdkId)
+}
+
+func (s *Server) RemoveSdk(w http.ResponseWriter, r *http.Request) {
+	registrar, err, code := s.getManualRegistrar()
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	sdkId := r.PathValue(SdkIdPathVariable)
+	if sdkId == "" {
+		http.Error(w, fmt.Sprintf("'%s' path parameter must be set", SdkIdPathVariable), http.StatusBadRequest)
+		return
+	}
+	if err = registrar.RemoveSdk(sdkId); err != nil {
+		http.Error(w, err.Error(), errorToStatusCode(err))
+		return
+	}
+	s.logger.Infof("SDK '%s' removed via the admin API", sdkId)
+}
+
+func (s *Server) collectSdkInfo(sdkId string, sdkClient sdk.Client, stat *status.Status) *sdkInfo {
+	info := &sdkInfo{Status: status.Initializing}
+	if sdkStatus, ok := stat.SDKs[sdkId]; ok {
+		info.Mode = sdkStatus.Mode
+		info.Source = sdkStatus.Source.Type
+		info.Status = sdkStatus.Source.Status
+	}
+	select {
+	case <-sdkClient.Ready():
+	default:
+		// don't block the request until the SDK finishes its initialization
+		return info
+	}
+	if entry := sdkClient.GetCachedJson(); entry != nil && !entry.Empty {
+		info.ETag = entry.ETag
+		if !entry.FetchTime.IsZero() {
+			fetchTime := entry.FetchTime.UTC()
+			info.FetchTime = &fetchTime
+		}
+	}
+	info.FlagCount = len(sdkClient.Keys())
+	return info
+}
+
+func (s *Server) getSdkClient(r *http.Request) (string, sdk.Client, error, int) {
+	sdkId := r.PathValue(SdkIdPathVariable)
+	if sdkId == "" {
+		return "", nil, fmt.Errorf("'%s' path parameter must be set", SdkIdPathVariable), http.StatusBadRequest
+	}
+	sdkClient := s.sdkRegistrar.GetSdkOrNil(sdkId)
+	if sdkClient == nil {
+		return "", nil, fmt.Errorf("%w: '%s'", sdk.ErrSdkNotFound, sdkId), http.StatusNotFound
+	}
+	return sdkId, sdkClient, nil, http.StatusOK
+}
+
+func (s *Server) parseSdkRequest(r *http.Request) (sdk.ManualRegistrar, string, *config.SDKConfig, error, int) {
+	registrar, err, code := s.getManualRegistrar()
+	if err != nil {
+		return nil, "", nil, err, code
+	}
+	sdkId := r.PathValue(SdkIdPathVariable)
+	if sdkId == "" {
+		return nil, "", nil, fmt.Errorf("'%s' path parameter must be set", SdkIdPathVariable), http.StatusBadRequest
+	}
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read request body"), http.StatusBadRequest
+	}
+	var sdkReq sdkRequest
+	if err = json.Unmarshal(reqBody, &sdkReq); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to parse JSON body: %s", err), http.StatusBadRequest
+	}
+	return registrar, sdkId, &config.SDKConfig{
+		Key:                      sdkReq.Key,
+		BaseUrl:                  sdkReq.BaseUrl,
+		PollInterval:             sdkReq.PollInterval,
+		DataGovernance:           sdkReq.DataGovernance,
+		WebhookSigningKey:        sdkReq.WebhookSigningKey,
+		WebhookSignatureValidFor: sdkReq.WebhookSignatureValidFor,
+		DefaultAttrs:             sdkReq.DefaultAttrs,
+		Log:                      config.LogConfig{Level: sdkReq.LogLevel},
+	}, nil, http.StatusOK
+}
+
+func (s *Server) getManualRegistrar() (sdk.ManualRegistrar, error, int) {
+	registrar, ok := s.sdkRegistrar.(sdk.ManualRegistrar)
+	if !ok {
+		return nil, fmt.Errorf("SDKs are managed by the proxy profile and can't be modified via the admin API"), http.StatusConflict
+	}
+	return registrar, nil, http.StatusOK
+}
+
+func errorToStatusCode(err error) int {
+	switch {
+	case errors.Is(err, sdk.ErrSdkNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, sdk.ErrSdkAlreadyExists), errors.Is(err, sdk.ErrSdkKeyInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func writeJson(w http.ResponseWriter, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
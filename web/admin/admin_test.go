@@ -0,0 +1,427 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/diag/status"
+	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/internal/testutils"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/configcat/configcat-proxy/model"
+	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/stream"
+	"github.com/configcat/go-sdk/v9/configcattest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmin_AddSdk(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		srv, reg := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"new-key","pollInterval":60,"dataGovernance":"eu"}`))
+		testutils.AddSdkIdContextParamWithSdkId(req, "new")
+		srv.AddSdk(res, req)
+
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.NotNil(t, reg.GetSdkOrNil("new"))
+		assert.NotNil(t, reg.GetSdkByKeyOrNil("new-key"))
+	})
+	t.Run("already exists", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"new-key"}`))
+		testutils.AddSdkIdContextParam(req)
+		srv.AddSdk(res, req)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, "SDK already exists: 'test'\n", res.Body.String())
+	})
+	t.Run("key in use", func(t *testing.T) {
+		srv, reg := newServer(t)
+		key, _ := reg.GetSdkOrNil("test").SdkKeys()
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"`+key+`"}`))
+		testutils.AddSdkIdContextParamWithSdkId(req, "new")
+		srv.AddSdk(res, req)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Nil(t, reg.GetSdkOrNil("new"))
+	})
+	t.Run("invalid config", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"new-key","dataGovernance":"invalid"}`))
+		testutils.AddSdkIdContextParamWithSdkId(req, "new")
+		srv.AddSdk(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "sdk-new: invalid data governance value, it must be 'global' or 'eu'\n", res.Body.String())
+	})
+	t.Run("invalid body", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":`))
+		testutils.AddSdkIdContextParamWithSdkId(req, "new")
+		srv.AddSdk(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+	t.Run("profile mode", func(t *testing.T) {
+		reg, _, _ := sdk.NewTestAutoRegistrarWithAutoConfig(t, config.ProfileConfig{PollInterval: 60}, log.NewNullLogger())
+		srv := NewServer(reg, status.NewEmptyReporter(), nil, log.NewNullLogger())
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"new-key"}`))
+		testutils.AddSdkIdContextParamWithSdkId(req, "new")
+		srv.AddSdk(res, req)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, "SDKs are managed by the proxy profile and can't be modified via the admin API\n", res.Body.String())
+	})
+}
+
+func TestAdmin_ResetSdk(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		srv, reg := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"key":"new-key"}`))
+		testutils.AddSdkIdContextParam(req)
+		srv.ResetSdk(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.NotNil(t, reg.GetSdkByKeyOrNil("new-key"))
+	})
+	t.Run("not found", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"key":"new-key"}`))
+		testutils.AddSdkIdContextParamWithSdkId(req, "non-existing")
+		srv.ResetSdk(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, "SDK not found: 'non-existing'\n", res.Body.String())
+	})
+}
+
+func TestAdmin_RemoveSdk(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		srv, reg := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.RemoveSdk(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Nil(t, reg.GetSdkOrNil("test"))
+	})
+	t.Run("not found", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/", http.NoBody)
+		testutils.AddSdkIdContextParamWithSdkId(req, "non-existing")
+		srv.RemoveSdk(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+	t.Run("missing sdk id", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/", http.NoBody)
+		srv.RemoveSdk(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
+
+func TestAdmin_ListSdks(t *testing.T) {
+	srv, reg := newServer(t)
+	<-reg.GetSdkOrNil("test").Ready()
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+	srv.ListSdks(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var sdks map[string]*sdkInfo
+	_ = json.Unmarshal(res.Body.Bytes(), &sdks)
+	assert.Len(t, sdks, 1)
+	assert.Equal(t, status.Online, sdks["test"].Mode)
+	assert.Equal(t, status.RemoteSrc, sdks["test"].Source)
+	assert.Equal(t, status.Healthy, sdks["test"].Status)
+	assert.NotEmpty(t, sdks["test"].ETag)
+	assert.NotNil(t, sdks["test"].FetchTime)
+	assert.Equal(t, 1, sdks["test"].FlagCount)
+}
+
+func TestAdmin_GetSdk(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		srv, reg := newServer(t)
+		<-reg.GetSdkOrNil("test").Ready()
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.GetSdk(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		var info sdkInfo
+		_ = json.Unmarshal(res.Body.Bytes(), &info)
+		assert.Equal(t, 1, info.FlagCount)
+		assert.NotEmpty(t, info.ETag)
+	})
+	t.Run("not found", func(t *testing.T) {
+		srv, _ := newServer(t)
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+		testutils.AddSdkIdContextParamWithSdkId(req, "non-existing")
+		srv.GetSdk(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestAdmin_Refresh(t *testing.T) {
+	reg, h, key := sdk.NewTestRegistrarT(t)
+	srv := NewServer(reg, status.NewEmptyReporter(), nil, log.NewNullLogger())
+	sdkClient := reg.GetSdkOrNil("test")
+	<-sdkClient.Ready()
+	assert.Equal(t, 1, len(sdkClient.Keys()))
+
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag":  {Default: true},
+		"flag2": {Default: false},
+	})
+
+	t.Run("single", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.RefreshSdk(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 2, len(sdkClient.Keys()))
+	})
+	t.Run("single not found", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", http.NoBody)
+		testutils.AddSdkIdContextParamWithSdkId(req, "non-existing")
+		srv.RefreshSdk(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+	t.Run("all", func(t *testing.T) {
+		_ = h.SetFlags(key, map[string]*configcattest.Flag{
+			"flag": {Default: true},
+		})
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", http.NoBody)
+		srv.RefreshAll(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 1, len(sdkClient.Keys()))
+	})
+}
+
+func TestAdmin_StreamConnections(t *testing.T) {
+	reg, _, _ := sdk.NewTestRegistrarT(t)
+	sseStreams := stream.NewServer(reg, telemetry.NewEmptyReporter(), log.NewNullLogger(), "sse")
+	grpcStreams := stream.NewServer(reg, telemetry.NewEmptyReporter(), log.NewNullLogger(), "grpc")
+	defer sseStreams.Close()
+	defer grpcStreams.Close()
+	srv := NewServer(reg, status.NewEmptyReporter(), map[string]stream.Server{"sse": sseStreams, "grpc": grpcStreams}, log.NewNullLogger())
+
+	str := sseStreams.GetStreamOrNil("test")
+	conn1 := str.CreateConnection("flag", nil)
+	conn2 := str.CreateConnection(stream.AllFlagsDiscriminator, nil)
+	defer str.CloseConnection(conn1, "flag")
+	defer str.CloseConnection(conn2, stream.AllFlagsDiscriminator)
+	testutils.WaitUntil(time.Second, func() bool {
+		return str.ConnectionCount() == 2
+	})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+	srv.StreamConnections(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `{"test":{"grpc":0,"sse":2}}`, res.Body.String())
+}
+
+func TestAdmin_LogLevels(t *testing.T) {
+	logger := log.NewNullLogger()
+	reg, _, _ := sdk.NewTestRegistrarT(t)
+	srv := NewServer(reg, status.NewEmptyReporter(), nil, logger)
+	sseLog := logger.WithPrefix("http").WithPrefix("sse")
+
+	t.Run("set", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug"}`))
+		req.SetPathValue(ComponentPathVariable, "http")
+		srv.SetLogLevel(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, log.Debug, sseLog.Level())
+	})
+	t.Run("set invalid", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"verbose"}`))
+		req.SetPathValue(ComponentPathVariable, "http")
+		srv.SetLogLevel(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "invalid log level 'verbose', it must be 'debug', 'info', 'warn', 'error', or 'none'\n", res.Body.String())
+	})
+	t.Run("get", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+		srv.GetLogLevels(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"http":"debug"}`, res.Body.String())
+	})
+	t.Run("reset", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/", http.NoBody)
+		req.SetPathValue(ComponentPathVariable, "http")
+		srv.ResetLogLevel(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, log.None, sseLog.Level())
+	})
+	t.Run("reset not found", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/", http.NoBody)
+		req.SetPathValue(ComponentPathVariable, "http")
+		srv.ResetLogLevel(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestAdmin_History(t *testing.T) {
+	reg, h, key := sdk.NewTestRegistrarT(t)
+	srv := NewServer(reg, status.NewEmptyReporter(), nil, log.NewNullLogger())
+	sdkClient := reg.GetSdkOrNil("test")
+	<-sdkClient.Ready()
+	sub := make(chan struct{}, 10)
+	sdkClient.Subscribe(sub)
+	defer sdkClient.Unsubscribe(sub)
+
+	_ = h.SetFlags(key, map[string]*configcattest.Flag{
+		"flag": {Default: false},
+	})
+	_ = sdkClient.Refresh(t.Context())
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+
+	t.Run("list", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.History(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		var history model.ConfigHistory
+		_ = json.Unmarshal(res.Body.Bytes(), &history)
+		assert.Equal(t, 2, history.Latest)
+		assert.Len(t, history.Versions, 2)
+	})
+	t.Run("version", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		req.SetPathValue(VersionPathVariable, "1")
+		srv.ConfigVersion(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), `"config":{`)
+	})
+	t.Run("version not found", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		req.SetPathValue(VersionPathVariable, "5")
+		srv.ConfigVersion(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, "config version 5 not found\n", res.Body.String())
+	})
+	t.Run("diff", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/?from=1", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.DiffVersions(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		var diff model.ConfigDiff
+		_ = json.Unmarshal(res.Body.Bytes(), &diff)
+		assert.Equal(t, 2, diff.To)
+		assert.Len(t, diff.Flags, 1)
+		assert.Equal(t, model.DiffModified, diff.Flags[0].Change)
+	})
+	t.Run("diff invalid", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/?from=a", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.DiffVersions(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+	t.Run("changes", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/?since=0", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.Changes(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		var changes []model.ConfigChange
+		_ = json.Unmarshal(res.Body.Bytes(), &changes)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "flag", changes[0].Flags[0].Key)
+	})
+	t.Run("changes invalid", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/?since=a", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.Changes(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+	t.Run("pin", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"version":1}`))
+		testutils.AddSdkIdContextParam(req)
+		srv.Pin(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 1, sdkClient.History().Pinned)
+	})
+	t.Run("pin not found", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"version":5}`))
+		testutils.AddSdkIdContextParam(req)
+		srv.Pin(res, req)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, 1, sdkClient.History().Pinned)
+	})
+	t.Run("unpin", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/", http.NoBody)
+		testutils.AddSdkIdContextParam(req)
+		srv.Unpin(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Zero(t, sdkClient.History().Pinned)
+	})
+}
+
+func newServer(t *testing.T) (*Server, sdk.Registrar) {
+	reporter := status.NewEmptyReporter()
+	reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+	return NewServer(reg, reporter, nil, log.NewNullLogger()), reg
+}
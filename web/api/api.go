@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/log"
@@ -21,6 +23,11 @@ type keysResponse struct {
 	Keys []string `json:"keys"`
 }
 
+type overrideRequest struct {
+	Value interface{} `json:"value"`
+	Ttl   int         `json:"ttl"`
+}
+
 type Server struct {
 	sdkRegistrar sdk.Registrar
 	config       *config.ApiConfig
@@ -40,11 +47,28 @@ func (s *Server) Eval(w http.ResponseWriter, r *http.Request) {
 	var evalReq model.EvalRequest
 	sdkClient, err, code := s.parseRequest(r, &evalReq)
 	if err != nil {
-		http.Error(w, err.Error(), code)
+		if sdkClient != nil && evalReq.DefaultValue != nil {
+			if !errors.Is(err, sdk.ErrNotReady) {
+				writePayload(w, model.DefaultPayload(evalReq.DefaultValue, model.ReasonError, model.ErrorCodeGeneral, err.Error()))
+				return
+			}
+			if sdkClient.ServeDefaultsWhileInitializing() {
+				writePayload(w, model.DefaultPayload(evalReq.DefaultValue, model.ReasonDefault, model.ErrorCodeProviderNotReady, err.Error()))
+				return
+			}
+		}
+		httpError(w, err, code)
 		return
 	}
 	eval := sdkClient.Eval(evalReq.Key, evalReq.User)
+	if eval.Error == nil && evalReq.DefaultValue != nil {
+		eval.Error = model.CheckDefaultType(eval.Value, evalReq.DefaultValue)
+	}
 	if eval.Error != nil {
+		if evalReq.DefaultValue != nil {
+			writePayload(w, model.DefaultPayload(evalReq.DefaultValue, model.ReasonError, model.ErrorCodeOf(eval.Error), eval.Error.Error()))
+			return
+		}
 		var errKeyNotFound configcat.ErrKeyNotFound
 		if errors.As(eval.Error, &errKeyNotFound) {
 			http.Error(w, "feature flag or setting with key '"+evalReq.Key+"' not found", http.StatusBadRequest)
@@ -53,21 +77,14 @@ func (s *Server) Eval(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	payload := model.PayloadFromEvalData(&eval)
-	data, err := json.Marshal(payload)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(data)
+	writePayload(w, model.PayloadFromEvalData(&eval))
 }
 
 func (s *Server) EvalAll(w http.ResponseWriter, r *http.Request) {
 	var evalReq model.EvalRequest
 	sdkClient, err, code := s.parseRequest(r, &evalReq)
 	if err != nil {
-		http.Error(w, err.Error(), code)
+		httpError(w, err, code)
 		return
 	}
 	details := sdkClient.EvalAll(evalReq.User)
@@ -87,7 +104,7 @@ func (s *Server) EvalAll(w http.ResponseWriter, r *http.Request) {
 func (s *Server) Keys(w http.ResponseWriter, r *http.Request) {
 	sdkClient, err, code := s.getSDKClient(r)
 	if err != nil {
-		http.Error(w, err.Error(), code)
+		httpError(w, err, code)
 		return
 	}
 	keys := sdkClient.Keys()
@@ -103,7 +120,7 @@ func (s *Server) Keys(w http.ResponseWriter, r *http.Request) {
 func (s *Server) Refresh(w http.ResponseWriter, r *http.Request) {
 	sdkClient, err, code := s.getSDKClient(r)
 	if err != nil {
-		http.Error(w, err.Error(), code)
+		httpError(w, err, code)
 		return
 	}
 	err = sdkClient.Refresh(r.Context())
@@ -113,6 +130,84 @@ func (s *Server) Refresh(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *Server) Overrides(w http.ResponseWriter, r *http.Request) {
+	sdkClient, err, code := s.getSDKClient(r)
+	if err != nil {
+		httpError(w, err, code)
+		return
+	}
+	data, err := json.Marshal(sdkClient.Overrides())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) SetOverride(w http.ResponseWriter, r *http.Request) {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var overrideReq overrideRequest
+	if err = json.Unmarshal(reqBody, &overrideReq); err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse JSON body: %s", err), http.StatusBadRequest)
+		return
+	}
+	if overrideReq.Value == nil {
+		http.Error(w, "'value' must be set", http.StatusBadRequest)
+		return
+	}
+	if overrideReq.Ttl < 1 {
+		http.Error(w, "'ttl' must be greater than 0", http.StatusBadRequest)
+		return
+	}
+	sdkClient, err, code := s.getSDKClient(r)
+	if err != nil {
+		httpError(w, err, code)
+		return
+	}
+	key := r.PathValue("key")
+	err = sdkClient.SetOverride(r.Context(), key, overrideReq.Value, time.Duration(overrideReq.Ttl)*time.Second)
+	if errors.Is(err, sdk.ErrOverrideKeyNotFound) {
+		http.Error(w, "feature flag or setting with key '"+key+"' not found", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, sdk.ErrOverrideTypeMismatch) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("failed to set override for '%s': %s", key, err)
+		http.Error(w, "the request failed; please check the logs for more details", http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(sdkClient.Overrides()[key])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) RemoveOverride(w http.ResponseWriter, r *http.Request) {
+	sdkClient, err, code := s.getSDKClient(r)
+	if err != nil {
+		httpError(w, err, code)
+		return
+	}
+	key := r.PathValue("key")
+	if err = sdkClient.RemoveOverride(r.Context(), key); err != nil {
+		s.logger.Errorf("failed to remove override for '%s': %s", key, err)
+		http.Error(w, "the request failed; please check the logs for more details", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *Server) ICanHasCoffee(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusTeapot)
 }
@@ -126,13 +221,14 @@ func (s *Server) parseRequest(r *http.Request, evalReq *model.EvalRequest) (sdk.
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JSON body: %s", err), http.StatusBadRequest
 	}
-	sdkClient, err, code := s.getSDKClient(r)
-	if err != nil {
-		return nil, err, code
+	if evalReq.DefaultValue != nil && !model.IsValidDefaultValue(evalReq.DefaultValue) {
+		return nil, fmt.Errorf("'defaultValue' must be a boolean, number or string"), http.StatusBadRequest
 	}
-	return sdkClient, nil, http.StatusOK
+	return s.getSDKClient(r)
 }
 
+// getSDKClient returns the SDK identified by the request. When the SDK is not initialized within its init timeout
+// or is in an invalid state, the client is returned along with the error, so the caller can still serve default values.
 func (s *Server) getSDKClient(r *http.Request) (sdk.Client, error, int) {
 	var sdkClient sdk.Client
 	sdkId := r.PathValue("sdkId")
@@ -148,8 +244,28 @@ func (s *Server) getSDKClient(r *http.Request) (sdk.Client, error, int) {
 	if sdkClient == nil {
 		return nil, fmt.Errorf("could not identify a configured SDK"), http.StatusNotFound
 	}
+	if err := sdkClient.WaitReady(); err != nil {
+		return sdkClient, err, http.StatusServiceUnavailable
+	}
 	if !sdkClient.IsInValidState() {
-		return nil, fmt.Errorf("requested SDK is in an invalid state; please check the logs for more details"), http.StatusInternalServerError
+		return sdkClient, fmt.Errorf("requested SDK is in an invalid state; please check the logs for more details"), http.StatusInternalServerError
 	}
 	return sdkClient, nil, http.StatusOK
 }
+
+func writePayload(w http.ResponseWriter, payload model.ResponsePayload) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func httpError(w http.ResponseWriter, err error, code int) {
+	if errors.Is(err, sdk.ErrNotReady) {
+		w.Header().Set("Retry-After", strconv.Itoa(sdk.NotReadyRetryAfter))
+	}
+	http.Error(w, err.Error(), code)
+}
@@ -129,6 +129,64 @@ func TestAPI_Eval(t *testing.T) {
 	})
 }
 
+func TestAPI_Eval_DefaultValue(t *testing.T) {
+	t.Run("flag not found", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"non-existing","defaultValue":"fallback"}`))
+
+		srv := newServer(t, config.ApiConfig{Enabled: true})
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), `{"value":"fallback","variationId":"","reason":"ERROR","errorCode":"FLAG_NOT_FOUND","errorMessage":`)
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag","defaultValue":"fallback"}`))
+
+		srv := newServer(t, config.ApiConfig{Enabled: true})
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), `{"value":"fallback","variationId":"","reason":"ERROR","errorCode":"TYPE_MISMATCH","errorMessage":`)
+	})
+	t.Run("matching type", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag","defaultValue":false}`))
+
+		srv := newServer(t, config.ApiConfig{Enabled: true})
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"value":true,"variationId":"v_flag"}`, res.Body.String())
+	})
+	t.Run("invalid state", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag","defaultValue":false}`))
+
+		srv := newErrorServer(t, config.ApiConfig{Enabled: true})
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"value":false,"variationId":"","reason":"ERROR","errorCode":"GENERAL","errorMessage":"requested SDK is in an invalid state; please check the logs for more details"}`, res.Body.String())
+	})
+	t.Run("invalid default", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag","defaultValue":{"a":"b"}}`))
+
+		srv := newServer(t, config.ApiConfig{Enabled: true})
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "'defaultValue' must be a boolean, number or string\n", res.Body.String())
+	})
+}
+
 func TestAPI_EvalAll(t *testing.T) {
 	t.Run("online", func(t *testing.T) {
 		res := httptest.NewRecorder()
@@ -296,6 +354,80 @@ func TestAPI_Refresh(t *testing.T) {
 	assert.Equal(t, `{"value":false,"variationId":"v_flag"}`, res.Body.String())
 }
 
+func TestAPI_Overrides(t *testing.T) {
+	srv := newServer(t, config.ApiConfig{Enabled: true})
+
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(`{"value":false,"ttl":60}`))
+	testutils.AddSdkIdContextParam(req)
+	req.SetPathValue("key", "flag")
+	srv.SetOverride(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `{"value":false,"expiresAt":"`)
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag"}`))
+	testutils.AddSdkIdContextParam(req)
+	srv.Eval(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `{"value":false,"variationId":"","overridden":true}`, res.Body.String())
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/", http.NoBody)
+	testutils.AddSdkIdContextParam(req)
+	srv.Overrides(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `{"flag":{"value":false,"expiresAt":"`)
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/", http.NoBody)
+	testutils.AddSdkIdContextParam(req)
+	req.SetPathValue("key", "flag")
+	srv.RemoveOverride(res, req)
+
+	assert.Equal(t, http.StatusNoContent, res.Code)
+
+	res = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag"}`))
+	testutils.AddSdkIdContextParam(req)
+	srv.Eval(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `{"value":true,"variationId":"v_flag"}`, res.Body.String())
+}
+
+func TestAPI_SetOverride_Invalid(t *testing.T) {
+	srv := newServer(t, config.ApiConfig{Enabled: true})
+
+	tests := []struct {
+		name string
+		key  string
+		body string
+		err  string
+	}{
+		{name: "invalid body", key: "flag", body: `{`, err: "failed to parse JSON body: unexpected end of JSON input\n"},
+		{name: "missing value", key: "flag", body: `{"ttl":60}`, err: "'value' must be set\n"},
+		{name: "missing ttl", key: "flag", body: `{"value":false}`, err: "'ttl' must be greater than 0\n"},
+		{name: "key not found", key: "non-existing", body: `{"value":false,"ttl":60}`, err: "feature flag or setting with key 'non-existing' not found\n"},
+		{name: "type mismatch", key: "flag", body: `{"value":"false","ttl":60}`, err: "override value doesn't match the type of the feature flag or setting\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPut, "/", strings.NewReader(test.body))
+			testutils.AddSdkIdContextParam(req)
+			req.SetPathValue("key", test.key)
+			srv.SetOverride(res, req)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, test.err, res.Body.String())
+		})
+	}
+}
+
 func TestAPI_WrongSdkId(t *testing.T) {
 	t.Run("Eval", func(t *testing.T) {
 		res := httptest.NewRecorder()
@@ -382,6 +514,57 @@ func TestAPI_WrongSDKState(t *testing.T) {
 	})
 }
 
+func TestAPI_NotReady(t *testing.T) {
+	release := make(chan struct{})
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(func() {
+		close(release)
+		h.Close()
+	})
+	newNotReadyServer := func(serveDefaults bool) *Server {
+		reg := sdk.NewTestRegistrar(&config.SDKConfig{BaseUrl: h.URL, Key: configcattest.RandomSDKKey(), Init: config.InitConfig{Timeout: 1, ServeDefaults: serveDefaults}}, nil)
+		t.Cleanup(reg.Close)
+		return NewServer(reg, &config.ApiConfig{Enabled: true}, log.NewNullLogger())
+	}
+
+	t.Run("Eval", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag","defaultValue":false}`))
+
+		srv := newNotReadyServer(false)
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+		assert.Equal(t, "5", res.Header().Get("Retry-After"))
+		assert.Equal(t, "SDK is not initialized yet\n", res.Body.String())
+	})
+	t.Run("Eval serve defaults", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"key":"flag","defaultValue":"fallback"}`))
+
+		srv := newNotReadyServer(true)
+		testutils.AddSdkIdContextParam(req)
+		srv.Eval(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"value":"fallback","variationId":"","reason":"DEFAULT","errorCode":"PROVIDER_NOT_READY","errorMessage":"SDK is not initialized yet"}`, res.Body.String())
+	})
+	t.Run("Keys", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/", http.NoBody)
+
+		srv := newNotReadyServer(true)
+		testutils.AddSdkIdContextParam(req)
+		srv.Keys(res, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+		assert.Equal(t, "5", res.Header().Get("Retry-After"))
+	})
+}
+
 func newServer(t *testing.T, conf config.ApiConfig) *Server {
 	reg, _, _ := sdk.NewTestRegistrarT(t)
 	return NewServer(reg, &conf, log.NewNullLogger())
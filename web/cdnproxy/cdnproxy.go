@@ -1,8 +1,10 @@
 package cdnproxy
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/configcat/configcat-proxy/config"
@@ -28,6 +30,9 @@ func NewServer(sdkRegistrar sdk.Registrar, config *config.CdnProxyConfig, log lo
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	sdkClient, err, code := s.getSDKClient(r)
 	if err != nil {
+		if errors.Is(err, sdk.ErrNotReady) {
+			w.Header().Set("Retry-After", strconv.Itoa(sdk.NotReadyRetryAfter))
+		}
 		http.Error(w, err.Error(), code)
 		return
 	}
@@ -63,6 +68,9 @@ func (s *Server) getSDKClient(r *http.Request) (sdk.Client, error, int) {
 	if sdkClient == nil {
 		return nil, fmt.Errorf("could not identify a configured SDK"), http.StatusNotFound
 	}
+	if err := sdkClient.WaitReady(); err != nil {
+		return nil, err, http.StatusServiceUnavailable
+	}
 	if !sdkClient.IsInValidState() {
 		return nil, fmt.Errorf("requested SDK is in an invalid state; please check the logs for more details"), http.StatusInternalServerError
 	}
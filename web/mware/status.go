@@ -0,0 +1,30 @@
+package mware
+
+import (
+	"net/http"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/internal/utils"
+	"github.com/configcat/configcat-proxy/log"
+)
+
+// SecureStatus wraps a status endpoint with the authentication and CORS handling configured for it.
+func SecureStatus(conf *config.StatusConfig, logger log.Logger, next http.HandlerFunc) http.HandlerFunc {
+	requestHeaders := utils.KeysOfMap(conf.AuthHeaders)
+	if conf.Auth.User != "" && conf.Auth.Password != "" {
+		next = BasicAuth(conf.Auth.User, conf.Auth.Password, logger, next)
+		requestHeaders = append(requestHeaders, "Authorization")
+	}
+	if len(conf.AuthHeaders) > 0 {
+		next = HeaderAuth(conf.AuthHeaders, logger, next)
+	}
+	next = AutoOptions(next)
+	if conf.CORS.Enabled {
+		next = CORS([]string{http.MethodGet, http.MethodOptions}, conf.CORS.AllowedOrigins,
+			nil, requestHeaders, &conf.CORS.AllowedOriginsRegex, next)
+	}
+	if logger.Level() == log.Debug {
+		next = DebugLog(logger, next)
+	}
+	return next
+}
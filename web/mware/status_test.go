@@ -0,0 +1,53 @@
+package mware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/configcat/configcat-proxy/config"
+	"github.com/configcat/configcat-proxy/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureStatus(t *testing.T) {
+	conf := &config.StatusConfig{
+		Auth:        config.AuthConfig{User: "user", Password: "pass"},
+		AuthHeaders: map[string]string{"X-STATUS-KEY": "key"},
+		CORS:        config.CORSConfig{Enabled: true},
+	}
+	handler := SecureStatus(conf, log.NewNullLogger(), func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(handler)
+	client := http.Client{}
+
+	t.Run("missing auth", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+		req.Header.Set("X-STATUS-KEY", "key")
+		resp, _ := client.Do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+	t.Run("missing auth header", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+		req.SetBasicAuth("user", "pass")
+		resp, _ := client.Do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+	t.Run("auth ok", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, http.NoBody)
+		req.SetBasicAuth("user", "pass")
+		req.Header.Set("X-STATUS-KEY", "key")
+		resp, _ := client.Do(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+	t.Run("options", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodOptions, srv.URL, http.NoBody)
+		resp, _ := client.Do(req)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "GET,OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
+		assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "Authorization")
+		assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "X-STATUS-KEY")
+	})
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"hash/maphash"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
+	"strconv"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/internal/utils"
@@ -21,25 +24,31 @@ type errorCode string
 type reason string
 
 const (
-	generalErrorCode        errorCode = "GENERAL"
-	flagNotFoundErrorCode   errorCode = "FLAG_NOT_FOUND"
-	invalidContextErrorCode errorCode = "INVALID_CONTEXT"
+	generalErrorCode          errorCode = model.ErrorCodeGeneral
+	flagNotFoundErrorCode     errorCode = model.ErrorCodeFlagNotFound
+	invalidContextErrorCode   errorCode = "INVALID_CONTEXT"
+	providerNotReadyErrorCode errorCode = model.ErrorCodeProviderNotReady
 
-	defaultReason        reason = "DEFAULT"
+	defaultReason        reason = model.ReasonDefault
 	targetingMatchReason reason = "TARGETING_MATCH"
+	overrideReason       reason = "OVERRIDE"
+	errorReason          reason = model.ReasonError
 
 	SdkIdHeader = "X-ConfigCat-SdkId"
 )
 
 type evaluationRequest struct {
-	Context model.UserAttrs `json:"context"`
+	Context      model.UserAttrs `json:"context"`
+	DefaultValue interface{}     `json:"defaultValue"`
 }
 
 type evaluationResponse struct {
-	Key     string      `json:"key"`
-	Reason  reason      `json:"reason"`
-	Variant string      `json:"variant"`
-	Value   interface{} `json:"value"`
+	Key          string      `json:"key"`
+	Reason       reason      `json:"reason"`
+	Variant      string      `json:"variant"`
+	Value        interface{} `json:"value"`
+	ErrorCode    errorCode   `json:"errorCode,omitempty"`
+	ErrorDetails string      `json:"errorDetails,omitempty"`
 }
 
 type errorResponse struct {
@@ -88,7 +97,17 @@ func (s *Server) Eval(w http.ResponseWriter, r *http.Request) {
 	var evalReq evaluationRequest
 	sdkClient, err, errCode, code := s.parseRequest(r, &evalReq)
 	if err != nil {
-		if code == http.StatusInternalServerError {
+		if sdkClient != nil && evalReq.DefaultValue != nil {
+			if !errors.Is(err, sdk.ErrNotReady) {
+				s.writeDefault(w, key, evalReq.DefaultValue, errorReason, generalErrorCode, err)
+				return
+			}
+			if sdkClient.ServeDefaultsWhileInitializing() {
+				s.writeDefault(w, key, evalReq.DefaultValue, defaultReason, providerNotReadyErrorCode, err)
+				return
+			}
+		}
+		if code == http.StatusInternalServerError || code == http.StatusServiceUnavailable {
 			s.writeError(w, generalErrorResponse{ErrorDetails: err.Error()}, code)
 			return
 		}
@@ -97,7 +116,14 @@ func (s *Server) Eval(w http.ResponseWriter, r *http.Request) {
 	}
 	mapTargetingKeyToIdentifier(evalReq.Context)
 	eval := sdkClient.Eval(key, evalReq.Context)
+	if eval.Error == nil && evalReq.DefaultValue != nil {
+		eval.Error = model.CheckDefaultType(eval.Value, evalReq.DefaultValue)
+	}
 	if eval.Error != nil {
+		if evalReq.DefaultValue != nil {
+			s.writeDefault(w, key, evalReq.DefaultValue, errorReason, errorCode(model.ErrorCodeOf(eval.Error)), eval.Error)
+			return
+		}
 		var errKeyNotFound configcat.ErrKeyNotFound
 		if errors.As(eval.Error, &errKeyNotFound) {
 			s.writeError(w, errorResponse{ErrorDetails: "feature flag or setting with key '" + key + "' not found", ErrorCode: flagNotFoundErrorCode, Key: key}, http.StatusNotFound)
@@ -106,21 +132,14 @@ func (s *Server) Eval(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	payload := toEvalResponse(&eval, key)
-	data, err := json.Marshal(payload)
-	if err != nil {
-		s.writeError(w, generalErrorResponse{ErrorDetails: err.Error()}, http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(data)
+	s.writeResponse(w, toEvalResponse(&eval, key))
 }
 
 func (s *Server) EvalAll(w http.ResponseWriter, r *http.Request) {
 	var evalReq evaluationRequest
 	sdkClient, err, errCode, code := s.parseRequest(r, &evalReq)
 	if err != nil {
-		if code == http.StatusInternalServerError {
+		if code == http.StatusInternalServerError || code == http.StatusServiceUnavailable {
 			s.writeError(w, generalErrorResponse{ErrorDetails: err.Error()}, code)
 			return
 		}
@@ -129,7 +148,7 @@ func (s *Server) EvalAll(w http.ResponseWriter, r *http.Request) {
 	}
 	etag := r.Header.Get("If-None-Match")
 	c := sdkClient.GetCachedJson()
-	genEtag := s.calcEtag(evalReq.Context, c.ETag)
+	genEtag := s.calcEtag(evalReq.Context, c.ETag, sdkClient.Overrides())
 	if etag != "" && etag == genEtag {
 		w.Header().Set("ETag", genEtag)
 		w.WriteHeader(http.StatusNotModified)
@@ -157,7 +176,24 @@ func (s *Server) EvalAll(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(data)
 }
 
+func (s *Server) writeDefault(w http.ResponseWriter, key string, defaultValue interface{}, reason reason, errCode errorCode, err error) {
+	s.writeResponse(w, evaluationResponse{Key: key, Value: defaultValue, Reason: reason, ErrorCode: errCode, ErrorDetails: err.Error()})
+}
+
+func (s *Server) writeResponse(w http.ResponseWriter, payload evaluationResponse) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		s.writeError(w, generalErrorResponse{ErrorDetails: err.Error()}, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
 func (s *Server) writeError(w http.ResponseWriter, body interface{}, code int) {
+	if code == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", strconv.Itoa(sdk.NotReadyRetryAfter))
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	data, _ := json.Marshal(body)
@@ -175,13 +211,13 @@ func (s *Server) parseRequest(r *http.Request, evalReq *evaluationRequest) (sdk.
 			return nil, fmt.Errorf("failed to parse JSON body: %s", err), invalidContextErrorCode, http.StatusBadRequest
 		}
 	}
-	sdkClient, err, errCode, code := s.getSDKClient(r)
-	if err != nil {
-		return nil, err, errCode, code
+	if evalReq.DefaultValue != nil && !model.IsValidDefaultValue(evalReq.DefaultValue) {
+		return nil, fmt.Errorf("'defaultValue' must be a boolean, number or string"), generalErrorCode, http.StatusBadRequest
 	}
-	return sdkClient, nil, "", http.StatusOK
+	return s.getSDKClient(r)
 }
 
+// getSDKClient hands back the client even when it's not ready or invalid, so Eval can fall back to the request's default value.
 func (s *Server) getSDKClient(r *http.Request) (sdk.Client, error, errorCode, int) {
 	var sdkClient sdk.Client
 	sdkId := r.Header.Get(SdkIdHeader)
@@ -197,15 +233,22 @@ func (s *Server) getSDKClient(r *http.Request) (sdk.Client, error, errorCode, in
 	if sdkClient == nil {
 		return nil, fmt.Errorf("could not identify a configured SDK"), generalErrorCode, http.StatusBadRequest
 	}
+	if err := sdkClient.WaitReady(); err != nil {
+		return sdkClient, err, providerNotReadyErrorCode, http.StatusServiceUnavailable
+	}
 	if !sdkClient.IsInValidState() {
-		return nil, fmt.Errorf("requested SDK is in an invalid state; please check the logs for more details"), generalErrorCode, http.StatusInternalServerError
+		return sdkClient, fmt.Errorf("requested SDK is in an invalid state; please check the logs for more details"), generalErrorCode, http.StatusInternalServerError
 	}
 	return sdkClient, nil, "", http.StatusOK
 }
 
-func (s *Server) calcEtag(attr model.UserAttrs, configJsonEtag string) string {
+func (s *Server) calcEtag(attr model.UserAttrs, configJsonEtag string, overrides map[string]model.FlagOverride) string {
 	attrHash := attr.Discriminator(s.seed)
 	payload := append([]byte(configJsonEtag), utils.Uint64ToBytes(attrHash)...)
+	// active overrides change the evaluation results without touching the config JSON
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		payload = fmt.Appendf(payload, "%s=%v", key, overrides[key].Value)
+	}
 	return utils.GenerateEtag(payload)
 }
 
@@ -225,5 +268,8 @@ func toEvalResponse(data *model.EvalData, key string) evaluationResponse {
 	if data.IsTargeting {
 		response.Reason = targetingMatchReason
 	}
+	if data.IsOverridden {
+		response.Reason = overrideReason
+	}
 	return response
 }
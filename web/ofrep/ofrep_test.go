@@ -166,6 +166,53 @@ func TestOFREP_Eval(t *testing.T) {
 	})
 }
 
+func TestOFREP_Eval_DefaultValue(t *testing.T) {
+	t.Run("flag not found", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"defaultValue":"fallback"}`))
+		req.Header.Set(SdkIdHeader, "test")
+		srv := newServer(t, config.OFREPConfig{Enabled: true})
+		req.SetPathValue("key", "non-existing")
+		srv.Eval(res, req)
+
+		assert.Equal(t, 200, res.Code)
+		assert.Contains(t, res.Body.String(), `{"key":"non-existing","reason":"ERROR","variant":"","value":"fallback","errorCode":"FLAG_NOT_FOUND","errorDetails":`)
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"defaultValue":5}`))
+		req.Header.Set(SdkIdHeader, "test")
+		srv := newServer(t, config.OFREPConfig{Enabled: true})
+		req.SetPathValue("key", "flag")
+		srv.Eval(res, req)
+
+		assert.Equal(t, 200, res.Code)
+		assert.Contains(t, res.Body.String(), `{"key":"flag","reason":"ERROR","variant":"","value":5,"errorCode":"TYPE_MISMATCH","errorDetails":`)
+	})
+	t.Run("invalid state", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"defaultValue":false}`))
+		req.Header.Set(SdkIdHeader, "test")
+		srv := newErrorServer(t, config.OFREPConfig{Enabled: true})
+		req.SetPathValue("key", "flag")
+		srv.Eval(res, req)
+
+		assert.Equal(t, 200, res.Code)
+		assert.Equal(t, `{"key":"flag","reason":"ERROR","variant":"","value":false,"errorCode":"GENERAL","errorDetails":"requested SDK is in an invalid state; please check the logs for more details"}`, res.Body.String())
+	})
+	t.Run("invalid default", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"defaultValue":["a"]}`))
+		req.Header.Set(SdkIdHeader, "test")
+		srv := newServer(t, config.OFREPConfig{Enabled: true})
+		req.SetPathValue("key", "flag")
+		srv.Eval(res, req)
+
+		assert.Equal(t, 400, res.Code)
+		assert.Equal(t, `{"key":"flag","errorCode":"GENERAL","errorDetails":"'defaultValue' must be a boolean, number or string"}`, res.Body.String())
+	})
+}
+
 func TestOFREP_EvalAll(t *testing.T) {
 	t.Run("online", func(t *testing.T) {
 		res := httptest.NewRecorder()
@@ -2,6 +2,7 @@ package web
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/status"
@@ -9,6 +10,7 @@ import (
 	"github.com/configcat/configcat-proxy/internal/utils"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/configcat/configcat-proxy/sdk"
+	"github.com/configcat/configcat-proxy/stream"
 	"github.com/configcat/configcat-proxy/web/api"
 	"github.com/configcat/configcat-proxy/web/cdnproxy"
 	"github.com/configcat/configcat-proxy/web/mware"
@@ -50,7 +52,7 @@ func NewRouter(sdkRegistrar sdk.Registrar, telemetryReporter telemetry.Reporter,
 		r.setupOFREPRoutes(&conf.OFREP, sdkRegistrar, httpLog)
 	}
 	if conf.Status.Enabled {
-		r.setupStatusRoutes(reporter, httpLog)
+		r.setupStatusRoutes(&conf.Status, reporter, httpLog)
 	}
 	return r
 }
@@ -63,6 +65,13 @@ func (s *HttpRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	s.router.ServeHTTP(w, req)
 }
 
+func (s *HttpRouter) StreamServer() stream.Server {
+	if s.sseServer == nil {
+		return nil
+	}
+	return s.sseServer.StreamServer()
+}
+
 func (s *HttpRouter) Close() {
 	if s.sseServer != nil {
 		s.sseServer.Close()
@@ -78,14 +87,23 @@ func (s *HttpRouter) setupSSERoutes(conf *config.SseConfig, sdkRegistrar sdk.Reg
 		{path: "/sse/eval/k/{data}", handler: http.HandlerFunc(s.sseServer.SingleFlag), method: http.MethodGet},
 		{path: "/sse/eval-all/k/{data}", handler: http.HandlerFunc(s.sseServer.AllFlags), method: http.MethodGet},
 	}
+	// the config stream exposes the whole config JSON, so it's only exposed behind authentication
+	if len(conf.AuthHeaders) > 0 {
+		endpoints = append(endpoints,
+			endpoint{path: "/sse/{sdkId}/config", handler: http.HandlerFunc(s.sseServer.ConfigJson), method: http.MethodGet, authHeaders: conf.AuthHeaders},
+		)
+	}
 	for _, endpoint := range endpoints {
+		if len(endpoint.authHeaders) > 0 {
+			endpoint.handler = mware.HeaderAuth(endpoint.authHeaders, l, endpoint.handler)
+		}
 		endpoint.handler = mware.AutoOptions(endpoint.handler)
 		if len(conf.Headers) > 0 {
 			endpoint.handler = mware.ExtraHeaders(conf.Headers, endpoint.handler)
 		}
 		if conf.CORS.Enabled {
 			endpoint.handler = mware.CORS([]string{endpoint.method, http.MethodOptions}, conf.CORS.AllowedOrigins,
-				utils.KeysOfMap(conf.Headers), nil, &conf.CORS.AllowedOriginsRegex, endpoint.handler)
+				utils.KeysOfMap(conf.Headers), utils.KeysOfMap(endpoint.authHeaders), &conf.CORS.AllowedOriginsRegex, endpoint.handler)
 		}
 		if l.Level() == log.Debug {
 			endpoint.handler = mware.DebugLog(l, endpoint.handler)
@@ -138,18 +156,19 @@ func (s *HttpRouter) setupCDNProxyRoutes(conf *config.CdnProxyConfig, sdkRegistr
 	l.Reportf("CDN proxy enabled, accepting requests on path: %s", path)
 }
 
-func (s *HttpRouter) setupStatusRoutes(reporter status.Reporter, l log.Logger) {
+func (s *HttpRouter) setupStatusRoutes(conf *config.StatusConfig, reporter status.Reporter, l log.Logger) {
 	path := "/status"
-	handler := mware.AutoOptions(mware.GZip(reporter.HttpHandler()))
+	handler := mware.SecureStatus(conf, l, mware.GZip(reporter.HttpHandler(conf)))
 	s.router.HandleFunc(addHttpMethod(path, http.MethodGet), handler)
 	s.router.HandleFunc(addHttpMethod(path, http.MethodOptions), handler)
 	l.Reportf("status enabled, accepting requests on path: %s", path)
 }
 
 type endpoint struct {
-	handler http.HandlerFunc
-	method  string
-	path    string
+	handler     http.HandlerFunc
+	method      string
+	path        string
+	authHeaders map[string]string
 }
 
 func (s *HttpRouter) setupAPIRoutes(conf *config.ApiConfig, sdkRegistrar sdk.Registrar, l log.Logger) {
@@ -165,23 +184,42 @@ func (s *HttpRouter) setupAPIRoutes(conf *config.ApiConfig, sdkRegistrar sdk.Reg
 		{path: "/api/refresh", handler: http.HandlerFunc(s.apiServer.Refresh), method: http.MethodPost},
 		{path: "/api/icanhascoffee", handler: http.HandlerFunc(s.apiServer.ICanHasCoffee), method: http.MethodGet},
 	}
+	// the override endpoints change evaluation results, so they are only exposed behind their own authentication
+	if overrideAuth := conf.Overrides.AuthHeaders; len(overrideAuth) > 0 {
+		endpoints = append(endpoints,
+			endpoint{path: "/api/{sdkId}/overrides", handler: mware.GZip(s.apiServer.Overrides), method: http.MethodGet, authHeaders: overrideAuth},
+			endpoint{path: "/api/{sdkId}/overrides/{key}", handler: http.HandlerFunc(s.apiServer.SetOverride), method: http.MethodPut, authHeaders: overrideAuth},
+			endpoint{path: "/api/{sdkId}/overrides/{key}", handler: http.HandlerFunc(s.apiServer.RemoveOverride), method: http.MethodDelete, authHeaders: overrideAuth},
+		)
+	} else {
+		l.Warnf("no API override auth headers are configured, the flag override endpoints are disabled")
+	}
+	methodsByPath := make(map[string][]string, len(endpoints))
 	for _, endpoint := range endpoints {
-		if len(conf.AuthHeaders) > 0 {
-			endpoint.handler = mware.HeaderAuth(conf.AuthHeaders, l, endpoint.handler)
+		methodsByPath[endpoint.path] = append(methodsByPath[endpoint.path], endpoint.method)
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.authHeaders == nil {
+			endpoint.authHeaders = conf.AuthHeaders
+		}
+		if len(endpoint.authHeaders) > 0 {
+			endpoint.handler = mware.HeaderAuth(endpoint.authHeaders, l, endpoint.handler)
 		}
 		endpoint.handler = mware.AutoOptions(endpoint.handler)
 		if len(conf.Headers) > 0 {
 			endpoint.handler = mware.ExtraHeaders(conf.Headers, endpoint.handler)
 		}
 		if conf.CORS.Enabled {
-			endpoint.handler = mware.CORS([]string{endpoint.method, http.MethodOptions}, conf.CORS.AllowedOrigins,
-				utils.KeysOfMap(conf.Headers), utils.KeysOfMap(conf.AuthHeaders), &conf.CORS.AllowedOriginsRegex, endpoint.handler)
+			endpoint.handler = mware.CORS(slices.Concat(methodsByPath[endpoint.path], []string{http.MethodOptions}), conf.CORS.AllowedOrigins,
+				utils.KeysOfMap(conf.Headers), utils.KeysOfMap(endpoint.authHeaders), &conf.CORS.AllowedOriginsRegex, endpoint.handler)
 		}
 		if l.Level() == log.Debug {
 			endpoint.handler = mware.DebugLog(l, endpoint.handler)
 		}
 		s.router.HandleFunc(addHttpMethod(endpoint.path, endpoint.method), s.telemetryReporter.InstrumentHttp(endpoint.path, endpoint.method, endpoint.handler))
-		s.router.HandleFunc(addHttpMethod(endpoint.path, http.MethodOptions), s.telemetryReporter.InstrumentHttp(endpoint.path, http.MethodOptions, endpoint.handler))
+		if methodsByPath[endpoint.path][0] == endpoint.method {
+			s.router.HandleFunc(addHttpMethod(endpoint.path, http.MethodOptions), s.telemetryReporter.InstrumentHttp(endpoint.path, http.MethodOptions, endpoint.handler))
+		}
 	}
 	l.Reportf("API enabled, accepting requests on path: /api/*")
 }
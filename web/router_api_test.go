@@ -479,6 +479,59 @@ func TestAPI_Refresh_Headers(t *testing.T) {
 	})
 }
 
+func TestAPI_Overrides(t *testing.T) {
+	router, _ := newAPIRouter(t, config.ApiConfig{Enabled: true, CORS: config.CORSConfig{Enabled: true}, AuthHeaders: map[string]string{"X-AUTH": "key"},
+		Overrides: config.ApiOverridesConfig{AuthHeaders: map[string]string{"X-OVERRIDES-AUTH": "admin-key"}}})
+	srv := httptest.NewServer(router)
+	path := fmt.Sprintf("%s/api/test/overrides/flag", srv.URL)
+
+	t.Run("options cors", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodOptions, path, http.NoBody)
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "PUT,DELETE,OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
+	})
+	t.Run("missing auth", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(`{"value":false,"ttl":60}`))
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+	t.Run("eval auth is not accepted", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(`{"value":false,"ttl":60}`))
+		req.Header.Set("X-AUTH", "key")
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+	t.Run("set, list, remove", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, path, strings.NewReader(`{"value":false,"ttl":60}`))
+		req.Header.Set("X-OVERRIDES-AUTH", "admin-key")
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		req, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/test/overrides", srv.URL), http.NoBody)
+		req.Header.Set("X-OVERRIDES-AUTH", "admin-key")
+		resp, _ = http.DefaultClient.Do(req)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), `"flag":{"value":false`)
+
+		req, _ = http.NewRequest(http.MethodDelete, path, http.NoBody)
+		req.Header.Set("X-OVERRIDES-AUTH", "admin-key")
+		resp, _ = http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func TestAPI_Overrides_Without_Auth(t *testing.T) {
+	router, _ := newAPIRouter(t, config.ApiConfig{Enabled: true, AuthHeaders: map[string]string{"X-AUTH": "key"}})
+	srv := httptest.NewServer(router)
+
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/test/overrides/flag", srv.URL), strings.NewReader(`{"value":false,"ttl":60}`))
+	req.Header.Set("X-AUTH", "key")
+	resp, _ := http.DefaultClient.Do(req)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 func newAPIRouter(t *testing.T, conf config.ApiConfig) (*HttpRouter, string) {
 	reg, _, k := sdk.NewTestRegistrarT(t)
 	return NewRouter(reg, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), &config.HttpConfig{Api: conf}, &config.ProfileConfig{}, log.NewNullLogger()), k
@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/internal/testutils"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/configcat/configcat-proxy/sdk"
 	"github.com/stretchr/testify/assert"
@@ -153,6 +155,65 @@ func TestSSE_EvalAllFlags_Not_Allowed_Methods(t *testing.T) {
 	}
 }
 
+func TestSSE_ConfigJson_Chained(t *testing.T) {
+	router, _ := newSSERouter(t, config.SseConfig{Enabled: true, AuthHeaders: map[string]string{"X-SSE-KEY": "secret"}})
+	defer router.Close()
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	ctx := sdk.NewTestSdkContext(&config.SDKConfig{Key: "follower", Offline: config.OfflineConfig{Enabled: true, Upstream: config.UpstreamConfig{Url: srv.URL, SdkId: "test", Headers: map[string]string{"X-SSE-KEY": "secret"}}}}, nil)
+	ctx.SdkId = "follower"
+	ctx.StatusReporter.RegisterSdk(ctx.SdkId, ctx.SDKConf)
+	follower := sdk.NewClient(ctx, log.NewNullLogger())
+	defer follower.Close()
+
+	sub := make(chan struct{})
+	follower.Subscribe(sub)
+	testutils.WithTimeout(2*time.Second, func() {
+		<-sub
+	})
+	assert.True(t, follower.IsInValidState())
+	assert.True(t, follower.Eval("flag", nil).Value.(bool))
+	assert.Equal(t, status.UpstreamSrc, ctx.StatusReporter.GetStatus().SDKs["follower"].Source.Type)
+}
+
+func TestSSE_ConfigJson_Auth(t *testing.T) {
+	t.Run("no auth headers configured", func(t *testing.T) {
+		router, _ := newSSERouter(t, config.SseConfig{Enabled: true})
+		defer router.Close()
+		srv := httptest.NewServer(router)
+		defer srv.Close()
+
+		resp, err := http.Get(fmt.Sprintf("%s/sse/test/config", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+	t.Run("missing auth header", func(t *testing.T) {
+		router, _ := newSSERouter(t, config.SseConfig{Enabled: true, AuthHeaders: map[string]string{"X-SSE-KEY": "secret"}})
+		defer router.Close()
+		srv := httptest.NewServer(router)
+		defer srv.Close()
+
+		resp, err := http.Get(fmt.Sprintf("%s/sse/test/config", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestSSE_ConfigJson_Not_Allowed_Methods(t *testing.T) {
+	router, _ := newSSERouter(t, config.SseConfig{Enabled: true, AuthHeaders: map[string]string{"X-SSE-KEY": "secret"}})
+	defer router.Close()
+	srv := httptest.NewServer(router)
+
+	for _, method := range []string{http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch} {
+		t.Run(method, func(t *testing.T) {
+			req, _ := http.NewRequest(method, fmt.Sprintf("%s/sse/test/config", srv.URL), http.NoBody)
+			resp, _ := http.DefaultClient.Do(req)
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		})
+	}
+}
+
 func newSSERouter(t *testing.T, conf config.SseConfig) (*HttpRouter, string) {
 	reg, _, k := sdk.NewTestRegistrarT(t)
 	return NewRouter(reg, telemetry.NewEmptyReporter(), status.NewEmptyReporter(), &config.HttpConfig{Sse: conf}, &config.ProfileConfig{}, log.NewNullLogger()), k
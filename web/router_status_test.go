@@ -11,9 +11,11 @@ import (
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/status"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
+	"github.com/configcat/configcat-proxy/internal/utils"
 	"github.com/configcat/configcat-proxy/log"
 	"github.com/configcat/configcat-proxy/sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStatus_Options(t *testing.T) {
@@ -71,11 +73,92 @@ func TestStatus_Not_Allowed_Methods(t *testing.T) {
 	})
 }
 
+func TestStatus_SdkKeyMasking(t *testing.T) {
+	t.Run("masked", func(t *testing.T) {
+		router, key := newStatusRouterWithConfig(t, config.StatusConfig{Enabled: true, MaskSdkKeys: true})
+		srv := httptest.NewServer(router)
+		stat := getStatus(t, srv.URL)
+		assert.Equal(t, utils.Obfuscate(key, 5), stat.SDKs["test"].SdkKey)
+	})
+	t.Run("unmasked", func(t *testing.T) {
+		router, key := newStatusRouterWithConfig(t, config.StatusConfig{Enabled: true})
+		srv := httptest.NewServer(router)
+		stat := getStatus(t, srv.URL)
+		assert.Equal(t, key, stat.SDKs["test"].SdkKey)
+	})
+}
+
+func TestStatus_Auth(t *testing.T) {
+	t.Run("headers", func(t *testing.T) {
+		router, _ := newStatusRouterWithConfig(t, config.StatusConfig{Enabled: true, AuthHeaders: map[string]string{"X-Status-Auth": "secret"}})
+		srv := httptest.NewServer(router)
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/status", srv.URL), http.NoBody)
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		req, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/status", srv.URL), http.NoBody)
+		req.Header.Set("X-Status-Auth", "secret")
+		resp, _ = http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+	t.Run("basic", func(t *testing.T) {
+		router, _ := newStatusRouterWithConfig(t, config.StatusConfig{Enabled: true, Auth: config.AuthConfig{User: "user", Password: "pass"}})
+		srv := httptest.NewServer(router)
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/status", srv.URL), http.NoBody)
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		req, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/status", srv.URL), http.NoBody)
+		req.SetBasicAuth("user", "pass")
+		resp, _ = http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+	t.Run("options skips auth", func(t *testing.T) {
+		router, _ := newStatusRouterWithConfig(t, config.StatusConfig{Enabled: true, AuthHeaders: map[string]string{"X-Status-Auth": "secret"}})
+		srv := httptest.NewServer(router)
+		req, _ := http.NewRequest(http.MethodOptions, fmt.Sprintf("%s/status", srv.URL), http.NoBody)
+		resp, _ := http.DefaultClient.Do(req)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func TestStatus_CORS(t *testing.T) {
+	router, _ := newStatusRouterWithConfig(t, config.StatusConfig{
+		Enabled:     true,
+		AuthHeaders: map[string]string{"X-Status-Auth": "secret"},
+		CORS:        config.CORSConfig{Enabled: true, AllowedOrigins: []string{"https://example.com"}},
+	})
+	srv := httptest.NewServer(router)
+	req, _ := http.NewRequest(http.MethodOptions, fmt.Sprintf("%s/status", srv.URL), http.NoBody)
+	req.Header.Set("Origin", "https://example.com")
+	resp, _ := http.DefaultClient.Do(req)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET,OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "X-Status-Auth")
+}
+
 func newStatusRouter(t *testing.T) *HttpRouter {
+	router, _ := newStatusRouterWithConfig(t, config.StatusConfig{Enabled: true})
+	return router
+}
+
+func newStatusRouterWithConfig(t *testing.T, conf config.StatusConfig) (*HttpRouter, string) {
 	reporter := status.NewEmptyReporter()
-	reg, _, _ := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
+	reg, _, key := sdk.NewTestRegistrarTWithStatusReporter(t, reporter)
 	for _, c := range reg.GetAll() {
 		<-c.Ready()
 	}
-	return NewRouter(reg, telemetry.NewEmptyReporter(), reporter, &config.HttpConfig{Status: config.StatusConfig{Enabled: true}}, &config.ProfileConfig{}, log.NewNullLogger())
+	return NewRouter(reg, telemetry.NewEmptyReporter(), reporter, &config.HttpConfig{Status: conf}, &config.ProfileConfig{}, log.NewNullLogger()), key
+}
+
+func getStatus(t *testing.T, url string) status.Status {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/status", url), http.NoBody)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	var stat status.Status
+	require.NoError(t, json.Unmarshal(body, &stat))
+	return stat
 }
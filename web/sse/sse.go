@@ -3,6 +3,7 @@ package sse
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/configcat/configcat-proxy/config"
 	"github.com/configcat/configcat-proxy/diag/telemetry"
@@ -15,6 +16,9 @@ import (
 
 const streamDataName = "data"
 
+// keepAliveMsg is an SSE comment line, clients ignore it, but it lets them detect a stalled connection.
+var keepAliveMsg = []byte(": keep-alive\n\n")
+
 type Server struct {
 	streamServer stream.Server
 	config       *config.SseConfig
@@ -83,6 +87,29 @@ func (s *Server) AllFlags(w http.ResponseWriter, r *http.Request) {
 	s.listenAndRespond(str, evalReq.User, stream.AllFlagsDiscriminator, w, r, flusher)
 }
 
+func (s *Server) ConfigJson(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusNotImplemented)
+		return
+	}
+
+	sdkId := r.PathValue("sdkId")
+	if prepareResponse(w, r, false) == nil {
+		return
+	}
+
+	str := s.getStream(w, sdkId, "")
+	if str == nil {
+		return
+	}
+	s.listenAndRespond(str, nil, stream.ConfigJsonDiscriminator, w, r, flusher)
+}
+
+func (s *Server) StreamServer() stream.Server {
+	return s.streamServer
+}
+
 func (s *Server) Close() {
 	close(s.stop)
 	s.streamServer.Close()
@@ -94,6 +121,13 @@ func (s *Server) listenAndRespond(str stream.Stream, attrs model.UserAttrs, key
 	w.WriteHeader(http.StatusOK)
 	flusher.Flush()
 
+	var heartBeat <-chan time.Time
+	if s.config.HeartBeatInterval > 0 {
+		ticker := time.NewTicker(time.Duration(s.config.HeartBeatInterval) * time.Second)
+		defer ticker.Stop()
+		heartBeat = ticker.C
+	}
+
 	for {
 		select {
 		case payload := <-conn.Receive():
@@ -108,6 +142,12 @@ func (s *Server) listenAndRespond(str stream.Stream, attrs model.UserAttrs, key
 			} else {
 				s.logger.Errorf("%s", e)
 			}
+		case <-heartBeat:
+			if _, e := w.Write(keepAliveMsg); e == nil {
+				flusher.Flush()
+			} else {
+				s.logger.Errorf("%s", e)
+			}
 		case <-r.Context().Done():
 			str.CloseConnection(conn, key)
 			return
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,6 +65,53 @@ func TestSSE_Get_With_Sdk_Key(t *testing.T) {
 	assert.Equal(t, "keep-alive", res.Header().Get("Connection"))
 }
 
+func TestSSE_ConfigJson(t *testing.T) {
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	srv := newServer(t, &config.SseConfig{Enabled: true})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	testutils.AddSdkIdContextParam(req)
+	req = req.WithContext(ctx)
+	srv.ConfigJson(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.True(t, strings.HasPrefix(res.Body.String(), `data: {"etag":"`))
+	assert.Contains(t, res.Body.String(), `"config":{`)
+	assert.True(t, strings.HasSuffix(res.Body.String(), "}\n\n"))
+	assert.Equal(t, "text/event-stream", res.Header().Get("Content-Type"))
+}
+
+func TestSSE_HeartBeat(t *testing.T) {
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	srv := newServer(t, &config.SseConfig{Enabled: true, HeartBeatInterval: 1})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 1500*time.Millisecond)
+	defer cancel()
+	testutils.AddSdkIdContextParam(req)
+	req = req.WithContext(ctx)
+	srv.ConfigJson(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.True(t, strings.HasPrefix(res.Body.String(), `data: {"etag":"`))
+	assert.True(t, strings.HasSuffix(res.Body.String(), "}\n\n: keep-alive\n\n"))
+}
+
+func TestSSE_ConfigJson_SdkNotFound(t *testing.T) {
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	srv := newServer(t, &config.SseConfig{Enabled: true})
+	testutils.AddSdkIdContextParamWithSdkId(req, "non-existing")
+	srv.ConfigJson(res, req)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
 func TestSSE_Get_SdkRemoved(t *testing.T) {
 	res := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)